@@ -0,0 +1,59 @@
+// Program codegen generates standalone Go source code from a saved network.
+// The encoding of the network is detected automatically, so any format jnet
+// can write is accepted.
+//
+// Usage:
+//
+//	codegen -in model.jnet -out model.go -package model -func Predict
+//
+// The network is read from stdin if -in is omitted, and the source is written
+// to stdout if -out is omitted.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Insulince/jnet/pkg/codegen"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+func main() {
+	in := flag.String("in", "", "path of the network to read, defaults to stdin")
+	out := flag.String("out", "", "path of the go file to write, defaults to stdout")
+	pkg := flag.String("package", "model", "name of the generated package")
+	fn := flag.String("func", "Predict", "name of the generated prediction function")
+	flag.Parse()
+
+	var r io.Reader = os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	nw, _, err := network.Load(r)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	src, err := codegen.Generate(nw, codegen.Options{Package: *pkg, FuncName: *fn})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if *out == "" {
+		if _, err := os.Stdout.Write(src); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalln(err)
+	}
+}
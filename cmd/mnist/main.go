@@ -0,0 +1,134 @@
+// Program mnist trains a network to recognize handwritten digits from the MNIST
+// dataset, which is the scaled up version of the seven segment display example
+// found in cmd/simple, and then evaluates it against the test set.
+//
+// The dataset is not included. Download the four files from
+// http://yann.lecun.com/exdb/mnist/ (or Fashion-MNIST, which uses the same
+// format) into a directory and point -dir at it. They can be left gzipped.
+//
+// Usage:
+//
+//	mnist -dir ./mnist -timeout 5m -out mnist.jnet
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/dataset"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+func init() {
+	rand.Seed(time.Now().Unix())
+}
+
+var outputLabels = []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+
+func main() {
+	dir := flag.String("dir", ".", "directory holding the mnist idx files")
+	timeout := flag.Duration("timeout", 5*time.Minute, "how long to train for")
+	out := flag.String("out", "", "path to save the trained network to, if any")
+	flag.Parse()
+
+	trainingData, err := load(*dir, "train")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	testData, err := load(*dir, "t10k")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("loaded %v training and %v test images\n", len(trainingData), len(testData))
+
+	nw, err := network.From(network.Spec{
+		NeuronMap:              []int{len(trainingData[0].Data), 64, 32, len(outputLabels)},
+		OutputLabels:           outputLabels,
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	trainConfig := trainer.Configuration{
+		LearningRate:      0.1,
+		MiniBatchSize:     32,
+		MaxIterations:     2500000,
+		AverageLossCutoff: 0.1,
+		Timeout:           *timeout,
+	}
+
+	t := trainer.New(trainConfig, trainingData, os.Stdout)
+
+	err = t.Train(nw)
+	if err != nil {
+		if err != trainer.ErrTimedOut {
+			log.Fatalln(err)
+		}
+		log.Println(err.Error(), "continuing to evaluation from current network state")
+	}
+
+	correct := 0
+	for _, td := range testData {
+		prediction, _, err := nw.Predict(td.Data)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if td.Truth[indexOf(outputLabels, prediction)] == 1 {
+			correct++
+		}
+	}
+	fmt.Printf("Accuracy: %v/%v (%.2f%%)\n", correct, len(testData), 100*float64(correct)/float64(len(testData)))
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer f.Close()
+		if err := network.NewProtoTranslator(network.WithCompression()).Encode(f, nw); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+// load reads the images and labels of the given set, either "train" or "t10k",
+// from dir, preferring the uncompressed files if both are present.
+func load(dir, set string) (trainer.Data, error) {
+	images, err := open(filepath.Join(dir, set+"-images-idx3-ubyte"))
+	if err != nil {
+		return nil, err
+	}
+	defer images.Close()
+	labels, err := open(filepath.Join(dir, set+"-labels-idx1-ubyte"))
+	if err != nil {
+		return nil, err
+	}
+	defer labels.Close()
+
+	return dataset.ReadMNIST(images, labels, outputLabels)
+}
+
+func open(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return os.Open(path + ".gz")
+	}
+	return f, err
+}
+
+func indexOf(ss []string, s string) int {
+	for i := range ss {
+		if ss[i] == s {
+			return i
+		}
+	}
+	return -1
+}
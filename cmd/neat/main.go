@@ -0,0 +1,176 @@
+// Program neat evolves the topology and weights of networks via the neat
+// package to solve one of two classic tasks.
+//
+// The xor task evolves a network computing the exclusive or of its two inputs,
+// which requires at least one hidden neuron, so the minimal networks NEAT starts
+// from cannot solve it until evolution adds structure.
+//
+// The pole task evolves a controller balancing a pole hinged atop a cart, by
+// pushing the cart left or right, without letting the pole fall or the cart run
+// off its track. The cart and pole are simulated here in pure Go, using the
+// equations of motion from Barto, Sutton and Anderson (1983).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"runtime"
+	"time"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/neat"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+func init() {
+	rand.Seed(time.Now().Unix())
+}
+
+func main() {
+	task := flag.String("task", "xor", "task to evolve a network for, xor or pole")
+	generations := flag.Int("generations", 300, "maximum number of generations to evolve")
+	flag.Parse()
+
+	c := neat.DefaultConfiguration()
+	c.Generations = *generations
+	c.Workers = runtime.NumCPU()
+
+	var (
+		spec    network.Spec
+		fitness neat.FitnessFunc
+	)
+	switch *task {
+	case "xor":
+		spec = network.Spec{
+			InputLabels:            []string{"a", "b"},
+			OutputLabels:           []string{"xor"},
+			ActivationFunctionName: activationfunction.NameSigmoid,
+		}
+		fitness = xor
+		c.FitnessThreshold = 3.9
+	case "pole":
+		spec = network.Spec{
+			InputLabels:            []string{"position", "velocity", "angle", "angular velocity"},
+			OutputLabels:           []string{"push"},
+			ActivationFunctionName: activationfunction.NameSigmoid,
+		}
+		fitness = balance
+		c.FitnessThreshold = poleSteps
+	default:
+		log.Fatalf("unknown task %q, must be xor or pole", *task)
+	}
+
+	e := neat.New(c, spec, fitness, nil)
+	nw, err := e.Evolve()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Printf("Best genome has fitness %v, with %v nodes and %v connection genes in %v species, enabled connections:\n", e.Best.Fitness, len(e.Best.Nodes), len(e.Best.Connections), len(e.Species))
+	for _, cg := range e.Best.Connections {
+		if cg.Enabled {
+			fmt.Printf("  %v -> %v: %.4f\n", cg.In, cg.Out, cg.Weight)
+		}
+	}
+
+	if *task == "xor" {
+		for _, in := range [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+			fmt.Printf("%v xor %v = %.4f\n", in[0], in[1], nw.MustPredictVector(in)[0])
+		}
+	}
+}
+
+// xor scores a network by how closely it computes a xor b, as 4 less its sum of
+// squared errors, so a perfect network scores 4.
+func xor(nw network.Network) (float64, error) {
+	fitness := 4.0
+	for _, c := range [][3]float64{{0, 0, 0}, {0, 1, 1}, {1, 0, 1}, {1, 1, 0}} {
+		vs, err := nw.PredictVector([]float64{c[0], c[1]})
+		if err != nil {
+			return 0, err
+		}
+		fitness -= (vs[0] - c[2]) * (vs[0] - c[2])
+	}
+	return fitness, nil
+}
+
+const (
+	gravity    = 9.8
+	cartMass   = 1.0
+	poleMass   = 0.1
+	totalMass  = cartMass + poleMass
+	halfLength = 0.5  // Half the length of the pole, in meters.
+	force      = 10.0 // Force of every push, in newtons.
+	timeStep   = 0.02 // Seconds simulated per step.
+
+	trackLimit = 2.4                // Meters the cart may move from the center.
+	angleLimit = 12 * math.Pi / 180 // Radians the pole may lean from upright.
+
+	// poleSteps is the number of steps a pole must be balanced for, from every
+	// starting state, to solve the task.
+	poleSteps = 10000
+	// poleStarts is the number of starting states every network is tested
+	// from.
+	poleStarts = 4
+)
+
+// cart is the state of the cart and pole.
+type cart struct {
+	x, dx, theta, dtheta float64
+}
+
+// step advances the simulation by one time step while pushing the cart right,
+// or left if right is false.
+func (c *cart) step(right bool) {
+	f := force
+	if !right {
+		f = -force
+	}
+	sin, cos := math.Sin(c.theta), math.Cos(c.theta)
+
+	temp := (f + poleMass*halfLength*c.dtheta*c.dtheta*sin) / totalMass
+	ddtheta := (gravity*sin - cos*temp) / (halfLength * (4.0/3.0 - poleMass*cos*cos/totalMass))
+	ddx := temp - poleMass*halfLength*ddtheta*cos/totalMass
+
+	c.x += timeStep * c.dx
+	c.dx += timeStep * ddx
+	c.theta += timeStep * c.dtheta
+	c.dtheta += timeStep * ddtheta
+}
+
+func (c cart) failed() bool {
+	return math.Abs(c.x) > trackLimit || math.Abs(c.theta) > angleLimit
+}
+
+// balance scores a network by the average number of steps it balances the pole
+// for, up to poleSteps, from a fixed set of starting states. The network is
+// given the state of the cart scaled to roughly [-1, 1], and pushes right when
+// its output is positive.
+func balance(nw network.Network) (float64, error) {
+	// NOTE: A source local to this call, seeded the same every time, keeps the
+	// fitness deterministic and safe to evaluate concurrently.
+	r := rand.New(rand.NewSource(1))
+
+	total := 0
+	for s := 0; s < poleStarts; s++ {
+		c := cart{
+			x:      (r.Float64()*2 - 1) * trackLimit / 2,
+			dx:     r.Float64()*2 - 1,
+			theta:  (r.Float64()*2 - 1) * angleLimit / 2,
+			dtheta: r.Float64() - 0.5,
+		}
+		steps := 0
+		for ; steps < poleSteps && !c.failed(); steps++ {
+			vs, err := nw.PredictVector([]float64{c.x / trackLimit, c.dx / 2, c.theta / angleLimit, c.dtheta / 2})
+			if err != nil {
+				return 0, err
+			}
+			c.step(vs[0] > 0)
+		}
+		total += steps
+	}
+	return float64(total) / poleStarts, nil
+}
@@ -1,6 +1,6 @@
 module github.com/Insulince/jnet
 
-go 1.16
+go 1.17
 
 require (
 	github.com/TheDemx27/calculus v0.0.0-20170802071712-c9ac6b2b2cc0
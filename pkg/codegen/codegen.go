@@ -0,0 +1,299 @@
+// Package codegen generates standalone Go source code from a trained network.
+// The generated code depends on nothing but the standard library, so a model
+// can be embedded in a service without importing jnet at all.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// Options configures the generated source code.
+type Options struct {
+	// Package is the name of the generated package. Defaults to "model".
+	Package string
+	// FuncName is the name of the generated prediction function. Defaults to
+	// "Predict". It must not clash with the other generated declarations.
+	FuncName string
+}
+
+// activationStatements maps every activation function to a format string for
+// the Go statements which assign it, computed for a float64 named x, to the
+// destination given as the format's only operand. Each is written identically
+// to the implementation in package activationfunction, so that the generated
+// code produces the same results as the network it was generated from.
+var activationStatements = map[activationfunction.Name]string{
+	activationfunction.NameNoop:    "_ = x\n%v = 0",
+	activationfunction.NameSigmoid: "%v = 2/(1+math.Pow(math.E, -x)) - 1",
+	activationfunction.NameTanh:    "%v = math.Tanh(x)",
+	activationfunction.NameRelu:    "if x > 0 {\n%[1]v = x\n} else {\n%[1]v = 0\n}",
+	activationfunction.NameLinear:  "%v = x",
+}
+
+// Generate returns the source code of a single, gofmt-ed Go file which computes
+// the same predictions as nw.
+//
+// The file declares the parameters of nw as package level arrays, and a
+// function, named by opts.FuncName, of the form
+//
+//	func Predict(input [N]float64) [M]float64
+//
+// where N and M are the number of input and output neurons of nw. It performs
+// a forward pass without allocating, with every activation function inlined.
+// The labels of the input and output neurons are declared as InputLabels and
+// OutputLabels.
+func Generate(nw network.Network, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "model"
+	}
+	if opts.FuncName == "" {
+		opts.FuncName = "Predict"
+	}
+	// NOTE: The blank identifier is an identifier, but not a valid package
+	// name.
+	if !token.IsIdentifier(opts.Package) || opts.Package == "_" {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+	if !token.IsIdentifier(opts.FuncName) {
+		return nil, fmt.Errorf("invalid function name %q", opts.FuncName)
+	}
+	if err := checkFuncName(opts.FuncName, opts.Package); err != nil {
+		return nil, err
+	}
+	if len(nw) < 2 {
+		return nil, errors.New("network must have at least 2 layers (for input and output layer)")
+	}
+	if !nw.IsFullyConnected() {
+		return nil, errors.New("only fully connected networks are supported, see network.Network.IsFullyConnected")
+	}
+	if nw.InputTransforms() != nil {
+		return nil, errors.New("networks with input transforms are not supported")
+	}
+
+	d := templateData{
+		Package:  opts.Package,
+		FuncName: opts.FuncName,
+		Inputs:   len(nw.FirstLayer()),
+		Outputs:  len(nw.LastLayer()),
+	}
+	spec := nw.Spec()
+	d.InputLabels = quoteAll(spec.InputLabels)
+	d.OutputLabels = quoteAll(spec.OutputLabels)
+
+	weights, biases := nw.ConnectionWeights(), nw.NeuronBiases()
+	afns := nw.NeuronActivationFunctions()
+	for li := 1; li < len(nw); li++ {
+		l := templateLayer{
+			Index:  li,
+			Prev:   fmt.Sprintf("l%v", li-1),
+			Size:   len(nw[li]),
+			Inputs: len(nw[li-1]),
+		}
+		if li == 1 {
+			l.Prev = "input"
+		}
+
+		var err error
+		if l.Weights, err = formatMatrix(weights[li]); err != nil {
+			return nil, errors.Wrapf(err, "layer %v weights", li)
+		}
+		if l.Biases, err = formatVector(biases[li]); err != nil {
+			return nil, errors.Wrapf(err, "layer %v biases", li)
+		}
+
+		dst := fmt.Sprintf("l%v[j]", li)
+		uniform := true
+		for _, afn := range afns[li] {
+			if _, found := activationStatements[afn]; !found {
+				return nil, fmt.Errorf("activation function %q of layer %v has no generated equivalent", afn, li)
+			}
+			uniform = uniform && afn == afns[li][0]
+		}
+		if uniform {
+			l.Activation = fmt.Sprintf(activationStatements[afns[li][0]], dst)
+		} else {
+			// NOTE: When neurons in a layer use different activation functions
+			// the loop switches on the index of the neuron to pick one.
+			for _, afn := range afns[li] {
+				l.Activations = append(l.Activations, fmt.Sprintf(activationStatements[afn], dst))
+			}
+		}
+
+		d.Layers = append(d.Layers, l)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, d); err != nil {
+		return nil, errors.Wrap(err, "executing template")
+	}
+
+	// NOTE: Only import math if it is actually used, since unused imports are
+	// a compile error.
+	src := b.String()
+	if strings.Contains(src, "math.") {
+		src = strings.Replace(src, "package "+d.Package+"\n", "package "+d.Package+"\n\nimport \"math\"\n", 1)
+	}
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "formatting generated source")
+	}
+	return formatted, nil
+}
+
+// MustGenerate calls Generate but panics if an error is encountered.
+func MustGenerate(nw network.Network, opts Options) []byte {
+	src, err := Generate(nw, opts)
+	if err != nil {
+		panic(errors.Wrap(err, "must generate"))
+	}
+	return src
+}
+
+// generatedNameRe matches the names of the package level variables declared
+// for the parameters of each layer.
+var generatedNameRe = regexp.MustCompile(`^layer[0-9]+(Weights|Biases)$`)
+
+// checkFuncName returns an error if a prediction function named name can't be
+// declared in the generated package pkg, as the name is reserved by Go or
+// clashes with one of the generated declarations or imports.
+func checkFuncName(name, pkg string) error {
+	switch {
+	case name == "_" || name == "init" || (name == "main" && pkg == "main"):
+		return fmt.Errorf("function name %q is reserved by go", name)
+	case types.Universe.Lookup(name) != nil:
+		return fmt.Errorf("function name %q would shadow a predeclared identifier", name)
+	case name == "InputLabels" || name == "OutputLabels" || name == "math" || generatedNameRe.MatchString(name):
+		return fmt.Errorf("function name %q clashes with a generated declaration", name)
+	}
+	return nil
+}
+
+type templateData struct {
+	Package      string
+	FuncName     string
+	Inputs       int
+	Outputs      int
+	InputLabels  []string
+	OutputLabels []string
+	Layers       []templateLayer
+}
+
+type templateLayer struct {
+	Index  int
+	Prev   string
+	Size   int
+	Inputs int
+	// Weights holds one formatted row of weights per neuron.
+	Weights []string
+	Biases  string
+	// Activation holds the statements applying the activation function shared
+	// by every neuron in the layer. If neurons use different activation
+	// functions it is empty and Activations holds the statements of each
+	// neuron instead.
+	Activation  string
+	Activations []string
+}
+
+// formatFloat formats v as a Go floating point literal which parses back into
+// exactly v.
+func formatFloat(v float64) (string, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", fmt.Errorf("non-finite value %v can't be represented in go source", v)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64), nil
+}
+
+func formatVector(vs []float64) (string, error) {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		s, err := formatFloat(v)
+		if err != nil {
+			return "", err
+		}
+		ss[i] = s
+	}
+	return strings.Join(ss, ", "), nil
+}
+
+func formatMatrix(m [][]float64) ([]string, error) {
+	rows := make([]string, len(m))
+	for i, vs := range m {
+		row, err := formatVector(vs)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+func quoteAll(ss []string) []string {
+	qs := make([]string, len(ss))
+	for i, s := range ss {
+		qs[i] = strconv.Quote(s)
+	}
+	return qs
+}
+
+var tmpl = template.Must(template.New("codegen").Parse(`// Code generated by jnet codegen. DO NOT EDIT.
+
+package {{.Package}}
+
+// InputLabels are the labels of the inputs to {{.FuncName}}, by index.
+var InputLabels = [{{.Inputs}}]string{ {{- range $i, $l := .InputLabels}}{{if $i}}, {{end}}{{$l}}{{end -}} }
+
+// OutputLabels are the labels of the outputs of {{.FuncName}}, by index.
+var OutputLabels = [{{.Outputs}}]string{ {{- range $i, $l := .OutputLabels}}{{if $i}}, {{end}}{{$l}}{{end -}} }
+
+{{range .Layers}}
+// layer{{.Index}}Weights[j][i] is the weight between neuron i of the previous
+// layer and neuron j of layer {{.Index}}.
+var layer{{.Index}}Weights = [{{.Size}}][{{.Inputs}}]float64{
+{{- range .Weights}}
+	{ {{- .}}},
+{{- end}}
+}
+
+var layer{{.Index}}Biases = [{{.Size}}]float64{ {{- .Biases -}} }
+{{end}}
+
+// {{.FuncName}} computes the outputs of the network for input without
+// allocating.
+func {{.FuncName}}(input [{{.Inputs}}]float64) [{{.Outputs}}]float64 {
+{{- range .Layers}}
+	var l{{.Index}} [{{.Size}}]float64
+	for j := range l{{.Index}} {
+		wSum := 0.0
+		for i, v := range {{.Prev}} {
+			wSum += v * layer{{.Index}}Weights[j][i]
+		}
+		x := wSum + layer{{.Index}}Biases[j]
+{{- if .Activation}}
+		{{.Activation}}
+{{- else}}
+		switch j {
+{{- range $j, $a := .Activations}}
+		case {{$j}}:
+			{{$a}}
+{{- end}}
+		}
+{{- end}}
+	}
+{{- end}}
+	return l{{len .Layers}}
+}
+`))
@@ -0,0 +1,200 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// typeCheck parses and type checks src, returning the type checked package.
+func typeCheck(t *testing.T, src []byte) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "model.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing generated source: %v\n%s", err, src)
+	}
+	for _, imp := range f.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); strings.Contains(p, ".") {
+			t.Fatalf("generated source imports non standard library package %v", p)
+		}
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("model", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatalf("type checking generated source: %v\n%s", err, src)
+	}
+	return pkg
+}
+
+func Test_Generate_TypeChecks(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 5, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	src := MustGenerate(nw, Options{Package: "mymodel", FuncName: "Infer"})
+	pkg := typeCheck(t, src)
+
+	if pkg.Name() != "mymodel" {
+		t.Fatalf("expected package mymodel, got %v", pkg.Name())
+	}
+	fn, ok := pkg.Scope().Lookup("Infer").(*types.Func)
+	if !ok {
+		t.Fatalf("expected function Infer to be declared")
+	}
+	if sig := fn.Type().String(); sig != "func(input [3]float64) [2]float64" {
+		t.Fatalf("unexpected signature %v", sig)
+	}
+	if labels := pkg.Scope().Lookup("OutputLabels"); labels == nil || labels.Type().String() != "[2]string" {
+		t.Fatalf("expected OutputLabels of type [2]string, got %v", labels)
+	}
+}
+
+func Test_Generate_MatchesPredict(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping running generated source in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not in PATH")
+	}
+
+	// The neurons of layer 2 use different activation functions, so that both
+	// forms of generated activation are run.
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 5, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetNeuronActivationFunctionsTo(activationfunction.NameRelu)
+	nw[2][0].MustSetActivationFunction(activationfunction.NameTanh)
+	nw[2][1].MustSetActivationFunction(activationfunction.NameLinear)
+	nw[2][3].MustSetActivationFunction(activationfunction.NameRelu)
+	inputs := [][]float64{
+		{0, 0, 0},
+		{1, -1, 0.5},
+		{-2.5, 3, 0.25},
+		{10, 10, -10},
+	}
+
+	src := MustGenerate(nw, Options{Package: "main"})
+	typeCheck(t, src)
+
+	var main strings.Builder
+	main.WriteString("package main\n\nimport \"fmt\"\n\nfunc main() {\n")
+	for _, input := range inputs {
+		fmt.Fprintf(&main, "\tfor _, v := range Predict([3]float64{%v, %v, %v}) {\n\t\tfmt.Printf(\"%%b\\n\", v)\n\t}\n", input[0], input[1], input[2])
+	}
+	main.WriteString("}\n")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(main.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module model\n\ngo 1.16\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("running generated source: %v\n%s", err, out)
+	}
+
+	got := strings.Fields(string(out))
+	var expected []string
+	for _, input := range inputs {
+		for _, v := range nw.MustPredictVector(input) {
+			// NOTE: %b prints the exact bits, so this checks the generated code
+			// produces identical results rather than merely close ones.
+			expected = append(expected, fmt.Sprintf("%b", v))
+		}
+	}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected outputs %v, got %v", expected, got)
+	}
+}
+
+func Test_Generate_NoMathImportWhenUnused(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{2, 2},
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameRelu,
+	})
+
+	src := MustGenerate(nw, Options{})
+	typeCheck(t, src)
+	if strings.Contains(string(src), `import "math"`) {
+		t.Fatalf("expected math not to be imported\n%s", src)
+	}
+}
+
+func Test_Generate_Errors(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{2, 2, 2},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	if _, err := Generate(nw, Options{Package: "not valid"}); err == nil {
+		t.Fatalf("expected error for invalid package name")
+	}
+	if _, err := Generate(nw, Options{Package: "_"}); err == nil {
+		t.Fatalf("expected error for blank package name")
+	}
+	if _, err := Generate(nw, Options{FuncName: "1Predict"}); err == nil {
+		t.Fatalf("expected error for invalid function name")
+	}
+	for _, name := range []string{"init", "_", "float64", "math", "InputLabels", "OutputLabels", "layer1Weights", "layer2Biases"} {
+		if _, err := Generate(nw, Options{FuncName: name}); err == nil {
+			t.Fatalf("expected error for function name %q", name)
+		}
+	}
+	if _, err := Generate(nw, Options{Package: "main", FuncName: "main"}); err == nil {
+		t.Fatalf("expected error for function main in package main")
+	}
+	if _, err := Generate(network.Network{nw[0]}, Options{}); err == nil {
+		t.Fatalf("expected error for network with a single layer")
+	}
+
+	nw[2][0].Connections[0].To = nw[0][1]
+	if _, err := Generate(nw, Options{}); err == nil {
+		t.Fatalf("expected error for network which is not fully connected")
+	}
+	nw[2][0].Connections[0].To = nw[1][0]
+
+	nw.MustSetInputTransforms([]network.InputTransform{{Source: 0}, {Source: 0, Steps: []network.TransformStep{{Kind: network.TransformLog, Offset: 1}}}})
+	if _, err := Generate(nw, Options{}); err == nil {
+		t.Fatalf("expected error for network with input transforms")
+	}
+	nw.MustSetInputTransforms(nil)
+
+	nw[2][1].SetBias(math.NaN())
+	if _, err := Generate(nw, Options{}); err == nil {
+		t.Fatalf("expected error for non-finite bias")
+	}
+}
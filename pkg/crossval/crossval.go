@@ -0,0 +1,270 @@
+// Package crossval estimates how well a network architecture and training
+// configuration generalize, by training a fresh network on each fold of some
+// data and evaluating it on the data held out of that fold.
+package crossval
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Method is a way of splitting data into folds.
+type Method int
+
+const (
+	// MethodKFold splits data via KFold.
+	MethodKFold Method = iota
+	// MethodStratifiedKFold splits data via StratifiedKFold.
+	MethodStratifiedKFold
+	// MethodLeaveOneOut splits data via LeaveOneOut, ignoring Options.Folds.
+	MethodLeaveOneOut
+)
+
+func (m Method) String() string {
+	switch m {
+	case MethodKFold:
+		return "k-fold"
+	case MethodStratifiedKFold:
+		return "stratified k-fold"
+	case MethodLeaveOneOut:
+		return "leave-one-out"
+	default:
+		return fmt.Sprintf("Method(%d)", int(m))
+	}
+}
+
+// SpecFunc returns the Spec of the network to train on a fold. It is called
+// once per fold, so it may vary labels or other details between folds, but
+// usually returns the same Spec every time.
+type SpecFunc func() network.Spec
+
+// Options configures a cross-validation Run.
+type Options struct {
+	// Method is how the data is split into folds.
+	Method Method
+	// Folds is the number of folds for MethodKFold and MethodStratifiedKFold.
+	Folds int
+	// Metrics are evaluated on the test data of every fold once its network is
+	// trained. At least one is required; see the metrics package.
+	Metrics []trainer.Metric
+	// Parallelism is the maximum number of folds trained at once. Values less
+	// than 1 are treated as 1.
+	Parallelism int
+	// Log receives the training logs of every fold. Providing nil discards
+	// them. When Parallelism is greater than 1 the logs of different folds are
+	// interleaved.
+	Log io.Writer
+}
+
+// FoldResult is the outcome of a single fold.
+type FoldResult struct {
+	// Fold is the index of the fold.
+	Fold int
+	// TrainSize and TestSize are the number of datums trained and evaluated
+	// on.
+	TrainSize, TestSize int
+	// Values holds the value of every Metric, keyed by its name.
+	Values map[string]float64
+	// Network is the network trained on the fold.
+	Network network.Network
+}
+
+// Result is the outcome of every fold of a Run.
+type Result struct {
+	// Folds holds the result of every fold, in order.
+	Folds []FoldResult
+	// Mean and Std hold the mean and population standard deviation of every
+	// Metric across folds, keyed by its name.
+	Mean, Std map[string]float64
+}
+
+// Run splits d into folds as described by o, then for every fold trains a
+// fresh network from spec on the fold's training data with c, and evaluates
+// o.Metrics on the fold's test data.
+//
+// If any fold fails, no more folds are started and, once those already
+// training finish, the error of the earliest failed fold is returned.
+func Run(spec SpecFunc, c trainer.Configuration, d trainer.Data, o Options) (Result, error) {
+	if len(o.Metrics) == 0 {
+		return Result{}, errors.New("must provide at least one metric")
+	}
+
+	// NOTE: Only the fold each datum is assigned to is kept up front, and each
+	// fold is built once it is run, so that at most Parallelism folds are held
+	// in memory at once rather than every one of them.
+	var assignments []int
+	var err error
+	k := o.Folds
+	switch o.Method {
+	case MethodKFold:
+		assignments, err = kFold(d, k)
+	case MethodStratifiedKFold:
+		assignments, err = stratifiedKFold(d, k)
+	case MethodLeaveOneOut:
+		assignments, err = leaveOneOut(d)
+		k = len(d)
+	default:
+		return Result{}, fmt.Errorf("unknown method %v", o.Method)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("splitting data via %v: %w", o.Method, err)
+	}
+
+	log := o.Log
+	if log == nil {
+		log = io.Discard
+	}
+	log = &syncWriter{w: log}
+
+	parallelism := o.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	results := make([]FoldResult, k)
+	errs := make([]error, k)
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+	for fi := 0; fi < k; fi++ {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(fi int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			r, err := runFold(spec, c, fold(d, assignments, fi), o.Metrics, log)
+			if err != nil {
+				errs[fi] = fmt.Errorf("fold %v: %w", fi, err)
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+				return
+			}
+			r.Fold = fi
+			results[fi] = r
+		}(fi)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	return aggregate(results, o.Metrics), nil
+}
+
+// MustRun calls Run but panics if an error is encountered.
+func MustRun(spec SpecFunc, c trainer.Configuration, d trainer.Data, o Options) Result {
+	r, err := Run(spec, c, d, o)
+	if err != nil {
+		panic(errors.Wrap(err, "must run"))
+	}
+	return r
+}
+
+func runFold(spec SpecFunc, c trainer.Configuration, f Fold, metrics []trainer.Metric, log io.Writer) (FoldResult, error) {
+	nw, err := network.From(spec())
+	if err != nil {
+		return FoldResult{}, fmt.Errorf("creating network: %w", err)
+	}
+
+	// NOTE: Reaching the timeout of c ends training of the fold like any other
+	// cutoff, rather than failing the whole run.
+	t := trainer.New(c, f.Train, log)
+	if err := t.Train(nw); err != nil && !errors.Is(err, trainer.ErrTimedOut) {
+		return FoldResult{}, fmt.Errorf("training: %w", err)
+	}
+
+	r := FoldResult{
+		TrainSize: len(f.Train),
+		TestSize:  len(f.Test),
+		Values:    make(map[string]float64, len(metrics)),
+		Network:   nw,
+	}
+	for _, m := range metrics {
+		v, err := m.Evaluate(nw, f.Test)
+		if err != nil {
+			return FoldResult{}, fmt.Errorf("evaluating metric %v: %w", m.Name(), err)
+		}
+		r.Values[m.Name()] = v
+	}
+	return r, nil
+}
+
+func aggregate(folds []FoldResult, metrics []trainer.Metric) Result {
+	r := Result{
+		Folds: folds,
+		Mean:  make(map[string]float64, len(metrics)),
+		Std:   make(map[string]float64, len(metrics)),
+	}
+	for _, m := range metrics {
+		name := m.Name()
+		mean := 0.0
+		for _, f := range folds {
+			mean += f.Values[name]
+		}
+		mean /= float64(len(folds))
+
+		variance := 0.0
+		for _, f := range folds {
+			variance += (f.Values[name] - mean) * (f.Values[name] - mean)
+		}
+		variance /= float64(len(folds))
+
+		r.Mean[name], r.Std[name] = mean, math.Sqrt(variance)
+	}
+	return r
+}
+
+// syncWriter serializes writes to w, so that folds training concurrently can
+// share it.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.Write(p)
+}
+
+// String formats the mean and standard deviation of every Metric of r, sorted
+// by name.
+func (r Result) String() string {
+	names := make([]string, 0, len(r.Mean))
+	for name := range r.Mean {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%v folds:", len(r.Folds))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&sb, " %v=%.4f±%.4f", name, r.Mean[name], r.Std[name])
+	}
+	return sb.String()
+}
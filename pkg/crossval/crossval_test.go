@@ -0,0 +1,122 @@
+package crossval
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// constant is a Metric evaluating to the number of datums it is evaluated on.
+type constant struct{}
+
+func (constant) Name() string {
+	return "size"
+}
+
+func (constant) Evaluate(_ network.Network, d trainer.Data) (float64, error) {
+	return float64(len(d)), nil
+}
+
+type failing struct{}
+
+func (failing) Name() string {
+	return "failing"
+}
+
+func (failing) Evaluate(network.Network, trainer.Data) (float64, error) {
+	return 0, errors.New("failed")
+}
+
+func Test_Run(t *testing.T) {
+	var calls int32
+	specs := func() network.Spec {
+		atomic.AddInt32(&calls, 1)
+		return network.Spec{
+			NeuronMap:              []int{1, 3},
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"a", "b", "c"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		}
+	}
+	c := trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 4, MaxIterations: 10}
+
+	r, err := Run(specs, c, data(10), Options{
+		Method:      MethodKFold,
+		Folds:       4,
+		Metrics:     []trainer.Metric{constant{}},
+		Parallelism: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 4 || len(r.Folds) != 4 {
+		t.Fatalf("expected 4 folds each with a fresh network, got %v folds and %v networks", len(r.Folds), calls)
+	}
+	for fi, f := range r.Folds {
+		if f.Fold != fi || f.Network == nil || f.TrainSize+f.TestSize != 10 {
+			t.Fatalf("unexpected result for fold %v: %+v", fi, f)
+		}
+		if f.Values["size"] != float64(f.TestSize) {
+			t.Fatalf("expected metric of fold %v to be evaluated on its test data, got %v", fi, f.Values)
+		}
+	}
+	// Folds test on 3, 3, 2 and 2 datums.
+	if r.Mean["size"] != 2.5 || math.Abs(r.Std["size"]-0.5) > 1e-9 {
+		t.Fatalf("expected mean 2.5 and std 0.5, got %v and %v", r.Mean["size"], r.Std["size"])
+	}
+	if !strings.Contains(r.String(), "size=2.5000±0.5000") {
+		t.Fatalf("unexpected summary %q", r.String())
+	}
+}
+
+func Test_Run_TimedOut(t *testing.T) {
+	spec := func() network.Spec {
+		return network.Spec{
+			NeuronMap:              []int{1, 3},
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"a", "b", "c"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		}
+	}
+	c := trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 4, MaxIterations: math.MaxInt32, Timeout: 10 * time.Millisecond}
+
+	r, err := Run(spec, c, data(10), Options{Method: MethodKFold, Folds: 2, Metrics: []trainer.Metric{constant{}}})
+	if err != nil {
+		t.Fatalf("expected timed out folds to be evaluated, got %v", err)
+	}
+	if len(r.Folds) != 2 {
+		t.Fatalf("expected 2 folds, got %v", len(r.Folds))
+	}
+}
+
+func Test_Run_Errors(t *testing.T) {
+	spec := func() network.Spec {
+		return network.Spec{
+			NeuronMap:              []int{1, 3},
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"a", "b", "c"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		}
+	}
+	c := trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 1, MaxIterations: 1}
+
+	if _, err := Run(spec, c, data(4), Options{Method: MethodLeaveOneOut}); err == nil {
+		t.Fatal("expected an error without metrics")
+	}
+	if _, err := Run(spec, c, data(4), Options{Method: MethodKFold, Folds: 5, Metrics: []trainer.Metric{constant{}}}); err == nil {
+		t.Fatal("expected an error for more folds than datums")
+	}
+
+	_, err := Run(spec, c, data(4), Options{Method: MethodLeaveOneOut, Metrics: []trainer.Metric{failing{}}, Parallelism: 2})
+	if err == nil || !strings.Contains(err.Error(), "fold 0") {
+		t.Fatalf("expected the error of fold 0, got %v", err)
+	}
+}
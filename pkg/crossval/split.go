@@ -0,0 +1,154 @@
+package crossval
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Fold is a single split of data into the data a network is trained on and the
+// data it is then evaluated on.
+type Fold struct {
+	Train trainer.Data
+	Test  trainer.Data
+}
+
+// KFold shuffles d and splits it into k folds of as equal size as possible,
+// each of which is tested on once while the others are trained on.
+func KFold(d trainer.Data, k int) ([]Fold, error) {
+	assignments, err := kFold(d, k)
+	if err != nil {
+		return nil, err
+	}
+	return split(d, assignments, k), nil
+}
+
+// kFold assigns every datum of d to one of the k folds of KFold.
+func kFold(d trainer.Data, k int) ([]int, error) {
+	if err := checkFolds(len(d), k); err != nil {
+		return nil, err
+	}
+
+	assignments := make([]int, len(d))
+	for i, di := range rand.Perm(len(d)) {
+		assignments[di] = i % k
+	}
+	return assignments, nil
+}
+
+// StratifiedKFold splits d into k folds like KFold, but keeps the proportion of
+// each class in every fold as close as possible to its proportion in d. The
+// class of a datum is the index of the largest value of its Truth.
+func StratifiedKFold(d trainer.Data, k int) ([]Fold, error) {
+	assignments, err := stratifiedKFold(d, k)
+	if err != nil {
+		return nil, err
+	}
+	return split(d, assignments, k), nil
+}
+
+// stratifiedKFold assigns every datum of d to one of the k folds of
+// StratifiedKFold.
+func stratifiedKFold(d trainer.Data, k int) ([]int, error) {
+	if err := checkFolds(len(d), k); err != nil {
+		return nil, err
+	}
+
+	var classes [][]int
+	indexes := make(map[int]int)
+	for di, td := range d {
+		c := argmax(td.Truth)
+		ci, found := indexes[c]
+		if !found {
+			ci = len(classes)
+			indexes[c] = ci
+			classes = append(classes, nil)
+		}
+		classes[ci] = append(classes[ci], di)
+	}
+
+	// NOTE: Dealing continues across classes rather than restarting at fold 0
+	// for each, so that the folds stay balanced in size as well as by class.
+	assignments := make([]int, len(d))
+	f := 0
+	for _, class := range classes {
+		rand.Shuffle(len(class), func(i, j int) {
+			class[i], class[j] = class[j], class[i]
+		})
+		for _, di := range class {
+			assignments[di] = f
+			f = (f + 1) % k
+		}
+	}
+	return assignments, nil
+}
+
+// LeaveOneOut splits d into len(d) folds, each of which tests on a single
+// datum and trains on every other.
+func LeaveOneOut(d trainer.Data) ([]Fold, error) {
+	assignments, err := leaveOneOut(d)
+	if err != nil {
+		return nil, err
+	}
+	return split(d, assignments, len(d)), nil
+}
+
+// leaveOneOut assigns every datum of d to its own fold of LeaveOneOut.
+func leaveOneOut(d trainer.Data) ([]int, error) {
+	if err := checkFolds(len(d), len(d)); err != nil {
+		return nil, err
+	}
+
+	assignments := make([]int, len(d))
+	for i := range assignments {
+		assignments[i] = i
+	}
+	return assignments, nil
+}
+
+func checkFolds(size, k int) error {
+	if k < 2 {
+		return fmt.Errorf("number of folds (%v) must be at least 2", k)
+	}
+	if k > size {
+		return fmt.Errorf("number of folds (%v) must not exceed number of datums (%v)", k, size)
+	}
+	return nil
+}
+
+// split builds all k folds of d, as fold builds each of them.
+func split(d trainer.Data, assignments []int, k int) []Fold {
+	folds := make([]Fold, k)
+	for fi := range folds {
+		folds[fi] = fold(d, assignments, fi)
+	}
+	return folds
+}
+
+// fold builds fold f of d, which tests on every datum assigned to f and trains
+// on every other. Its datums are copied so that folds can be trained on, which
+// shuffles them, concurrently.
+func fold(d trainer.Data, assignments []int, f int) Fold {
+	var ff Fold
+	for di, a := range assignments {
+		if a == f {
+			ff.Test = append(ff.Test, d[di])
+		} else {
+			ff.Train = append(ff.Train, d[di])
+		}
+	}
+	return ff
+}
+
+// argmax returns the index of the largest value of vs, preferring the first on
+// ties.
+func argmax(vs []float64) int {
+	best := 0
+	for i, v := range vs {
+		if v > vs[best] {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,112 @@
+package crossval
+
+import (
+	"testing"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// data returns n datums whose Data identifies them, with classes 0, 1 and 2 in
+// the ratio 1:2:3.
+func data(n int) trainer.Data {
+	d := make(trainer.Data, n)
+	for i := range d {
+		truth := []float64{0, 0, 0}
+		switch i % 6 {
+		case 0:
+			truth[0] = 1
+		case 1, 2:
+			truth[1] = 1
+		default:
+			truth[2] = 1
+		}
+		d[i] = trainer.Datum{Data: []float64{float64(i)}, Truth: truth}
+	}
+	return d
+}
+
+// checkPartition checks that every datum of d is tested on exactly once, and
+// trained on in every other fold.
+func checkPartition(t *testing.T, d trainer.Data, folds []Fold) {
+	t.Helper()
+	tested := make(map[float64]int)
+	for fi, f := range folds {
+		if len(f.Train)+len(f.Test) != len(d) {
+			t.Fatalf("fold %v has %v train and %v test datums, expected %v total", fi, len(f.Train), len(f.Test), len(d))
+		}
+		inTest := make(map[float64]bool)
+		for _, td := range f.Test {
+			tested[td.Data[0]]++
+			inTest[td.Data[0]] = true
+		}
+		for _, td := range f.Train {
+			if inTest[td.Data[0]] {
+				t.Fatalf("fold %v trains and tests on datum %v", fi, td.Data[0])
+			}
+		}
+	}
+	for _, td := range d {
+		if tested[td.Data[0]] != 1 {
+			t.Fatalf("datum %v tested on %v times", td.Data[0], tested[td.Data[0]])
+		}
+	}
+}
+
+func Test_KFold(t *testing.T) {
+	d := data(10)
+	folds, err := KFold(d, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folds) != 3 {
+		t.Fatalf("expected 3 folds, got %v", len(folds))
+	}
+	checkPartition(t, d, folds)
+	for fi, f := range folds {
+		if len(f.Test) < 3 || len(f.Test) > 4 {
+			t.Fatalf("expected fold %v to test on 3 or 4 datums, got %v", fi, len(f.Test))
+		}
+	}
+
+	if _, err := KFold(d, 1); err == nil {
+		t.Fatal("expected an error for 1 fold")
+	}
+	if _, err := KFold(d, 11); err == nil {
+		t.Fatal("expected an error for more folds than datums")
+	}
+}
+
+func Test_StratifiedKFold(t *testing.T) {
+	d := data(60)
+	folds, err := StratifiedKFold(d, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPartition(t, d, folds)
+	for fi, f := range folds {
+		counts := make([]int, 3)
+		for _, td := range f.Test {
+			counts[argmax(td.Truth)]++
+		}
+		if counts[0] != 2 || counts[1] != 4 || counts[2] != 6 {
+			t.Fatalf("expected fold %v to test on 2, 4 and 6 of each class, got %v", fi, counts)
+		}
+	}
+}
+
+func Test_LeaveOneOut(t *testing.T) {
+	d := data(4)
+	folds, err := LeaveOneOut(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folds) != 4 {
+		t.Fatalf("expected 4 folds, got %v", len(folds))
+	}
+	checkPartition(t, d, folds)
+	for fi, f := range folds {
+		if len(f.Test) != 1 {
+			t.Fatalf("expected fold %v to test on 1 datum, got %v", fi, len(f.Test))
+		}
+	}
+}
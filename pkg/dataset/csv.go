@@ -0,0 +1,207 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// DefaultMissingValues are the values ReadCSV treats as missing unless
+// overridden via CSVConfig.MissingValues.
+var DefaultMissingValues = []string{"", "NA", "N/A", "NaN", "null", "?"}
+
+// CSVConfig configures ReadCSV.
+type CSVConfig struct {
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// NoHeader indicates that the first row holds data rather than the names
+	// of the columns. Columns are then named by their 0-based index, so the
+	// third column is selected via "2".
+	NoHeader bool
+	// Features are the names of the columns used as the inputs of each datum,
+	// in order. Defaults to every column which is not a target.
+	Features []string
+	// Targets are the names of the columns used as the truth of each datum, in
+	// order. At least one is required.
+	Targets []string
+	// TargetClasses, when set, one-hot encodes the single target column
+	// against these classes instead of parsing it as a number, so that a
+	// column of class names such as "setosa" can be used directly.
+	TargetClasses []string
+	// MissingValues are the values, after trimming surrounding whitespace,
+	// which are treated as missing. Defaults to DefaultMissingValues.
+	MissingValues []string
+	// Imputation decides how missing feature values are handled.
+	Imputation Imputation
+}
+
+// ReadCSV reads r as CSV into trainer.Data according to c.
+func ReadCSV(r io.Reader, c CSVConfig) (trainer.Data, error) {
+	if len(c.Targets) == 0 {
+		return nil, errors.New("at least one target column is required")
+	}
+	if len(c.TargetClasses) > 0 && len(c.Targets) != 1 {
+		return nil, fmt.Errorf("target classes require exactly 1 target column, got %v", len(c.Targets))
+	}
+	missingValues := c.MissingValues
+	if missingValues == nil {
+		missingValues = DefaultMissingValues
+	}
+	isMissing := make(map[string]bool, len(missingValues))
+	for _, mv := range missingValues {
+		isMissing[mv] = true
+	}
+
+	cr := csv.NewReader(r)
+	if c.Comma != 0 {
+		cr.Comma = c.Comma
+	}
+	cr.ReuseRecord = true
+
+	record, err := cr.Read()
+	if err == io.EOF {
+		return trainer.Data{}, nil
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+	header := make([]string, len(record))
+	for i, name := range record {
+		header[i] = strings.TrimSpace(name)
+		if c.NoHeader {
+			header[i] = strconv.Itoa(i)
+		}
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, found := index[name]; found {
+			return nil, &ParseError{Line: 1, Column: name, Err: errors.New("duplicate column")}
+		}
+		index[name] = i
+	}
+
+	targets, err := columnIndices(index, c.Targets)
+	if err != nil {
+		return nil, err
+	}
+	features := c.Features
+	if len(features) == 0 {
+		isTarget := make(map[string]bool, len(c.Targets))
+		for _, name := range c.Targets {
+			isTarget[name] = true
+		}
+		for _, name := range header {
+			if !isTarget[name] {
+				features = append(features, name)
+			}
+		}
+	}
+	featureIndices, err := columnIndices(index, features)
+	if err != nil {
+		return nil, err
+	}
+
+	t := table{columns: features}
+	parseRow := func(record []string) error {
+		line, _ := cr.FieldPos(0)
+
+		row := make([]float64, len(featureIndices))
+		for i, ci := range featureIndices {
+			s := strings.TrimSpace(record[ci])
+			if isMissing[s] {
+				row[i] = missing
+				continue
+			}
+			v, err := parseFloat(s)
+			if err != nil {
+				return &ParseError{Line: line, Column: header[ci], Err: err}
+			}
+			row[i] = v
+		}
+
+		var truth []float64
+		for _, ci := range targets {
+			s := strings.TrimSpace(record[ci])
+			if isMissing[s] {
+				truth = append(truth, missing)
+				continue
+			}
+			if len(c.TargetClasses) > 0 {
+				tv, err := encodeClass(c.TargetClasses, s)
+				if err != nil {
+					return &ParseError{Line: line, Column: header[ci], Err: err}
+				}
+				truth = append(truth, tv...)
+				continue
+			}
+			v, err := parseFloat(s)
+			if err != nil {
+				return &ParseError{Line: line, Column: header[ci], Err: err}
+			}
+			truth = append(truth, v)
+		}
+
+		t.lines = append(t.lines, line)
+		t.data = append(t.data, row)
+		t.truths = append(t.truths, truth)
+		return nil
+	}
+
+	if c.NoHeader {
+		if err := parseRow(record); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		if err := parseRow(record); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.impute(c.Imputation)
+}
+
+// MustReadCSV calls ReadCSV but panics if an error is encountered.
+func MustReadCSV(r io.Reader, c CSVConfig) trainer.Data {
+	d, err := ReadCSV(r, c)
+	if err != nil {
+		panic(errors.Wrap(err, "must read csv"))
+	}
+	return d
+}
+
+// columnIndices returns the index of each of names.
+func columnIndices(index map[string]int, names []string) ([]int, error) {
+	indices := make([]int, len(names))
+	for i, name := range names {
+		ci, found := index[name]
+		if !found {
+			return nil, fmt.Errorf("no column found with name %q", name)
+		}
+		indices[i] = ci
+	}
+	return indices, nil
+}
+
+// csvError converts the line numbered errors of encoding/csv into a
+// *ParseError.
+func csvError(err error) error {
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		return &ParseError{Line: pe.Line, Err: pe.Err}
+	}
+	return errors.Wrap(err, "reading csv")
+}
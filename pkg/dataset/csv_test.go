@@ -0,0 +1,132 @@
+package dataset
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+func Test_ReadCSV(t *testing.T) {
+	in := `id,x1,x2,y
+1,0.5,-1,1
+2,2,3e-1,0
+`
+	d := MustReadCSV(strings.NewReader(in), CSVConfig{
+		Features: []string{"x2", "x1"},
+		Targets:  []string{"y"},
+	})
+
+	expected := trainer.Data{
+		{Data: []float64{-1, 0.5}, Truth: []float64{1}},
+		{Data: []float64{0.3, 2}, Truth: []float64{0}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadCSV_DefaultsFeaturesToNonTargets(t *testing.T) {
+	in := "a;y;b\n1;2;3\n"
+	d := MustReadCSV(strings.NewReader(in), CSVConfig{Comma: ';', Targets: []string{"y"}})
+
+	expected := trainer.Data{{Data: []float64{1, 3}, Truth: []float64{2}}}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadCSV_NoHeader(t *testing.T) {
+	in := "1,2,setosa\n3,4,virginica\n"
+	d := MustReadCSV(strings.NewReader(in), CSVConfig{
+		NoHeader:      true,
+		Targets:       []string{"2"},
+		TargetClasses: []string{"setosa", "versicolor", "virginica"},
+	})
+
+	expected := trainer.Data{
+		{Data: []float64{1, 2}, Truth: []float64{1, 0, 0}},
+		{Data: []float64{3, 4}, Truth: []float64{0, 0, 1}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadCSV_Imputes(t *testing.T) {
+	in := "x,y\n1,0\nNA,0\n3,1\n"
+	d := MustReadCSV(strings.NewReader(in), CSVConfig{
+		Targets:    []string{"y"},
+		Imputation: Imputation{Strategy: ImputeMean},
+	})
+
+	if d[1].Data[0] != 2 {
+		t.Fatalf("expected missing value to be imputed as 2, got %v", d[1].Data[0])
+	}
+}
+
+func Test_ReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		config CSVConfig
+		line   int
+		column string
+	}{
+		{
+			name:   "invalid number",
+			in:     "x,y\n1,0\n\n2,0\nabc,1\n",
+			config: CSVConfig{Targets: []string{"y"}},
+			line:   5,
+			column: "x",
+		},
+		{
+			name:   "missing value",
+			in:     "x,y\n1,0\n,1\n",
+			config: CSVConfig{Targets: []string{"y"}},
+			line:   3,
+			column: "x",
+		},
+		{
+			name:   "missing target",
+			in:     "x,y\n1,0\n2,?\n",
+			config: CSVConfig{Targets: []string{"y"}, Imputation: Imputation{Strategy: ImputeZero}},
+			line:   3,
+		},
+		{
+			name:   "unknown class",
+			in:     "x,y\n1,cat\n2,dog\n",
+			config: CSVConfig{Targets: []string{"y"}, TargetClasses: []string{"cat"}},
+			line:   3,
+			column: "y",
+		},
+		{
+			name:   "wrong number of fields",
+			in:     "x,y\n1,0\n2\n",
+			config: CSVConfig{Targets: []string{"y"}},
+			line:   3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(test.in), test.config)
+
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a parse error, got %v", err)
+			}
+			if pe.Line != test.line || pe.Column != test.column {
+				t.Fatalf("expected error at line %v column %q, got %v", test.line, test.column, pe)
+			}
+		})
+	}
+
+	if _, err := ReadCSV(strings.NewReader("x,y\n"), CSVConfig{Targets: []string{"z"}}); err == nil {
+		t.Fatalf("expected error for unknown column")
+	}
+	if _, err := ReadCSV(strings.NewReader("x,y\n"), CSVConfig{}); err == nil {
+		t.Fatalf("expected error for no targets")
+	}
+}
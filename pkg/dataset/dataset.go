@@ -0,0 +1,206 @@
+// Package dataset reads training data from common file formats into
+// trainer.Data, so that it doesn't have to be written out by hand as Go
+// literals.
+//
+// CSV, JSON Lines and LIBSVM are supported via ReadCSV, ReadJSONL and
+// ReadLIBSVM respectively. Problems with the contents of a file are reported
+// as a *ParseError carrying the line they were found on.
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// ParseError reports a problem with a value in a data file.
+type ParseError struct {
+	// Line is the 1-based line number the problem was found on.
+	Line int
+	// Column identifies the column or key the problem was found in, if any.
+	Column string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %v: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %v, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ImputeStrategy decides how a missing feature value is filled in.
+type ImputeStrategy int
+
+const (
+	// ImputeNone fails with a *ParseError when a value is missing.
+	ImputeNone ImputeStrategy = iota
+	// ImputeZero replaces missing values with 0.
+	ImputeZero
+	// ImputeConstant replaces missing values with Imputation.Value.
+	ImputeConstant
+	// ImputeMean replaces missing values with the mean of the values present
+	// in the same column.
+	ImputeMean
+	// ImputeMedian replaces missing values with the median of the values
+	// present in the same column.
+	ImputeMedian
+	// ImputeMostFrequent replaces missing values with the most frequent value
+	// present in the same column, preferring the smallest value on ties.
+	ImputeMostFrequent
+	// ImputeDrop discards every row with a missing value.
+	ImputeDrop
+)
+
+// Imputation configures how missing feature values are handled. Its zero value
+// treats a missing value as an error.
+//
+// Imputation only ever applies to features. A missing target fails with a
+// *ParseError, unless the strategy is ImputeDrop in which case its row is
+// discarded.
+type Imputation struct {
+	Strategy ImputeStrategy
+	// Value is used in place of missing values by ImputeConstant.
+	Value float64
+}
+
+// table holds the rows read from a data file before missing values have been
+// imputed. Missing values are recorded as NaN.
+type table struct {
+	columns []string
+	lines   []int
+	data    [][]float64
+	truths  [][]float64
+}
+
+// missing is the value recorded in a table for a missing feature or target.
+var missing = math.NaN()
+
+// impute fills in the missing values of t according to imp, and returns the
+// resulting trainer.Data.
+func (t *table) impute(imp Imputation) (trainer.Data, error) {
+	fills := make([]float64, len(t.columns))
+	for ci := range t.columns {
+		var present []float64
+		for ri, row := range t.data {
+			v := row[ci]
+			if !math.IsNaN(v) {
+				present = append(present, v)
+				continue
+			}
+			if imp.Strategy == ImputeNone {
+				return nil, &ParseError{Line: t.lines[ri], Column: t.columns[ci], Err: fmt.Errorf("missing value")}
+			}
+		}
+
+		switch imp.Strategy {
+		case ImputeNone, ImputeZero, ImputeDrop:
+		case ImputeConstant:
+			fills[ci] = imp.Value
+		case ImputeMean, ImputeMedian, ImputeMostFrequent:
+			if len(present) == 0 {
+				if len(t.data) == 0 {
+					continue
+				}
+				return nil, fmt.Errorf("can't impute column %q, every value is missing", t.columns[ci])
+			}
+			fills[ci] = summarize(imp.Strategy, present)
+		default:
+			return nil, fmt.Errorf("unknown impute strategy %v", imp.Strategy)
+		}
+	}
+
+	d := make(trainer.Data, 0, len(t.data))
+rows:
+	for ri, row := range t.data {
+		for _, v := range t.truths[ri] {
+			if !math.IsNaN(v) {
+				continue
+			}
+			if imp.Strategy == ImputeDrop {
+				continue rows
+			}
+			return nil, &ParseError{Line: t.lines[ri], Err: fmt.Errorf("missing target")}
+		}
+
+		datum := trainer.Datum{Data: make([]float64, len(row)), Truth: t.truths[ri]}
+		for ci, v := range row {
+			if math.IsNaN(v) {
+				if imp.Strategy == ImputeDrop {
+					continue rows
+				}
+				v = fills[ci]
+			}
+			datum.Data[ci] = v
+		}
+		d = append(d, datum)
+	}
+	return d, nil
+}
+
+// summarize returns the mean, median or most frequent value of vs, which must
+// not be empty.
+func summarize(s ImputeStrategy, vs []float64) float64 {
+	switch s {
+	case ImputeMean:
+		sum := 0.0
+		for _, v := range vs {
+			sum += v
+		}
+		return sum / float64(len(vs))
+
+	case ImputeMedian:
+		sorted := append([]float64(nil), vs...)
+		sort.Float64s(sorted)
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[mid-1] + sorted[mid]) / 2
+		}
+		return sorted[mid]
+
+	default:
+		counts := make(map[float64]int, len(vs))
+		for _, v := range vs {
+			counts[v]++
+		}
+		best, bestCount := 0.0, 0
+		for v, c := range counts {
+			if c > bestCount || (c == bestCount && v < best) {
+				best, bestCount = v, c
+			}
+		}
+		return best
+	}
+}
+
+// encodeClass one-hot encodes class against classes.
+func encodeClass(classes []string, class string) ([]float64, error) {
+	truth := make([]float64, len(classes))
+	for i, c := range classes {
+		if c == class {
+			truth[i] = 1
+			return truth, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown class %q, must be one of %q", class, classes)
+}
+
+// parseFloat parses s as a finite float64. "NaN" is accepted, and so is
+// recorded as a missing value.
+func parseFloat(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	if math.IsInf(v, 0) {
+		return 0, fmt.Errorf("non-finite number %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,57 @@
+package dataset
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_Impute(t *testing.T) {
+	newTable := func() table {
+		return table{
+			columns: []string{"x"},
+			lines:   []int{1, 2, 3, 4, 5},
+			data:    [][]float64{{1}, {missing}, {4}, {4}, {10}},
+			truths:  [][]float64{{0}, {0}, {0}, {0}, {0}},
+		}
+	}
+
+	tests := []struct {
+		imputation Imputation
+		expected   float64
+	}{
+		{imputation: Imputation{Strategy: ImputeZero}, expected: 0},
+		{imputation: Imputation{Strategy: ImputeConstant, Value: -1}, expected: -1},
+		{imputation: Imputation{Strategy: ImputeMean}, expected: 4.75},
+		{imputation: Imputation{Strategy: ImputeMedian}, expected: 4},
+		{imputation: Imputation{Strategy: ImputeMostFrequent}, expected: 4},
+	}
+	for _, test := range tests {
+		tb := newTable()
+		d, err := tb.impute(test.imputation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(d) != 5 || d[1].Data[0] != test.expected {
+			t.Fatalf("strategy %v: expected %v, got %v", test.imputation.Strategy, test.expected, d)
+		}
+	}
+
+	tb := newTable()
+	d, err := tb.impute(Imputation{Strategy: ImputeDrop})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 4 {
+		t.Fatalf("expected row with missing value to be dropped, got %v", d)
+	}
+
+	tb = newTable()
+	if _, err := tb.impute(Imputation{}); err == nil {
+		t.Fatalf("expected error for missing value without imputation")
+	}
+
+	tb = table{columns: []string{"x"}, lines: []int{1}, data: [][]float64{{math.NaN()}}, truths: [][]float64{{0}}}
+	if _, err := tb.impute(Imputation{Strategy: ImputeMedian}); err == nil {
+		t.Fatalf("expected error imputing a column with no values")
+	}
+}
@@ -0,0 +1,206 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// IDXType is the type of the values held by an IDX file.
+type IDXType byte
+
+const (
+	IDXUint8   IDXType = 0x08
+	IDXInt8    IDXType = 0x09
+	IDXInt16   IDXType = 0x0B
+	IDXInt32   IDXType = 0x0C
+	IDXFloat32 IDXType = 0x0D
+	IDXFloat64 IDXType = 0x0E
+)
+
+// size returns the number of bytes each value of type t takes up, or 0 if t is
+// unknown.
+func (t IDXType) size() int {
+	switch t {
+	case IDXUint8, IDXInt8:
+		return 1
+	case IDXInt16:
+		return 2
+	case IDXInt32, IDXFloat32:
+		return 4
+	case IDXFloat64:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// IDX is the contents of a file in the IDX format, as used by the MNIST and
+// Fashion-MNIST datasets.
+type IDX struct {
+	Type IDXType
+	// Dims are the sizes of each dimension, the first of which is the number
+	// of items in the file.
+	Dims []int
+	// Values holds every value in the file in row major order.
+	Values []float64
+}
+
+// maxIDXValues bounds the number of values in an IDX file, so that a corrupt
+// header can't overflow the computation of its size.
+const maxIDXValues = 1 << 30
+
+// ReadIDX reads an IDX file from r. Files compressed with gzip, as MNIST is
+// distributed, are decompressed transparently.
+func ReadIDX(r io.Reader) (IDX, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return IDX{}, errors.Wrap(err, "gzip new reader")
+		}
+		defer gr.Close()
+		br = bufio.NewReader(gr)
+	}
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return IDX{}, errors.Wrap(err, "reading magic number")
+	}
+	if magic[0] != 0 || magic[1] != 0 {
+		return IDX{}, fmt.Errorf("invalid idx magic number %x", magic)
+	}
+	t := IDXType(magic[2])
+	if t.size() == 0 {
+		return IDX{}, fmt.Errorf("unknown idx type 0x%02x", magic[2])
+	}
+	if magic[3] == 0 {
+		return IDX{}, errors.New("idx file must have at least 1 dimension")
+	}
+
+	idx := IDX{Type: t, Dims: make([]int, magic[3])}
+	n := 1
+	for i := range idx.Dims {
+		var dim uint32
+		if err := binary.Read(br, binary.BigEndian, &dim); err != nil {
+			return IDX{}, errors.Wrapf(err, "reading size of dimension %v", i)
+		}
+		idx.Dims[i] = int(dim)
+		if dim != 0 && n > maxIDXValues/int(dim) {
+			return IDX{}, fmt.Errorf("idx file of dimensions %v is too large", idx.Dims[:i+1])
+		}
+		n *= int(dim)
+	}
+
+	// NOTE: The values are read incrementally rather than into a buffer of
+	// the size claimed by the header, so that a truncated file fails without
+	// first allocating for values that don't exist.
+	bs, err := io.ReadAll(io.LimitReader(br, int64(n*t.size())))
+	if err != nil {
+		return IDX{}, errors.Wrapf(err, "reading %v values", n)
+	}
+	if len(bs) != n*t.size() {
+		return IDX{}, fmt.Errorf("expected %v values of %v bytes, but file ends after %v bytes", n, t.size(), len(bs))
+	}
+	idx.Values = make([]float64, n)
+	for i := range idx.Values {
+		b := bs[i*t.size():]
+		switch t {
+		case IDXUint8:
+			idx.Values[i] = float64(b[0])
+		case IDXInt8:
+			idx.Values[i] = float64(int8(b[0]))
+		case IDXInt16:
+			idx.Values[i] = float64(int16(binary.BigEndian.Uint16(b)))
+		case IDXInt32:
+			idx.Values[i] = float64(int32(binary.BigEndian.Uint32(b)))
+		case IDXFloat32:
+			idx.Values[i] = float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		case IDXFloat64:
+			idx.Values[i] = math.Float64frombits(binary.BigEndian.Uint64(b))
+		}
+	}
+	return idx, nil
+}
+
+// MustReadIDX calls ReadIDX but panics if an error is encountered.
+func MustReadIDX(r io.Reader) IDX {
+	idx, err := ReadIDX(r)
+	if err != nil {
+		panic(errors.Wrap(err, "must read idx"))
+	}
+	return idx
+}
+
+// ReadMNIST reads a pair of IDX files, holding images and their labels in the
+// layout of MNIST and Fashion-MNIST, into trainer.Data.
+//
+// Each image becomes the Data of a datum, flattened row by row with its pixels
+// normalized from [0, 255] to [0, 1]. Each label becomes a one-hot Truth
+// against outputLabels, where label l sets the index of the output label
+// strconv.Itoa(l), so the OutputLabels of the network being trained can be
+// passed directly.
+func ReadMNIST(images, labels io.Reader, outputLabels []string) (trainer.Data, error) {
+	imgs, err := ReadIDX(images)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading images")
+	}
+	lbls, err := ReadIDX(labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading labels")
+	}
+
+	if imgs.Type != IDXUint8 || len(imgs.Dims) < 2 {
+		return nil, fmt.Errorf("images must be unsigned bytes of at least 2 dimensions, got type 0x%02x of dimensions %v", byte(imgs.Type), imgs.Dims)
+	}
+	if lbls.Type != IDXUint8 || len(lbls.Dims) != 1 {
+		return nil, fmt.Errorf("labels must be unsigned bytes of 1 dimension, got type 0x%02x of dimensions %v", byte(lbls.Type), lbls.Dims)
+	}
+	if imgs.Dims[0] != lbls.Dims[0] {
+		return nil, fmt.Errorf("number of images (%v) does not match number of labels (%v)", imgs.Dims[0], lbls.Dims[0])
+	}
+
+	indices := make(map[string]int, len(outputLabels))
+	for i, ol := range outputLabels {
+		indices[ol] = i
+	}
+
+	d := make(trainer.Data, imgs.Dims[0])
+	if len(d) == 0 {
+		return d, nil
+	}
+	pixels := len(imgs.Values) / len(d)
+	for i := range d {
+		label := strconv.Itoa(int(lbls.Values[i]))
+		li, found := indices[label]
+		if !found {
+			return nil, fmt.Errorf("label %v of item %v is not one of the output labels %q", label, i, outputLabels)
+		}
+
+		d[i].Data = make([]float64, pixels)
+		for pi, v := range imgs.Values[i*pixels : (i+1)*pixels] {
+			d[i].Data[pi] = v / 255
+		}
+		d[i].Truth = make([]float64, len(outputLabels))
+		d[i].Truth[li] = 1
+	}
+	return d, nil
+}
+
+// MustReadMNIST calls ReadMNIST but panics if an error is encountered.
+func MustReadMNIST(images, labels io.Reader, outputLabels []string) trainer.Data {
+	d, err := ReadMNIST(images, labels, outputLabels)
+	if err != nil {
+		panic(errors.Wrap(err, "must read mnist"))
+	}
+	return d
+}
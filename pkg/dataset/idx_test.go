@@ -0,0 +1,106 @@
+package dataset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// newIDX returns the bytes of an IDX file of type t with dimensions dims whose
+// values, already encoded, are bs.
+func newIDX(t IDXType, dims []uint32, bs []byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0, 0, byte(t), byte(len(dims))})
+	for _, dim := range dims {
+		_ = binary.Write(&b, binary.BigEndian, dim)
+	}
+	b.Write(bs)
+	return b.Bytes()
+}
+
+func Test_ReadIDX(t *testing.T) {
+	var values bytes.Buffer
+	_ = binary.Write(&values, binary.BigEndian, []int16{-2, 300, 7, 0, 1, -1})
+
+	idx := MustReadIDX(bytes.NewReader(newIDX(IDXInt16, []uint32{2, 3}, values.Bytes())))
+
+	if idx.Type != IDXInt16 || !reflect.DeepEqual(idx.Dims, []int{2, 3}) {
+		t.Fatalf("unexpected header %v %v", idx.Type, idx.Dims)
+	}
+	if expected := []float64{-2, 300, 7, 0, 1, -1}; !reflect.DeepEqual(idx.Values, expected) {
+		t.Fatalf("expected %v, got %v", expected, idx.Values)
+	}
+}
+
+func Test_ReadIDX_Gzip(t *testing.T) {
+	var values bytes.Buffer
+	_ = binary.Write(&values, binary.BigEndian, []float64{0.5, -1.25})
+
+	var b bytes.Buffer
+	gw := gzip.NewWriter(&b)
+	_, _ = gw.Write(newIDX(IDXFloat64, []uint32{2}, values.Bytes()))
+	_ = gw.Close()
+
+	idx := MustReadIDX(&b)
+
+	if expected := []float64{0.5, -1.25}; !reflect.DeepEqual(idx.Values, expected) {
+		t.Fatalf("expected %v, got %v", expected, idx.Values)
+	}
+}
+
+func Test_ReadIDX_Errors(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":          {},
+		"bad magic":      {1, 0, 8, 1, 0, 0, 0, 0},
+		"unknown type":   {0, 0, 7, 1, 0, 0, 0, 0},
+		"no dimensions":  {0, 0, 8, 0},
+		"truncated dims": {0, 0, 8, 2, 0, 0, 0, 1},
+		"truncated data": newIDX(IDXUint8, []uint32{2, 2}, []byte{1, 2, 3}),
+		"huge":           newIDX(IDXUint8, []uint32{1 << 20, 1 << 20}, nil),
+	}
+	for name, bs := range tests {
+		if _, err := ReadIDX(bytes.NewReader(bs)); err == nil {
+			t.Fatalf("%v: expected error", name)
+		}
+	}
+}
+
+func Test_ReadMNIST(t *testing.T) {
+	images := newIDX(IDXUint8, []uint32{2, 2, 2}, []byte{
+		0, 255, 51, 102,
+		255, 255, 0, 0,
+	})
+	labels := newIDX(IDXUint8, []uint32{2}, []byte{2, 0})
+
+	d := MustReadMNIST(bytes.NewReader(images), bytes.NewReader(labels), []string{"0", "1", "2"})
+
+	expected := trainer.Data{
+		{Data: []float64{0, 1, 0.2, 0.4}, Truth: []float64{0, 0, 1}},
+		{Data: []float64{1, 1, 0, 0}, Truth: []float64{1, 0, 0}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadMNIST_Errors(t *testing.T) {
+	images := newIDX(IDXUint8, []uint32{2, 1, 1}, []byte{0, 1})
+	labels := newIDX(IDXUint8, []uint32{2}, []byte{0, 9})
+
+	if _, err := ReadMNIST(bytes.NewReader(images), bytes.NewReader(labels), []string{"0", "1"}); err == nil {
+		t.Fatalf("expected error for label missing from output labels")
+	}
+
+	short := newIDX(IDXUint8, []uint32{1}, []byte{0})
+	if _, err := ReadMNIST(bytes.NewReader(images), bytes.NewReader(short), []string{"0"}); err == nil {
+		t.Fatalf("expected error for mismatched number of labels")
+	}
+
+	if _, err := ReadMNIST(bytes.NewReader(labels), bytes.NewReader(labels), []string{"0", "9"}); err == nil {
+		t.Fatalf("expected error for 1 dimensional images")
+	}
+}
@@ -0,0 +1,136 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// JSONLConfig configures ReadJSONL.
+type JSONLConfig struct {
+	// Features are the keys used as the inputs of each datum, in order. At
+	// least one is required.
+	Features []string
+	// Targets are the keys used as the truth of each datum, in order. At least
+	// one is required.
+	Targets []string
+	// TargetClasses, when set, one-hot encodes the single target key, whose
+	// values must then be strings, against these classes.
+	TargetClasses []string
+	// Imputation decides how missing feature values are handled. A key that
+	// is absent or null is missing.
+	Imputation Imputation
+}
+
+// ReadJSONL reads r as JSON Lines, one JSON object per line, into trainer.Data
+// according to c. Values must be numbers or booleans, which are read as 1 and
+// 0. Blank lines are skipped.
+func ReadJSONL(r io.Reader, c JSONLConfig) (trainer.Data, error) {
+	if len(c.Features) == 0 {
+		return nil, errors.New("at least one feature key is required")
+	}
+	if len(c.Targets) == 0 {
+		return nil, errors.New("at least one target key is required")
+	}
+	if len(c.TargetClasses) > 0 && len(c.Targets) != 1 {
+		return nil, fmt.Errorf("target classes require exactly 1 target key, got %v", len(c.Targets))
+	}
+
+	t := table{columns: c.Features}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<26)
+	for line := 1; s.Scan(); line++ {
+		bs := bytes.TrimSpace(s.Bytes())
+		if len(bs) == 0 {
+			continue
+		}
+
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(bs, &obj); err != nil {
+			return nil, &ParseError{Line: line, Err: errors.Wrap(err, "json unmarshal")}
+		}
+
+		row := make([]float64, len(c.Features))
+		for i, key := range c.Features {
+			v, err := jsonNumber(obj[key])
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: key, Err: err}
+			}
+			row[i] = v
+		}
+
+		var truth []float64
+		for _, key := range c.Targets {
+			raw := obj[key]
+			if len(c.TargetClasses) == 0 || isNull(raw) {
+				v, err := jsonNumber(raw)
+				if err != nil {
+					return nil, &ParseError{Line: line, Column: key, Err: err}
+				}
+				truth = append(truth, v)
+				continue
+			}
+			var class string
+			if err := json.Unmarshal(raw, &class); err != nil {
+				return nil, &ParseError{Line: line, Column: key, Err: fmt.Errorf("target must be a string when target classes are set, got %s", raw)}
+			}
+			tv, err := encodeClass(c.TargetClasses, class)
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: key, Err: err}
+			}
+			truth = append(truth, tv...)
+		}
+
+		t.lines = append(t.lines, line)
+		t.data = append(t.data, row)
+		t.truths = append(t.truths, truth)
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading json lines")
+	}
+
+	return t.impute(c.Imputation)
+}
+
+// MustReadJSONL calls ReadJSONL but panics if an error is encountered.
+func MustReadJSONL(r io.Reader, c JSONLConfig) trainer.Data {
+	d, err := ReadJSONL(r, c)
+	if err != nil {
+		panic(errors.Wrap(err, "must read jsonl"))
+	}
+	return d
+}
+
+func isNull(raw json.RawMessage) bool {
+	return raw == nil || bytes.Equal(raw, []byte("null"))
+}
+
+// jsonNumber returns the number held by raw, or missing if raw is absent or
+// null.
+func jsonNumber(raw json.RawMessage) (float64, error) {
+	if isNull(raw) {
+		return missing, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return 0, errors.Wrap(err, "json unmarshal")
+	}
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %s", raw)
+	}
+}
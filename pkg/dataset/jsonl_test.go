@@ -0,0 +1,74 @@
+package dataset
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+func Test_ReadJSONL(t *testing.T) {
+	in := `{"a": 1, "b": true, "label": "spam"}
+
+{"a": -2.5, "b": false, "label": "ham", "ignored": "x"}
+`
+	d := MustReadJSONL(strings.NewReader(in), JSONLConfig{
+		Features:      []string{"a", "b"},
+		Targets:       []string{"label"},
+		TargetClasses: []string{"ham", "spam"},
+	})
+
+	expected := trainer.Data{
+		{Data: []float64{1, 1}, Truth: []float64{0, 1}},
+		{Data: []float64{-2.5, 0}, Truth: []float64{1, 0}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadJSONL_Imputes(t *testing.T) {
+	in := `{"a": 1, "y": 0}
+{"a": null, "y": 1}
+{"y": 1}
+{"a": 5, "y": null}
+`
+	d := MustReadJSONL(strings.NewReader(in), JSONLConfig{
+		Features:   []string{"a"},
+		Targets:    []string{"y"},
+		Imputation: Imputation{Strategy: ImputeDrop},
+	})
+
+	expected := trainer.Data{{Data: []float64{1}, Truth: []float64{0}}}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadJSONL_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		line   int
+		column string
+	}{
+		{name: "invalid json", in: "{\"a\": 1, \"y\": 0}\n{\"a\": \n", line: 2},
+		{name: "string feature", in: "{\"a\": 1, \"y\": 0}\n\n{\"a\": \"1\", \"y\": 0}\n", line: 3, column: "a"},
+		{name: "missing feature", in: "{\"y\": 0}\n", line: 1, column: "a"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadJSONL(strings.NewReader(test.in), JSONLConfig{Features: []string{"a"}, Targets: []string{"y"}})
+
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a parse error, got %v", err)
+			}
+			if pe.Line != test.line || pe.Column != test.column {
+				t.Fatalf("expected error at line %v column %q, got %v", test.line, test.column, pe)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package dataset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// LIBSVMConfig configures ReadLIBSVM.
+type LIBSVMConfig struct {
+	// NumFeatures is the number of inputs of each datum. Defaults to the
+	// largest feature index found in the file.
+	NumFeatures int
+	// MaxFeatures bounds the number of inputs inferred when NumFeatures is 0,
+	// since every datum holds a value for every feature, so a single large
+	// feature index would otherwise allocate that many values per datum.
+	// Defaults to DefaultMaxLIBSVMFeatures.
+	MaxFeatures int
+	// ZeroBased indicates that feature indices start at 0 rather than 1.
+	ZeroBased bool
+	// TargetClasses, when set, encodes the label of each line against these
+	// classes instead of parsing it as a number. A label holding several
+	// comma separated classes, as used for multi-label data, sets each of
+	// them.
+	TargetClasses []string
+}
+
+// DefaultMaxLIBSVMFeatures is the default MaxFeatures of a LIBSVMConfig.
+const DefaultMaxLIBSVMFeatures = 1 << 16
+
+// ReadLIBSVM reads r in the sparse LIBSVM format into trainer.Data according to
+// c. Each line holds a label followed by index:value pairs for every non-zero
+// feature, such as
+//
+//	+1 1:0.5 3:-2 # comment
+//
+// Features which are not listed are 0 rather than missing, so no imputation
+// is needed. Blank lines and lines starting with # are skipped.
+func ReadLIBSVM(r io.Reader, c LIBSVMConfig) (trainer.Data, error) {
+	if c.NumFeatures < 0 {
+		return nil, fmt.Errorf("number of features must not be negative, got %v", c.NumFeatures)
+	}
+	if c.MaxFeatures < 0 {
+		return nil, fmt.Errorf("maximum number of features must not be negative, got %v", c.MaxFeatures)
+	}
+	if c.MaxFeatures == 0 {
+		c.MaxFeatures = DefaultMaxLIBSVMFeatures
+	}
+	first := 1
+	if c.ZeroBased {
+		first = 0
+	}
+
+	type feature struct {
+		index int
+		value float64
+	}
+	var (
+		rows        [][]feature
+		truths      [][]float64
+		numFeatures = c.NumFeatures
+	)
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<26)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var truth []float64
+		if len(c.TargetClasses) > 0 {
+			truth = make([]float64, len(c.TargetClasses))
+			for _, class := range strings.Split(fields[0], ",") {
+				tv, err := encodeClass(c.TargetClasses, class)
+				if err != nil {
+					return nil, &ParseError{Line: line, Column: "label", Err: err}
+				}
+				for i := range tv {
+					truth[i] += tv[i]
+				}
+			}
+		} else {
+			v, err := parseFloat(fields[0])
+			if err == nil && math.IsNaN(v) {
+				err = errors.New("missing label")
+			}
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: "label", Err: err}
+			}
+			truth = []float64{v}
+		}
+
+		row := make([]feature, 0, len(fields)-1)
+		seen := make(map[int]bool, len(fields)-1)
+		for _, field := range fields[1:] {
+			i := strings.IndexByte(field, ':')
+			if i < 0 {
+				return nil, &ParseError{Line: line, Err: fmt.Errorf("expected index:value, got %q", field)}
+			}
+			index, err := strconv.Atoi(field[:i])
+			if err != nil || index < first {
+				return nil, &ParseError{Line: line, Err: fmt.Errorf("invalid feature index %q", field[:i])}
+			}
+			if seen[index] {
+				return nil, &ParseError{Line: line, Column: field[:i], Err: errors.New("duplicate feature index")}
+			}
+			seen[index] = true
+			value, err := parseFloat(field[i+1:])
+			if err == nil && math.IsNaN(value) {
+				err = errors.New("missing value")
+			}
+			if err != nil {
+				return nil, &ParseError{Line: line, Column: field[:i], Err: err}
+			}
+
+			index -= first
+			if c.NumFeatures > 0 && index >= c.NumFeatures {
+				return nil, &ParseError{Line: line, Column: field[:i], Err: fmt.Errorf("feature index out of range for %v features", c.NumFeatures)}
+			}
+			if c.NumFeatures == 0 && index >= c.MaxFeatures {
+				return nil, &ParseError{Line: line, Column: field[:i], Err: fmt.Errorf("feature index exceeds the maximum of %v features, set NumFeatures or MaxFeatures to read it", c.MaxFeatures)}
+			}
+			if index >= numFeatures {
+				numFeatures = index + 1
+			}
+			row = append(row, feature{index: index, value: value})
+		}
+
+		rows = append(rows, row)
+		truths = append(truths, truth)
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading libsvm")
+	}
+
+	d := make(trainer.Data, len(rows))
+	for ri, row := range rows {
+		d[ri] = trainer.Datum{Data: make([]float64, numFeatures), Truth: truths[ri]}
+		for _, f := range row {
+			d[ri].Data[f.index] = f.value
+		}
+	}
+	return d, nil
+}
+
+// MustReadLIBSVM calls ReadLIBSVM but panics if an error is encountered.
+func MustReadLIBSVM(r io.Reader, c LIBSVMConfig) trainer.Data {
+	d, err := ReadLIBSVM(r, c)
+	if err != nil {
+		panic(errors.Wrap(err, "must read libsvm"))
+	}
+	return d
+}
@@ -0,0 +1,88 @@
+package dataset
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+func Test_ReadLIBSVM(t *testing.T) {
+	in := `# comment
++1 1:0.5 3:-2 # trailing comment
+
+-1 2:1
+`
+	d := MustReadLIBSVM(strings.NewReader(in), LIBSVMConfig{})
+
+	expected := trainer.Data{
+		{Data: []float64{0.5, 0, -2}, Truth: []float64{1}},
+		{Data: []float64{0, 1, 0}, Truth: []float64{-1}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadLIBSVM_Classes(t *testing.T) {
+	in := "0 0:1\n1,2 1:1\n"
+	d := MustReadLIBSVM(strings.NewReader(in), LIBSVMConfig{
+		NumFeatures:   4,
+		ZeroBased:     true,
+		TargetClasses: []string{"0", "1", "2"},
+	})
+
+	expected := trainer.Data{
+		{Data: []float64{1, 0, 0, 0}, Truth: []float64{1, 0, 0}},
+		{Data: []float64{0, 1, 0, 0}, Truth: []float64{0, 1, 1}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+}
+
+func Test_ReadLIBSVM_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		line   int
+		column string
+	}{
+		{name: "invalid label", in: "1 1:1\nx 1:1\n", line: 2, column: "label"},
+		{name: "missing colon", in: "1 1:1\n\n1 2\n", line: 3},
+		{name: "zero index", in: "1 0:1\n", line: 1},
+		{name: "duplicate index", in: "1 1:1 1:2\n", line: 1, column: "1"},
+		{name: "out of range", in: "1 1:1\n1 3:1\n", line: 2, column: "3"},
+		{name: "invalid value", in: "1 1:abc\n", line: 1, column: "1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadLIBSVM(strings.NewReader(test.in), LIBSVMConfig{NumFeatures: 2})
+
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a parse error, got %v", err)
+			}
+			if pe.Line != test.line || pe.Column != test.column {
+				t.Fatalf("expected error at line %v column %q, got %v", test.line, test.column, pe)
+			}
+		})
+	}
+}
+
+func Test_ReadLIBSVM_MaxFeatures(t *testing.T) {
+	var pe *ParseError
+	if _, err := ReadLIBSVM(strings.NewReader("1 1:1\n1 100000000:1\n"), LIBSVMConfig{}); !errors.As(err, &pe) || pe.Line != 2 {
+		t.Fatalf("expected a parse error at line 2 for a feature index beyond the default maximum, got %v", err)
+	}
+	if _, err := ReadLIBSVM(strings.NewReader("1 4:1\n"), LIBSVMConfig{MaxFeatures: 3}); err == nil {
+		t.Fatalf("expected an error for a feature index beyond the maximum")
+	}
+
+	d := MustReadLIBSVM(strings.NewReader("1 3:1\n"), LIBSVMConfig{MaxFeatures: 3})
+	if len(d[0].Data) != 3 {
+		t.Fatalf("expected 3 features, got %v", len(d[0].Data))
+	}
+}
@@ -0,0 +1,380 @@
+// Package evolve trains networks via a genetic algorithm rather than gradient
+// descent. A population of networks is scored by a fitness function, and each
+// generation is bred from the fittest networks of the last, so neither the
+// fitness function nor the activation functions of the networks need to be
+// differentiable, and no truth vectors are needed.
+package evolve
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// FitnessFunc scores how well nw performs, where higher is better. When
+// Configuration.Workers is greater than 1, it is called concurrently for
+// different networks.
+type FitnessFunc func(nw network.Network) (float64, error)
+
+// Configuration defines how an Evolver breeds networks.
+type Configuration struct {
+	// PopulationSize is the number of networks in every generation. Must be at
+	// least 2.
+	PopulationSize int
+	// Generations is the number of generations bred before evolution ends.
+	Generations int
+	// TournamentSize is the number of networks drawn at random from the
+	// population to select each parent, of which the fittest is selected.
+	// Larger tournaments favor fitter networks more strongly. Must be in
+	// [1, PopulationSize].
+	TournamentSize int
+	// CrossoverRate is the probability that a child is bred by crossing over
+	// two parents rather than copying one. Must be in [0, 1].
+	CrossoverRate float64
+	// MutationRate is the probability that each weight and bias of a child is
+	// mutated. Must be in [0, 1].
+	MutationRate float64
+	// MutationStdDev is the standard deviation of the Gaussian noise added to
+	// a weight or bias when it is mutated. Must not be negative.
+	MutationStdDev float64
+	// Elitism is the number of the fittest networks of every generation which
+	// are carried into the next unchanged. Their fitness isn't evaluated again,
+	// so FitnessFunc should be deterministic if Elitism is used. Must be less
+	// than PopulationSize.
+	Elitism int
+	// FitnessThreshold, when not zero, ends evolution once any network's
+	// fitness reaches it.
+	FitnessThreshold float64
+	// Timeout ends evolution after this much time has passed. Setting to 0
+	// means there is no timeout.
+	Timeout time.Duration
+	// Workers is the maximum number of networks whose fitness is evaluated at
+	// once. Values less than 1 are treated as 1.
+	Workers int
+}
+
+// Individual is a member of a population along with its fitness.
+type Individual struct {
+	Network network.Network
+	Fitness float64
+}
+
+// GenerationStats summarizes the fitness of a generation's population.
+type GenerationStats struct {
+	Generation        int
+	Best, Mean, Worst float64
+}
+
+// Evolver evolves a population of networks towards maximizing a fitness
+// function.
+type Evolver struct {
+	Configuration
+	// Spec is the shape of every network of the initial population, which
+	// are created with random weights and biases via network.From.
+	Spec network.Spec
+	// Fitness scores every network.
+	Fitness FitnessFunc
+	Log     io.Writer
+
+	// Population is the current population, fittest first once a generation
+	// has been evaluated. If it is set before Evolve is called, evolution
+	// continues from it rather than from networks created from Spec, in which
+	// case it must hold exactly PopulationSize Individuals.
+	Population []Individual
+	// History holds the GenerationStats of every generation evaluated.
+	History []GenerationStats
+}
+
+// New creates an Evolver which evolves networks shaped like spec to maximize
+// fitness. Providing nil for log results in logs being written to stdout.
+func New(c Configuration, spec network.Spec, fitness FitnessFunc, log io.Writer) Evolver {
+	if log == nil {
+		log = os.Stdout
+	}
+	return Evolver{
+		Configuration: c,
+		Spec:          spec,
+		Fitness:       fitness,
+		Log:           log,
+	}
+}
+
+// ErrTimedOut is returned by Evolve when the Timeout of its Configuration is
+// reached.
+var ErrTimedOut = errors.New("evolution process timed out")
+
+func (c Configuration) validate() error {
+	if c.PopulationSize < 2 {
+		return fmt.Errorf("population size (%v) must be at least 2", c.PopulationSize)
+	}
+	if c.TournamentSize < 1 || c.TournamentSize > c.PopulationSize {
+		return fmt.Errorf("tournament size (%v) must be in [1, %v]", c.TournamentSize, c.PopulationSize)
+	}
+	if c.CrossoverRate < 0 || c.CrossoverRate > 1 {
+		return fmt.Errorf("crossover rate (%v) must be in [0, 1]", c.CrossoverRate)
+	}
+	if c.MutationRate < 0 || c.MutationRate > 1 {
+		return fmt.Errorf("mutation rate (%v) must be in [0, 1]", c.MutationRate)
+	}
+	if c.MutationStdDev < 0 {
+		return fmt.Errorf("mutation standard deviation (%v) must not be negative", c.MutationStdDev)
+	}
+	if c.Elitism < 0 || c.Elitism >= c.PopulationSize {
+		return fmt.Errorf("elitism (%v) must be in [0, %v)", c.Elitism, c.PopulationSize)
+	}
+	return nil
+}
+
+// Evolve breeds Generations generations of networks, then returns the fittest
+// network found. Evolution ends early, returning the fittest network so far,
+// if FitnessThreshold is reached, or with ErrTimedOut if Timeout is reached.
+func (e *Evolver) Evolve() (network.Network, error) {
+	if err := e.Configuration.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if e.Fitness == nil {
+		return nil, errors.New("must provide a fitness function")
+	}
+	if e.Log == nil {
+		e.Log = os.Stdout
+	}
+
+	var deadline time.Time
+	if e.Configuration.Timeout > 0 {
+		deadline = time.Now().Add(e.Configuration.Timeout)
+	}
+
+	if n := len(e.Population); n != 0 && n != e.Configuration.PopulationSize {
+		return nil, fmt.Errorf("population of %v individuals does not match population size (%v)", n, e.Configuration.PopulationSize)
+	}
+	if len(e.Population) == 0 {
+		e.Population = make([]Individual, e.Configuration.PopulationSize)
+		for i := range e.Population {
+			nw, err := network.From(e.Spec)
+			if err != nil {
+				return nil, fmt.Errorf("creating initial population: %w", err)
+			}
+			e.Population[i] = Individual{Network: nw}
+		}
+	}
+	// NOTE: Fitness is only evaluated for Individuals whose fitness is NaN, so
+	// the fitness of a population carried over from elsewhere is discarded.
+	for i := range e.Population {
+		e.Population[i].Fitness = math.NaN()
+	}
+
+	_, _ = fmt.Fprintln(e.Log, "Starting evolution process...")
+
+	for g := 0; ; g++ {
+		if err := e.evaluate(); err != nil {
+			return nil, fmt.Errorf("evaluating generation %v: %w", g, err)
+		}
+		e.record(g)
+
+		best := e.Population[0]
+		if e.Configuration.FitnessThreshold != 0 && best.Fitness >= e.Configuration.FitnessThreshold {
+			_, _ = fmt.Fprintln(e.Log, "Reached fitness threshold, ending evolution process...")
+			return best.Network, nil
+		}
+		if g >= e.Configuration.Generations {
+			_, _ = fmt.Fprintln(e.Log, "Reached maximum generations, ending evolution process...")
+			return best.Network, nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return best.Network, ErrTimedOut
+		}
+
+		next, err := e.breed()
+		if err != nil {
+			return nil, fmt.Errorf("breeding generation %v: %w", g+1, err)
+		}
+		e.Population = next
+	}
+}
+
+// MustEvolve calls Evolve but panics if an error is encountered.
+func (e *Evolver) MustEvolve() network.Network {
+	nw, err := e.Evolve()
+	if err != nil {
+		panic(errors.Wrap(err, "must evolve"))
+	}
+	return nw
+}
+
+// evaluate scores every Individual of the population whose fitness is unknown,
+// then sorts the population fittest first. A NaN fitness is treated as the
+// worst possible.
+func (e *Evolver) evaluate() error {
+	workers := e.Configuration.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, len(e.Population))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range e.Population {
+		if !math.IsNaN(e.Population[i].Fitness) {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			f, err := e.Fitness(e.Population[i].Network)
+			if err != nil {
+				errs[i] = fmt.Errorf("network %v: %w", i, err)
+				return
+			}
+			if math.IsNaN(f) {
+				f = math.Inf(-1)
+			}
+			e.Population[i].Fitness = f
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(e.Population, func(a, b int) bool {
+		return e.Population[a].Fitness > e.Population[b].Fitness
+	})
+	return nil
+}
+
+// record appends the GenerationStats of the sorted population to History and
+// logs them.
+func (e *Evolver) record(g int) {
+	s := GenerationStats{
+		Generation: g,
+		Best:       e.Population[0].Fitness,
+		Worst:      e.Population[len(e.Population)-1].Fitness,
+	}
+	for _, ind := range e.Population {
+		s.Mean += ind.Fitness
+	}
+	s.Mean /= float64(len(e.Population))
+
+	e.History = append(e.History, s)
+	_, _ = fmt.Fprintf(e.Log, "Generation %v: best %.6f mean %.6f worst %.6f\n", g, s.Best, s.Mean, s.Worst)
+}
+
+// breed returns the next generation of the sorted population: its elites,
+// followed by children of parents chosen by tournament selection.
+func (e *Evolver) breed() ([]Individual, error) {
+	c := e.Configuration
+	next := make([]Individual, 0, c.PopulationSize)
+	next = append(next, e.Population[:c.Elitism]...)
+
+	for len(next) < c.PopulationSize {
+		p1 := e.tournament()
+		var child network.Network
+		if rand.Float64() < c.CrossoverRate {
+			p2 := e.tournament()
+			var err error
+			child, err = Crossover(p1, p2)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			child = p1.Clone()
+		}
+		Mutate(child, c.MutationRate, c.MutationStdDev)
+		next = append(next, Individual{Network: child, Fitness: math.NaN()})
+	}
+	return next, nil
+}
+
+// tournament returns the fittest of TournamentSize networks drawn at random
+// from the sorted population. Since the population is sorted, that is the one
+// with the smallest index.
+func (e *Evolver) tournament() network.Network {
+	best := len(e.Population)
+	for i := 0; i < e.Configuration.TournamentSize; i++ {
+		if r := rand.Intn(len(e.Population)); r < best {
+			best = r
+		}
+	}
+	return e.Population[best].Network
+}
+
+// Crossover returns a new network shaped like a and b, whose every weight and
+// bias is taken from either a or b with equal probability. Everything else,
+// such as labels, activation functions and the unused biases of the input
+// layer, is taken from a.
+//
+// If a and b are not shaped the same, an error is returned.
+func Crossover(a, b network.Network) (network.Network, error) {
+	if err := sameShape(a, b); err != nil {
+		return nil, fmt.Errorf("cannot cross over networks: %w", err)
+	}
+
+	ps, ps2 := a.Parameters(), b.Parameters()
+	for i := range ps {
+		if rand.Intn(2) == 0 {
+			ps[i] = ps2[i]
+		}
+	}
+
+	child := a.Clone()
+	if err := child.SetParameters(ps); err != nil {
+		return nil, err
+	}
+	return child, nil
+}
+
+// MustCrossover calls Crossover but panics if an error is encountered.
+func MustCrossover(a, b network.Network) network.Network {
+	child, err := Crossover(a, b)
+	if err != nil {
+		panic(errors.Wrap(err, "must crossover"))
+	}
+	return child
+}
+
+// Mutate adds Gaussian noise with a standard deviation of stdDev to each weight
+// and bias of nw, as returned by Network.Parameters, with probability rate.
+func Mutate(nw network.Network, rate, stdDev float64) {
+	ps := nw.Parameters()
+	for i := range ps {
+		if rand.Float64() < rate {
+			ps[i] += rand.NormFloat64() * stdDev
+		}
+	}
+	// NOTE: ps came from nw, so it can't fail to fit it.
+	nw.MustSetParameters(ps)
+}
+
+func sameShape(a, b network.Network) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("networks have different numbers of layers, %v != %v", len(a), len(b))
+	}
+	for li := range a {
+		if len(a[li]) != len(b[li]) {
+			return fmt.Errorf("networks have different numbers of neurons in layer %v, %v != %v", li, len(a[li]), len(b[li]))
+		}
+		for ni := range a[li] {
+			if len(a[li][ni].Connections) != len(b[li][ni].Connections) {
+				return fmt.Errorf("networks have different numbers of connections in layer %v neuron %v, %v != %v", li, ni, len(a[li][ni].Connections), len(b[li][ni].Connections))
+			}
+		}
+	}
+	return nil
+}
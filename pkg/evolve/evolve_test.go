@@ -0,0 +1,188 @@
+package evolve
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// line scores a network by how closely it fits y = 2x + 1.
+func line(nw network.Network) (float64, error) {
+	loss := 0.0
+	for _, x := range []float64{-1, -0.5, 0, 0.5, 1} {
+		vs, err := nw.PredictVector([]float64{x})
+		if err != nil {
+			return 0, err
+		}
+		loss += (vs[0] - (2*x + 1)) * (vs[0] - (2*x + 1))
+	}
+	return -loss, nil
+}
+
+func Test_Evolve(t *testing.T) {
+	spec := network.Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"x"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	}
+	e := New(Configuration{
+		PopulationSize: 50,
+		Generations:    200,
+		TournamentSize: 3,
+		CrossoverRate:  0.5,
+		MutationRate:   0.5,
+		MutationStdDev: 0.1,
+		Elitism:        2,
+		Workers:        4,
+	}, spec, line, io.Discard)
+
+	nw, err := e.Evolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fitness, _ := line(nw)
+	if fitness < -0.01 {
+		t.Fatalf("expected evolution to fit the line, got fitness %v with weight %v and bias %v", fitness, nw.ConnectionWeights()[1][0][0], nw.NeuronBiases()[1][0])
+	}
+	if len(e.History) != 201 || e.Population[0].Network == nil {
+		t.Fatalf("expected stats for 201 generations, got %v", len(e.History))
+	}
+	// With elitism, the best fitness never decreases.
+	for g := 1; g < len(e.History); g++ {
+		if e.History[g].Best < e.History[g-1].Best {
+			t.Fatalf("best fitness decreased from %v to %v at generation %v", e.History[g-1].Best, e.History[g].Best, g)
+		}
+	}
+}
+
+func Test_Evolve_FitnessThreshold(t *testing.T) {
+	spec := network.Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"x"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	}
+	e := New(Configuration{
+		PopulationSize:   20,
+		Generations:      1000,
+		TournamentSize:   2,
+		MutationRate:     1,
+		MutationStdDev:   0.2,
+		Elitism:          1,
+		FitnessThreshold: -0.5,
+	}, spec, line, io.Discard)
+
+	nw := e.MustEvolve()
+	if fitness, _ := line(nw); fitness < -0.5 {
+		t.Fatalf("expected fitness of at least -0.5, got %v", fitness)
+	}
+	if len(e.History) > 1000 {
+		t.Fatalf("expected evolution to end at the threshold, got %v generations", len(e.History))
+	}
+}
+
+func Test_Evolve_InvalidConfiguration(t *testing.T) {
+	spec := network.Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"x"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	}
+	tests := []Configuration{
+		{PopulationSize: 1, TournamentSize: 1},
+		{PopulationSize: 10, TournamentSize: 11},
+		{PopulationSize: 10, TournamentSize: 2, MutationRate: 2},
+		{PopulationSize: 10, TournamentSize: 2, Elitism: 10},
+	}
+	for i, c := range tests {
+		e := New(c, spec, line, io.Discard)
+		if _, err := e.Evolve(); err == nil {
+			t.Errorf("expected an error for configuration %v", i)
+		}
+	}
+}
+
+func Test_Evolve_PresetPopulation(t *testing.T) {
+	spec := network.Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"x"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	}
+	c := Configuration{PopulationSize: 10, TournamentSize: 2, Elitism: 5, Generations: 1}
+
+	e := New(c, spec, line, io.Discard)
+	e.Population = []Individual{{Network: network.MustFrom(spec)}, {Network: network.MustFrom(spec)}}
+	if _, err := e.Evolve(); err == nil {
+		t.Fatal("expected an error for a population smaller than the population size")
+	}
+
+	e.Population = nil
+	for i := 0; i < c.PopulationSize; i++ {
+		e.Population = append(e.Population, Individual{Network: network.MustFrom(spec)})
+	}
+	if _, err := e.Evolve(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Crossover(t *testing.T) {
+	spec := network.Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"x"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	}
+	a, b := network.MustFrom(spec), network.MustFrom(spec)
+	child := MustCrossover(a, b)
+
+	pa, pb, pc := a.Parameters(), b.Parameters(), child.Parameters()
+	for i := range pc {
+		if pc[i] != pa[i] && pc[i] != pb[i] {
+			t.Fatalf("parameter %v of child (%v) came from neither parent (%v, %v)", i, pc[i], pa[i], pb[i])
+		}
+	}
+
+	wide := network.MustFrom(network.Spec{
+		NeuronMap:              []int{1, 2},
+		OutputLabels:           []string{"y", "z"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	if _, err := Crossover(a, wide); err == nil {
+		t.Fatal("expected an error for networks of different shapes")
+	}
+}
+
+func Test_Mutate(t *testing.T) {
+	spec := network.Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"x"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	}
+	nw := network.MustFrom(spec)
+	ps := nw.Parameters()
+
+	Mutate(nw, 0, 1)
+	for i, p := range nw.Parameters() {
+		if p != ps[i] {
+			t.Fatal("expected a rate of 0 to leave the network unchanged")
+		}
+	}
+
+	Mutate(nw, 1, 1)
+	changed := 0
+	for i, p := range nw.Parameters() {
+		if math.Abs(p-ps[i]) > 0 {
+			changed++
+		}
+	}
+	if changed != len(ps) {
+		t.Fatalf("expected a rate of 1 to change all %v parameters, changed %v", len(ps), changed)
+	}
+}
@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Confusion is a confusion matrix, counting how often each class of the data a
+// network was evaluated on was predicted as each class.
+type Confusion struct {
+	// Labels names every class, index-wise, from the output labels of the
+	// network.
+	Labels []string
+	// Counts[i][j] is the number of datums of class i which were predicted as
+	// class j.
+	Counts [][]int
+}
+
+// ConfusionMatrix returns the Confusion of nw on d.
+func ConfusionMatrix(nw network.Network, d trainer.Data) (Confusion, error) {
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return Confusion{}, err
+	}
+
+	labels := nw.Spec().OutputLabels
+	c := Confusion{
+		Labels: append([]string(nil), labels...),
+		Counts: make([][]int, len(labels)),
+	}
+	for i := range c.Counts {
+		c.Counts[i] = make([]int, len(labels))
+	}
+	for i, out := range outs {
+		c.Counts[argmax(d[i].Truth)][argmax(out)]++
+	}
+	return c, nil
+}
+
+// MustConfusionMatrix calls ConfusionMatrix but panics if an error is
+// encountered.
+func MustConfusionMatrix(nw network.Network, d trainer.Data) Confusion {
+	c, err := ConfusionMatrix(nw, d)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Total returns the number of datums counted by c.
+func (c Confusion) Total() int {
+	total := 0
+	for i := range c.Counts {
+		for j := range c.Counts[i] {
+			total += c.Counts[i][j]
+		}
+	}
+	return total
+}
+
+// Accuracy returns the fraction of datums counted by c which were predicted as
+// their own class.
+func (c Confusion) Accuracy() float64 {
+	correct := 0
+	for i := range c.Counts {
+		correct += c.Counts[i][i]
+	}
+	return ratio(correct, c.Total())
+}
+
+// Support returns the number of datums of class i.
+func (c Confusion) Support(i int) int {
+	support := 0
+	for _, n := range c.Counts[i] {
+		support += n
+	}
+	return support
+}
+
+// predicted returns the number of datums predicted as class i.
+func (c Confusion) predicted(i int) int {
+	predicted := 0
+	for j := range c.Counts {
+		predicted += c.Counts[j][i]
+	}
+	return predicted
+}
+
+// Precision returns the fraction of datums predicted as class i which are of
+// class i, or 0 if none were predicted as class i.
+func (c Confusion) Precision(i int) float64 {
+	return ratio(c.Counts[i][i], c.predicted(i))
+}
+
+// Recall returns the fraction of datums of class i which were predicted as
+// class i, or 0 if there are none of class i.
+func (c Confusion) Recall(i int) float64 {
+	return ratio(c.Counts[i][i], c.Support(i))
+}
+
+// F1 returns the harmonic mean of the Precision and Recall of class i.
+func (c Confusion) F1(i int) float64 {
+	return f1(c.Precision(i), c.Recall(i))
+}
+
+// MacroPrecision returns the Precision of every class, averaged.
+func (c Confusion) MacroPrecision() float64 {
+	return c.macro(c.Precision)
+}
+
+// MacroRecall returns the Recall of every class, averaged.
+func (c Confusion) MacroRecall() float64 {
+	return c.macro(c.Recall)
+}
+
+// MacroF1 returns the F1 of every class, averaged.
+func (c Confusion) MacroF1() float64 {
+	return c.macro(c.F1)
+}
+
+func (c Confusion) macro(f func(int) float64) float64 {
+	if len(c.Counts) == 0 {
+		return 0
+	}
+	total := 0.0
+	for i := range c.Counts {
+		total += f(i)
+	}
+	return total / float64(len(c.Counts))
+}
+
+// MicroPrecision returns the precision of every datum pooled together. Since
+// every datum is predicted as exactly one class, it equals Accuracy.
+func (c Confusion) MicroPrecision() float64 {
+	return c.Accuracy()
+}
+
+// MicroRecall returns the recall of every datum pooled together. Since every
+// datum is of exactly one class, it equals Accuracy.
+func (c Confusion) MicroRecall() float64 {
+	return c.Accuracy()
+}
+
+// MicroF1 returns the harmonic mean of MicroPrecision and MicroRecall, which
+// equals Accuracy.
+func (c Confusion) MicroF1() float64 {
+	return f1(c.MicroPrecision(), c.MicroRecall())
+}
+
+// String formats c as a table with a row per class of the data and a column
+// per predicted class.
+func (c Confusion) String() string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprint(tw, "truth \\ predicted\t")
+	for _, l := range c.Labels {
+		_, _ = fmt.Fprintf(tw, "%v\t", l)
+	}
+	_, _ = fmt.Fprintln(tw)
+	for i, row := range c.Counts {
+		_, _ = fmt.Fprintf(tw, "%v\t", c.Labels[i])
+		for _, n := range row {
+			_, _ = fmt.Fprintf(tw, "%v\t", n)
+		}
+		_, _ = fmt.Fprintln(tw)
+	}
+	_ = tw.Flush()
+	return sb.String()
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+func f1(precision, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Func evaluates a Network on trainer.Data.
+type Func func(nw network.Network, d trainer.Data) (float64, error)
+
+type metric struct {
+	name string
+	fn   Func
+}
+
+// NewMetric returns a trainer.Metric named name which evaluates fn, so that it
+// can be tracked during training.
+func NewMetric(name string, fn Func) trainer.Metric {
+	return metric{name: name, fn: fn}
+}
+
+func (m metric) Name() string {
+	return m.name
+}
+
+func (m metric) Evaluate(nw network.Network, d trainer.Data) (float64, error) {
+	return m.fn(nw, d)
+}
+
+// Metrics which can be tracked by a trainer.Trainer.
+var (
+	AccuracyMetric = NewMetric("accuracy", Accuracy)
+	MacroF1Metric  = NewMetric("macroF1", func(nw network.Network, d trainer.Data) (float64, error) {
+		c, err := ConfusionMatrix(nw, d)
+		if err != nil {
+			return 0, err
+		}
+		return c.MacroF1(), nil
+	})
+	LogLossMetric = NewMetric("logLoss", func(nw network.Network, d trainer.Data) (float64, error) {
+		return LogLoss(nw, d, nil)
+	})
+	ROCAUCMetric = NewMetric("rocAUC", ROCAUC)
+	MAEMetric    = NewMetric("mae", MAE)
+	RMSEMetric   = NewMetric("rmse", RMSE)
+	R2Metric     = NewMetric("r2", R2)
+)
+
+// TopKAccuracyMetric returns a trainer.Metric evaluating TopKAccuracy with k.
+func TopKAccuracyMetric(k int) trainer.Metric {
+	return NewMetric(fmt.Sprintf("top%vAccuracy", k), func(nw network.Network, d trainer.Data) (float64, error) {
+		return TopKAccuracy(nw, d, k)
+	})
+}
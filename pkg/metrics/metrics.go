@@ -0,0 +1,257 @@
+// Package metrics evaluates how well a Network performs on trainer.Data, for
+// both classification and regression.
+//
+// Every metric feeds the Data of each datum directly into the network's input
+// layer via Network.OutputVector, the same as training does, so data which
+// was preprocessed for training should be evaluated as is.
+//
+// For classification, the class of a datum is the index of the largest value
+// of its Truth, and the class predicted for it is the index of the network's
+// largest output.
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// outputs returns the outputs of nw for every datum in d.
+func outputs(nw network.Network, d trainer.Data) ([][]float64, error) {
+	if len(d) == 0 {
+		return nil, errors.New("can't evaluate on empty data")
+	}
+	outs := make([][]float64, len(d))
+	for i, td := range d {
+		out, err := nw.OutputVector(td.Data)
+		if err != nil {
+			return nil, fmt.Errorf("datum %v: %w", i, err)
+		}
+		if len(out) != len(td.Truth) {
+			return nil, fmt.Errorf("datum %v: truth has %v values, but network has %v outputs", i, len(td.Truth), len(out))
+		}
+		outs[i] = out
+	}
+	return outs, nil
+}
+
+// argmax returns the index of the largest value of vs, preferring the first on
+// ties.
+func argmax(vs []float64) int {
+	best := 0
+	for i, v := range vs {
+		if v > vs[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// Accuracy returns the fraction of d whose class is predicted correctly by nw.
+func Accuracy(nw network.Network, d trainer.Data) (float64, error) {
+	return TopKAccuracy(nw, d, 1)
+}
+
+// TopKAccuracy returns the fraction of d whose class is one of the k largest
+// outputs of nw.
+func TopKAccuracy(nw network.Network, d trainer.Data, k int) (float64, error) {
+	if k < 1 {
+		return 0, fmt.Errorf("k must be at least 1 (requested %v)", k)
+	}
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return 0, err
+	}
+
+	correct := 0
+	for i, out := range outs {
+		class := argmax(d[i].Truth)
+		// NOTE: The class is in the top k if fewer than k outputs beat it,
+		// with ties broken by index the same as argmax.
+		better := 0
+		for j, v := range out {
+			if v > out[class] || (v == out[class] && j < class) {
+				better++
+			}
+		}
+		if better < k {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(outs)), nil
+}
+
+// ProbabilityFunc converts the outputs of a network into probabilities.
+type ProbabilityFunc func(outputs []float64) []float64
+
+// Softmax converts outputs into probabilities which sum to 1.
+func Softmax(outputs []float64) []float64 {
+	max := outputs[argmax(outputs)]
+	ps := make([]float64, len(outputs))
+	sum := 0.0
+	for i, o := range outputs {
+		ps[i] = math.Exp(o - max)
+		sum += ps[i]
+	}
+	for i := range ps {
+		ps[i] /= sum
+	}
+	return ps
+}
+
+// Rescale converts outputs in (-1, 1), the range of jnet's sigmoid activation
+// function, into probabilities in (0, 1). If there is more than one output they
+// are then normalized to sum to 1.
+func Rescale(outputs []float64) []float64 {
+	ps := make([]float64, len(outputs))
+	sum := 0.0
+	for i, o := range outputs {
+		ps[i] = (o + 1) / 2
+		sum += ps[i]
+	}
+	if len(ps) > 1 && sum > 0 {
+		for i := range ps {
+			ps[i] /= sum
+		}
+	}
+	return ps
+}
+
+// logLossEpsilon bounds probabilities away from 0 and 1 so that log-loss stays
+// finite.
+const logLossEpsilon = 1e-15
+
+// LogLoss returns the mean cross-entropy between the Truth of d and the outputs
+// of nw, converted into probabilities by toProbabilities.
+//
+// If nw has a single output, it scores the positive class and the Truth of
+// each datum must be 0 or 1. Since a single output can't be normalized by
+// Softmax, toProbabilities then defaults to Rescale if the output neuron's
+// activation function is sigmoid or tanh, whose range is (-1, 1), and to
+// taking the output as a probability as is otherwise. If nw has several
+// outputs, the Truth of each datum is a one-hot class and toProbabilities
+// defaults to Softmax.
+func LogLoss(nw network.Network, d trainer.Data, toProbabilities ProbabilityFunc) (float64, error) {
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return 0, err
+	}
+
+	if toProbabilities == nil && len(outs[0]) == 1 {
+		switch nw.LastLayer()[0].ActivationFunctionName {
+		case activationfunction.NameSigmoid, activationfunction.NameTanh:
+			toProbabilities = Rescale
+		default:
+			toProbabilities = func(outputs []float64) []float64 { return outputs }
+		}
+	}
+
+	clamp := func(p float64) float64 {
+		return math.Max(logLossEpsilon, math.Min(1-logLossEpsilon, p))
+	}
+
+	loss := 0.0
+	for i, out := range outs {
+		var ps []float64
+		switch {
+		case toProbabilities != nil:
+			ps = toProbabilities(out)
+		default:
+			ps = Softmax(out)
+		}
+
+		if len(ps) == 1 {
+			y, p := d[i].Truth[0], clamp(ps[0])
+			loss -= y*math.Log(p) + (1-y)*math.Log(1-p)
+			continue
+		}
+		loss -= math.Log(clamp(ps[argmax(d[i].Truth)]))
+	}
+	return loss / float64(len(outs)), nil
+}
+
+// ROCAUC returns the area under the receiver operating characteristic curve of
+// the outputs of nw on d, which is the probability that a random positive datum
+// is scored above a random negative one.
+//
+// If nw has a single output, it scores the positive class and a datum is
+// positive if its Truth is at least 0.5. Otherwise the one-vs-rest ROC-AUC of
+// every class is averaged, skipping classes which d has no positive or no
+// negative datums of.
+func ROCAUC(nw network.Network, d trainer.Data) (float64, error) {
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return 0, err
+	}
+
+	scores := make([]float64, len(outs))
+	positives := make([]bool, len(outs))
+	if len(outs[0]) == 1 {
+		for i, out := range outs {
+			scores[i], positives[i] = out[0], d[i].Truth[0] >= 0.5
+		}
+		auc, ok := rocAUC(scores, positives)
+		if !ok {
+			return 0, errors.New("can't compute roc auc without both positive and negative datums")
+		}
+		return auc, nil
+	}
+
+	total, classes := 0.0, 0
+	for c := range outs[0] {
+		for i, out := range outs {
+			scores[i], positives[i] = out[c], argmax(d[i].Truth) == c
+		}
+		if auc, ok := rocAUC(scores, positives); ok {
+			total += auc
+			classes++
+		}
+	}
+	if classes == 0 {
+		return 0, errors.New("can't compute roc auc without both positive and negative datums of any class")
+	}
+	return total / float64(classes), nil
+}
+
+// rocAUC computes the ROC-AUC of scores via the Mann-Whitney U statistic, with
+// tied scores sharing their average rank. It returns false if there are no
+// positives or no negatives.
+func rocAUC(scores []float64, positives []bool) (float64, bool) {
+	idx := make([]int, len(scores))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool {
+		return scores[idx[a]] < scores[idx[b]]
+	})
+
+	rankSum, nPos := 0.0, 0
+	for start := 0; start < len(idx); {
+		end := start
+		for end < len(idx) && scores[idx[end]] == scores[idx[start]] {
+			end++
+		}
+		// Ranks are 1-based, so the average rank of [start, end) is the
+		// midpoint of start+1 and end.
+		rank := float64(start+1+end) / 2
+		for _, i := range idx[start:end] {
+			if positives[i] {
+				rankSum += rank
+				nPos++
+			}
+		}
+		start = end
+	}
+
+	nNeg := len(scores) - nPos
+	if nPos == 0 || nNeg == 0 {
+		return 0, false
+	}
+	u := rankSum - float64(nPos*(nPos+1))/2
+	return u / float64(nPos*nNeg), true
+}
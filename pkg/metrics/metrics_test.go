@@ -0,0 +1,323 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// classes holds 3 classes, with outputs which are correct for datums 0, 1, 3
+// and 5. Tests evaluate it, like the rest of their data, on linear networks
+// with identity weights, whose outputs are their inputs.
+var classes = trainer.Data{
+	{Data: []float64{0.9, 0.1, 0.0}, Truth: []float64{1, 0, 0}},
+	{Data: []float64{0.2, 0.7, 0.1}, Truth: []float64{0, 1, 0}},
+	{Data: []float64{0.5, 0.4, 0.1}, Truth: []float64{0, 1, 0}},
+	{Data: []float64{0.1, 0.2, 0.7}, Truth: []float64{0, 0, 1}},
+	{Data: []float64{0.1, 0.6, 0.3}, Truth: []float64{0, 0, 1}},
+	{Data: []float64{0.0, 0.3, 0.6}, Truth: []float64{0, 0, 1}},
+}
+
+func Test_Accuracy(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 3},
+		OutputLabels:           []string{"a", "b", "c"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}, {}, {}}, {{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}})
+
+	acc, err := Accuracy(nw, classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(acc, 4.0/6) {
+		t.Fatalf("expected accuracy %v, got %v", 4.0/6, acc)
+	}
+
+	top2, err := TopKAccuracy(nw, classes, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(top2, 1) {
+		t.Fatalf("expected top 2 accuracy 1, got %v", top2)
+	}
+
+	if _, err := TopKAccuracy(nw, classes, 0); err == nil {
+		t.Fatal("expected an error for k of 0")
+	}
+	if _, err := Accuracy(nw, nil); err == nil {
+		t.Fatal("expected an error for empty data")
+	}
+	if _, err := Accuracy(nw, trainer.Data{{Data: []float64{1, 0, 0}, Truth: []float64{1}}}); err == nil {
+		t.Fatal("expected an error for a truth of the wrong size")
+	}
+}
+
+func Test_ConfusionMatrix(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 3},
+		OutputLabels:           []string{"a", "b", "c"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}, {}, {}}, {{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}})
+
+	c, err := ConfusionMatrix(nw, classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]int{
+		{1, 0, 0},
+		{1, 1, 0},
+		{0, 1, 2},
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if c.Counts[i][j] != expected[i][j] {
+				t.Fatalf("expected counts %v, got %v", expected, c.Counts)
+			}
+		}
+	}
+	if c.Labels[2] != "c" {
+		t.Fatalf("expected labels from the network, got %v", c.Labels)
+	}
+
+	tests := []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"precision of a", c.Precision(0), 0.5},
+		{"recall of a", c.Recall(0), 1},
+		{"f1 of a", c.F1(0), 2.0 / 3},
+		{"precision of c", c.Precision(2), 1},
+		{"recall of c", c.Recall(2), 2.0 / 3},
+		{"f1 of c", c.F1(2), 0.8},
+		{"macro precision", c.MacroPrecision(), (0.5 + 0.5 + 1) / 3},
+		{"macro recall", c.MacroRecall(), (1 + 0.5 + 2.0/3) / 3},
+		{"macro f1", c.MacroF1(), (2.0/3 + 0.5 + 0.8) / 3},
+		{"micro f1", c.MicroF1(), 4.0 / 6},
+		{"accuracy", c.Accuracy(), 4.0 / 6},
+	}
+	for _, test := range tests {
+		if !almostEqual(test.actual, test.expected) {
+			t.Errorf("expected %v of %v, got %v", test.name, test.expected, test.actual)
+		}
+	}
+	if c.Support(2) != 3 {
+		t.Errorf("expected support of c of 3, got %v", c.Support(2))
+	}
+}
+
+func Test_LogLoss(t *testing.T) {
+	// A single sigmoid output is rescaled from (-1, 1), which makes the
+	// probability the logistic function of the input.
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{1, 1},
+		OutputLabels:           []string{"p"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}}, {{1}}})
+	d := trainer.Data{
+		{Data: []float64{math.Log(4)}, Truth: []float64{1}},
+		{Data: []float64{math.Log(2.0 / 3)}, Truth: []float64{0}},
+	}
+
+	loss, err := LogLoss(nw, d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := -(math.Log(0.8) + math.Log(0.6)) / 2
+	if !almostEqual(loss, expected) {
+		t.Fatalf("expected log-loss %v, got %v", expected, loss)
+	}
+
+	// Any other single output is taken as a probability as is.
+	for _, afn := range []activationfunction.Name{activationfunction.NameLinear, activationfunction.NameRelu} {
+		nw[1].MustSetNeuronActivationFunctionsTo(afn)
+		d = trainer.Data{
+			{Data: []float64{0.8}, Truth: []float64{1}},
+			{Data: []float64{0.4}, Truth: []float64{0}},
+		}
+
+		loss, err = LogLoss(nw, d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(loss, expected) {
+			t.Fatalf("expected %v log-loss %v, got %v", afn, expected, loss)
+		}
+	}
+
+	nw = network.MustFrom(network.Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}, {}}, {{1, 0}, {0, 1}}})
+	d = trainer.Data{{Data: []float64{0, math.Log(3)}, Truth: []float64{0, 1}}}
+
+	loss, err = LogLoss(nw, d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := -math.Log(0.75); !almostEqual(loss, expected) {
+		t.Fatalf("expected softmax log-loss %v, got %v", expected, loss)
+	}
+
+	d = trainer.Data{{Data: []float64{-1, 1}, Truth: []float64{0, 1}}}
+	loss, err = LogLoss(nw, d, Rescale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := -math.Log(1 - logLossEpsilon); !almostEqual(loss, expected) {
+		t.Fatalf("expected rescaled log-loss %v, got %v", expected, loss)
+	}
+}
+
+func Test_ROCAUC(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{1, 1},
+		OutputLabels:           []string{"p"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}}, {{1}}})
+	// Of the 2*2 positive/negative pairs, 0.9 beats both negatives, and 0.4
+	// ties 0.4 and beats 0.1, for (2 + 1.5) / 4.
+	d := trainer.Data{
+		{Data: []float64{0.9}, Truth: []float64{1}},
+		{Data: []float64{0.4}, Truth: []float64{1}},
+		{Data: []float64{0.4}, Truth: []float64{0}},
+		{Data: []float64{0.1}, Truth: []float64{0}},
+	}
+
+	auc, err := ROCAUC(nw, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(auc, 0.875) {
+		t.Fatalf("expected roc auc 0.875, got %v", auc)
+	}
+
+	if _, err := ROCAUC(nw, d[:2]); err == nil {
+		t.Fatal("expected an error without negatives")
+	}
+
+	nw = network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 3},
+		OutputLabels:           []string{"a", "b", "c"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}, {}, {}}, {{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}})
+	auc, err = ROCAUC(nw, classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auc <= 0.5 || auc > 1 {
+		t.Fatalf("expected roc auc in (0.5, 1], got %v", auc)
+	}
+}
+
+func Test_Regression(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{1, 1},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}}, {{1}}})
+	d := trainer.Data{
+		{Data: []float64{1}, Truth: []float64{1}},
+		{Data: []float64{3}, Truth: []float64{2}},
+		{Data: []float64{1}, Truth: []float64{3}},
+	}
+
+	mae, err := MAE(nw, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(mae, 1) {
+		t.Fatalf("expected mae 1, got %v", mae)
+	}
+
+	rmse, err := RMSE(nw, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(rmse, math.Sqrt(5.0/3)) {
+		t.Fatalf("expected rmse %v, got %v", math.Sqrt(5.0/3), rmse)
+	}
+
+	r2, err := R2(nw, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The residual sum of squares is 5, and the total sum of squares is 2.
+	if !almostEqual(r2, 1-5.0/2) {
+		t.Fatalf("expected r2 %v, got %v", 1-5.0/2, r2)
+	}
+}
+
+func Test_Report(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 3},
+		OutputLabels:           []string{"a", "b", "c"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{{{}, {}, {}}, {{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}})
+
+	r, err := Report(nw, classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"truth \\ predicted", "precision", "macro avg", "micro avg", "accuracy: 0.6667", "log-loss", "roc-auc"} {
+		if !strings.Contains(r, s) {
+			t.Errorf("expected report to contain %q, got:\n%v", s, r)
+		}
+	}
+}
+
+func Test_Metric_Trainer(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{3, 3},
+		OutputLabels:           []string{"a", "b", "c"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+
+	tr := trainer.New(trainer.Configuration{
+		LearningRate:       0.01,
+		MiniBatchSize:      2,
+		MaxIterations:      6,
+		ValidationInterval: 3,
+	}, append(trainer.Data(nil), classes...), io.Discard)
+	tr.Validation = classes
+	tr.Metrics = []trainer.Metric{AccuracyMetric, TopKAccuracyMetric(2), MacroF1Metric}
+
+	if err := tr.Train(nw); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.History) != 2 || tr.History[1].Iteration != 5 {
+		t.Fatalf("expected records at iterations 2 and 5, got %v", tr.History)
+	}
+	for _, name := range []string{"accuracy", "top2Accuracy", "macroF1"} {
+		if _, found := tr.History[0].Values[name]; !found {
+			t.Fatalf("expected a value for %v, got %v", name, tr.History[0].Values)
+		}
+	}
+}
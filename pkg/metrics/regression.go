@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"math"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// MAE returns the mean absolute error between the outputs of nw and the Truth
+// of d, over every output of every datum.
+func MAE(nw network.Network, d trainer.Data) (float64, error) {
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return 0, err
+	}
+
+	total, n := 0.0, 0
+	for i, out := range outs {
+		for j, o := range out {
+			total += math.Abs(o - d[i].Truth[j])
+			n++
+		}
+	}
+	return total / float64(n), nil
+}
+
+// RMSE returns the root mean squared error between the outputs of nw and the
+// Truth of d, over every output of every datum.
+func RMSE(nw network.Network, d trainer.Data) (float64, error) {
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return 0, err
+	}
+
+	total, n := 0.0, 0
+	for i, out := range outs {
+		for j, o := range out {
+			total += (o - d[i].Truth[j]) * (o - d[i].Truth[j])
+			n++
+		}
+	}
+	return math.Sqrt(total / float64(n)), nil
+}
+
+// R2 returns the coefficient of determination of the outputs of nw on the Truth
+// of d, which is 1 for perfect predictions and 0 for always predicting the mean
+// Truth. With several outputs, the R2 of each is averaged.
+//
+// An output whose Truth is the same for every datum has an R2 of 1 if it is
+// predicted perfectly and 0 otherwise.
+func R2(nw network.Network, d trainer.Data) (float64, error) {
+	outs, err := outputs(nw, d)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0.0
+	for j := range outs[0] {
+		mean := 0.0
+		for i := range outs {
+			mean += d[i].Truth[j]
+		}
+		mean /= float64(len(outs))
+
+		residual, variance := 0.0, 0.0
+		for i, out := range outs {
+			residual += (d[i].Truth[j] - out[j]) * (d[i].Truth[j] - out[j])
+			variance += (d[i].Truth[j] - mean) * (d[i].Truth[j] - mean)
+		}
+		switch {
+		case variance != 0:
+			total += 1 - residual/variance
+		case residual == 0:
+			total++
+		}
+	}
+	return total / float64(len(outs[0])), nil
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Report returns a text report of how well nw classifies d: its confusion
+// matrix, the precision, recall, F1 and support of every class, their macro and
+// micro averages, and the log-loss and ROC-AUC of nw. Log-loss uses the default
+// probabilities described by LogLoss, and ROC-AUC is omitted if d lacks the
+// positive and negative datums it needs.
+func Report(nw network.Network, d trainer.Data) (string, error) {
+	c, err := ConfusionMatrix(nw, d)
+	if err != nil {
+		return "", err
+	}
+	logLoss, err := LogLoss(nw, d, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, c)
+
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "\tprecision\trecall\tf1\tsupport\t")
+	for i, l := range c.Labels {
+		_, _ = fmt.Fprintf(tw, "%v\t%.4f\t%.4f\t%.4f\t%v\t\n", l, c.Precision(i), c.Recall(i), c.F1(i), c.Support(i))
+	}
+	_, _ = fmt.Fprintln(tw, "\t\t\t\t\t")
+	_, _ = fmt.Fprintf(tw, "macro avg\t%.4f\t%.4f\t%.4f\t%v\t\n", c.MacroPrecision(), c.MacroRecall(), c.MacroF1(), c.Total())
+	_, _ = fmt.Fprintf(tw, "micro avg\t%.4f\t%.4f\t%.4f\t%v\t\n", c.MicroPrecision(), c.MicroRecall(), c.MicroF1(), c.Total())
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(&sb, "\naccuracy: %.4f\n", c.Accuracy())
+	_, _ = fmt.Fprintf(&sb, "log-loss: %.4f\n", logLoss)
+	if auc, err := ROCAUC(nw, d); err == nil {
+		_, _ = fmt.Fprintf(&sb, "roc-auc:  %.4f\n", auc)
+	}
+	return sb.String(), nil
+}
+
+// MustReport calls Report but panics if an error is encountered.
+func MustReport(nw network.Network, d trainer.Data) string {
+	r, err := Report(nw, d)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// RegressionReport returns a text report of the MAE, RMSE and R2 of nw on d.
+func RegressionReport(nw network.Network, d trainer.Data) (string, error) {
+	mae, err := MAE(nw, d)
+	if err != nil {
+		return "", err
+	}
+	rmse, err := RMSE(nw, d)
+	if err != nil {
+		return "", err
+	}
+	r2, err := R2(nw, d)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("mae:  %.4f\nrmse: %.4f\nr2:   %.4f\n", mae, rmse, r2), nil
+}
+
+// MustRegressionReport calls RegressionReport but panics if an error is
+// encountered.
+func MustRegressionReport(nw network.Network, d trainer.Data) string {
+	r, err := RegressionReport(nw, d)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
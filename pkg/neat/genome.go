@@ -0,0 +1,497 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// NodeKind is the role of a node within a Genome.
+type NodeKind int
+
+const (
+	// NodeInput nodes are the inputs of the compiled network.
+	NodeInput NodeKind = iota
+	// NodeHidden nodes are added by mutation.
+	NodeHidden
+	// NodeOutput nodes are the outputs of the compiled network.
+	NodeOutput
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case NodeInput:
+		return "input"
+	case NodeHidden:
+		return "hidden"
+	case NodeOutput:
+		return "output"
+	default:
+		return fmt.Sprintf("NodeKind(%d)", int(k))
+	}
+}
+
+// NodeGene describes a single neuron. IDs are shared across every Genome of a
+// population, so that the same node added by the same mutation in different
+// genomes has the same ID.
+type NodeGene struct {
+	ID   int
+	Kind NodeKind
+	Bias float64
+}
+
+// ConnectionGene describes a single connection from the node In to the node
+// Out. Innovation identifies the connection across every Genome of a
+// population, and is used to line up the genes of genomes when crossing them
+// over and measuring their distance. Disabled connections are kept so that
+// they may be inherited, but are left out of the compiled network.
+type ConnectionGene struct {
+	Innovation int
+	In, Out    int
+	Weight     float64
+	Enabled    bool
+}
+
+// Genome encodes the topology and parameters of a network. Its Nodes are
+// sorted by ID and its Connections by Innovation.
+type Genome struct {
+	Nodes       []NodeGene
+	Connections []ConnectionGene
+	// Fitness is the fitness of the genome's network once it has been
+	// evaluated.
+	Fitness float64
+}
+
+// Clone returns a deep copy of g.
+func (g *Genome) Clone() *Genome {
+	return &Genome{
+		Nodes:       append([]NodeGene(nil), g.Nodes...),
+		Connections: append([]ConnectionGene(nil), g.Connections...),
+		Fitness:     g.Fitness,
+	}
+}
+
+func (g *Genome) node(id int) (NodeGene, bool) {
+	i := sort.Search(len(g.Nodes), func(i int) bool {
+		return g.Nodes[i].ID >= id
+	})
+	if i < len(g.Nodes) && g.Nodes[i].ID == id {
+		return g.Nodes[i], true
+	}
+	return NodeGene{}, false
+}
+
+func (g *Genome) addNode(n NodeGene) {
+	g.Nodes = append(g.Nodes, n)
+	sort.Slice(g.Nodes, func(a, b int) bool {
+		return g.Nodes[a].ID < g.Nodes[b].ID
+	})
+}
+
+func (g *Genome) addConnection(c ConnectionGene) {
+	g.Connections = append(g.Connections, c)
+	sort.Slice(g.Connections, func(a, b int) bool {
+		return g.Connections[a].Innovation < g.Connections[b].Innovation
+	})
+}
+
+// depths returns the depth of every node of g, which is 0 for inputs and
+// otherwise one more than the deepest node with an enabled connection into it.
+// An error is returned if the enabled connections of g form a cycle.
+func (g *Genome) depths() (map[int]int, error) {
+	incoming := make(map[int][]int)
+	for _, c := range g.Connections {
+		if c.Enabled {
+			incoming[c.Out] = append(incoming[c.Out], c.In)
+		}
+	}
+
+	depths := make(map[int]int, len(g.Nodes))
+	visiting := make(map[int]bool)
+	var visit func(id int) (int, error)
+	visit = func(id int) (int, error) {
+		if d, found := depths[id]; found {
+			return d, nil
+		}
+		if visiting[id] {
+			return 0, fmt.Errorf("connections form a cycle through node %v", id)
+		}
+		visiting[id] = true
+
+		d := 0
+		if n, _ := g.node(id); n.Kind != NodeInput {
+			d = 1
+			for _, source := range incoming[id] {
+				sd, err := visit(source)
+				if err != nil {
+					return 0, err
+				}
+				if sd+1 > d {
+					d = sd + 1
+				}
+			}
+		}
+		visiting[id] = false
+		depths[id] = d
+		return d, nil
+	}
+
+	for _, n := range g.Nodes {
+		if _, err := visit(n.ID); err != nil {
+			return nil, err
+		}
+	}
+	return depths, nil
+}
+
+// Network compiles g into a network. Its input and output layers hold the
+// input and output nodes of g in ID order, labeled with the InputLabels and
+// OutputLabels of spec, and every hidden node is placed in the shallowest layer
+// after all the nodes it reads from. Every neuron uses the activation function
+// of spec, and only reads from the nodes it has enabled connections from, so
+// the network is sparse and its connections may skip layers. See
+// network.Neuron.AddConnection for what such networks support.
+func (g *Genome) Network(spec network.Spec) (network.Network, error) {
+	depths, err := g.depths()
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs, hidden, outputs []NodeGene
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case NodeInput:
+			inputs = append(inputs, n)
+		case NodeHidden:
+			hidden = append(hidden, n)
+		case NodeOutput:
+			outputs = append(outputs, n)
+		}
+	}
+	if len(inputs) != len(spec.InputLabels) || len(outputs) != len(spec.OutputLabels) {
+		return nil, fmt.Errorf("genome has %v inputs and %v outputs, but spec labels %v and %v", len(inputs), len(outputs), len(spec.InputLabels), len(spec.OutputLabels))
+	}
+
+	// NOTE: Hidden layers are numbered by the distinct depths of hidden nodes,
+	// so that no layer is empty. Since a node is always deeper than the nodes
+	// it reads from, this preserves their order.
+	var hiddenDepths []int
+	layerOf := make(map[int]int)
+	for _, n := range hidden {
+		if _, found := layerOf[depths[n.ID]]; !found {
+			layerOf[depths[n.ID]] = 0
+			hiddenDepths = append(hiddenDepths, depths[n.ID])
+		}
+	}
+	sort.Ints(hiddenDepths)
+	for i, d := range hiddenDepths {
+		layerOf[d] = i + 1
+	}
+
+	nw := make(network.Network, len(hiddenDepths)+2)
+	neurons := make(map[int]*network.Neuron, len(g.Nodes))
+	add := func(li int, n NodeGene, label string) error {
+		neuron, err := network.NewNeuron(nil, spec.ActivationFunctionName)
+		if err != nil {
+			return err
+		}
+		neuron.SetBias(n.Bias)
+		neuron.SetLabel(label)
+		nw[li] = append(nw[li], neuron)
+		neurons[n.ID] = neuron
+		return nil
+	}
+	for i, n := range inputs {
+		if err := add(0, n, spec.InputLabels[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range hidden {
+		if err := add(layerOf[depths[n.ID]], n, ""); err != nil {
+			return nil, err
+		}
+	}
+	for i, n := range outputs {
+		if err := add(len(nw)-1, n, spec.OutputLabels[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range g.Connections {
+		if !c.Enabled {
+			continue
+		}
+		in, out := neurons[c.In], neurons[c.Out]
+		if in == nil || out == nil {
+			return nil, fmt.Errorf("connection %v is between unknown nodes %v and %v", c.Innovation, c.In, c.Out)
+		}
+		out.AddConnection(in, c.Weight)
+	}
+
+	if !nw.IsFeedForward() {
+		return nil, errors.New("genome has a connection from an output node")
+	}
+	return nw, nil
+}
+
+// MustNetwork calls Network but panics if an error is encountered.
+func (g *Genome) MustNetwork(spec network.Spec) network.Network {
+	nw, err := g.Network(spec)
+	if err != nil {
+		panic(errors.Wrap(err, "must network"))
+	}
+	return nw
+}
+
+// reaches reports whether there is a path of enabled or disabled connections
+// from the node from to the node to.
+func (g *Genome) reaches(from, to int) bool {
+	outgoing := make(map[int][]int)
+	for _, c := range g.Connections {
+		outgoing[c.In] = append(outgoing[c.In], c.Out)
+	}
+	seen := map[int]bool{from: true}
+	stack := []int{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		for _, next := range outgoing[id] {
+			if !seen[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
+
+// mutateWeights perturbs, or occasionally replaces, the weight of each
+// connection and the bias of each non-input node of g, with probability
+// c.WeightMutationRate.
+func (g *Genome) mutateWeights(c Configuration) {
+	mutate := func(v float64) float64 {
+		if rand.Float64() < c.WeightReplaceRate {
+			return rand.NormFloat64()
+		}
+		return v + rand.NormFloat64()*c.WeightPerturbStdDev
+	}
+	for i := range g.Connections {
+		if rand.Float64() < c.WeightMutationRate {
+			g.Connections[i].Weight = mutate(g.Connections[i].Weight)
+		}
+	}
+	for i := range g.Nodes {
+		if g.Nodes[i].Kind != NodeInput && rand.Float64() < c.WeightMutationRate {
+			g.Nodes[i].Bias = mutate(g.Nodes[i].Bias)
+		}
+	}
+}
+
+// mutateAddConnection adds a connection with a random weight between two
+// unconnected nodes of g, if it can find a pair which wouldn't form a cycle.
+// Connections never lead out of an output node or into an input node.
+func (g *Genome) mutateAddConnection(in *innovations) {
+	connected := make(map[[2]int]bool, len(g.Connections))
+	for _, c := range g.Connections {
+		connected[[2]int{c.In, c.Out}] = true
+	}
+
+	var sources, targets []int
+	for _, n := range g.Nodes {
+		if n.Kind != NodeOutput {
+			sources = append(sources, n.ID)
+		}
+		if n.Kind != NodeInput {
+			targets = append(targets, n.ID)
+		}
+	}
+
+	const attempts = 20
+	for a := 0; a < attempts; a++ {
+		from, to := sources[rand.Intn(len(sources))], targets[rand.Intn(len(targets))]
+		if from == to || connected[[2]int{from, to}] || g.reaches(to, from) {
+			continue
+		}
+		g.addConnection(ConnectionGene{
+			Innovation: in.connection(from, to),
+			In:         from,
+			Out:        to,
+			Weight:     rand.NormFloat64(),
+			Enabled:    true,
+		})
+		return
+	}
+}
+
+// mutateAddNode splits a random enabled connection of g in two with a new
+// hidden node. The connection into the node has a weight of 1 and the one out
+// of it has the weight of the split connection, which is disabled, so the
+// network initially behaves much as it did.
+func (g *Genome) mutateAddNode(in *innovations) {
+	var enabled []int
+	for i, c := range g.Connections {
+		if c.Enabled {
+			enabled = append(enabled, i)
+		}
+	}
+	if len(enabled) == 0 {
+		return
+	}
+
+	ci := enabled[rand.Intn(len(enabled))]
+	split := g.Connections[ci]
+	g.Connections[ci].Enabled = false
+
+	id := in.split(split.Innovation)
+	if _, found := g.node(id); found {
+		// NOTE: The same connection was already split in this genome, then
+		// later re-enabled via crossover, so the shared node can't be reused.
+		id = in.newNode()
+	}
+	g.addNode(NodeGene{ID: id, Kind: NodeHidden})
+	g.addConnection(ConnectionGene{Innovation: in.connection(split.In, id), In: split.In, Out: id, Weight: 1, Enabled: true})
+	g.addConnection(ConnectionGene{Innovation: in.connection(id, split.Out), In: id, Out: split.Out, Weight: split.Weight, Enabled: true})
+}
+
+// Crossover returns a child of parents a and b. Connections whose innovations
+// both parents share are inherited from either at random, while those only one
+// parent has are inherited from the fitter parent, or from a if they are
+// equally fit. A connection disabled in either parent is usually disabled in
+// the child.
+func Crossover(a, b *Genome) *Genome {
+	if b.Fitness > a.Fitness {
+		a, b = b, a
+	}
+
+	others := make(map[int]ConnectionGene, len(b.Connections))
+	for _, c := range b.Connections {
+		others[c.Innovation] = c
+	}
+
+	child := &Genome{Connections: make([]ConnectionGene, len(a.Connections))}
+	for i, c := range a.Connections {
+		child.Connections[i] = c
+		other, found := others[c.Innovation]
+		if !found {
+			continue
+		}
+		if rand.Intn(2) == 0 {
+			child.Connections[i].Weight = other.Weight
+		}
+		child.Connections[i].Enabled = c.Enabled && other.Enabled || rand.Float64() >= disabledInheritanceRate
+	}
+
+	// NOTE: Every connection comes from a, so the child needs exactly a's
+	// nodes, with biases shared with b mixed in the same way as weights.
+	child.Nodes = make([]NodeGene, len(a.Nodes))
+	for i, n := range a.Nodes {
+		child.Nodes[i] = n
+		if other, found := b.node(n.ID); found && rand.Intn(2) == 0 {
+			child.Nodes[i].Bias = other.Bias
+		}
+	}
+	return child
+}
+
+// disabledInheritanceRate is the probability that a connection disabled in
+// either parent is disabled in their child.
+const disabledInheritanceRate = 0.75
+
+// Distance returns the compatibility distance between a and b, which grows
+// with the number of connections only one of them has, and with the average
+// difference between the weights of the connections both have. Connections
+// beyond the latest innovation of the other genome are excess, and those
+// before it are disjoint.
+func Distance(a, b *Genome, c Configuration) float64 {
+	i, j := 0, 0
+	excess, disjoint, matching := 0, 0, 0
+	weightDiff := 0.0
+	for i < len(a.Connections) && j < len(b.Connections) {
+		ca, cb := a.Connections[i], b.Connections[j]
+		switch {
+		case ca.Innovation == cb.Innovation:
+			matching++
+			weightDiff += math.Abs(ca.Weight - cb.Weight)
+			i++
+			j++
+		case ca.Innovation < cb.Innovation:
+			disjoint++
+			i++
+		default:
+			disjoint++
+			j++
+		}
+	}
+	excess = len(a.Connections) - i + len(b.Connections) - j
+
+	// NOTE: As in the original NEAT paper, small genomes aren't normalized by
+	// size, since a few differing genes are significant for them.
+	n := float64(len(a.Connections))
+	if len(b.Connections) > len(a.Connections) {
+		n = float64(len(b.Connections))
+	}
+	if n < 20 {
+		n = 1
+	}
+
+	d := (c.ExcessCoefficient*float64(excess) + c.DisjointCoefficient*float64(disjoint)) / n
+	if matching > 0 {
+		d += c.WeightCoefficient * weightDiff / float64(matching)
+	}
+	return d
+}
+
+// innovations hands out innovation numbers and node IDs, giving the same
+// structural mutation the same number every time it occurs.
+type innovations struct {
+	nextInnovation int
+	nextNode       int
+	connections    map[[2]int]int
+	splits         map[int]int
+}
+
+func newInnovations(nodes int) *innovations {
+	return &innovations{
+		nextNode:    nodes,
+		connections: make(map[[2]int]int),
+		splits:      make(map[int]int),
+	}
+}
+
+// connection returns the innovation number of a connection from the node from
+// to the node to.
+func (in *innovations) connection(from, to int) int {
+	key := [2]int{from, to}
+	if i, found := in.connections[key]; found {
+		return i
+	}
+	i := in.nextInnovation
+	in.nextInnovation++
+	in.connections[key] = i
+	return i
+}
+
+// split returns the ID of the node added when splitting the connection with
+// innovation number innovation.
+func (in *innovations) split(innovation int) int {
+	if id, found := in.splits[innovation]; found {
+		return id
+	}
+	id := in.newNode()
+	in.splits[innovation] = id
+	return id
+}
+
+func (in *innovations) newNode() int {
+	id := in.nextNode
+	in.nextNode++
+	return id
+}
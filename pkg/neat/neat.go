@@ -0,0 +1,552 @@
+// Package neat evolves both the topology and the weights of networks via
+// NeuroEvolution of Augmenting Topologies. Every network starts with its inputs
+// connected directly to its outputs, and over generations mutations add
+// connections and split them with new neurons. Genomes are grouped into species
+// of similar topology, which compete mostly amongst themselves via fitness
+// sharing, so that new structure has time to be optimized before it must
+// outperform the rest of the population.
+//
+// The fittest Genome compiles into a network.Network, which is sparse and whose
+// connections may skip layers.
+package neat
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// FitnessFunc scores how well nw performs, where higher is better. When
+// Configuration.Workers is greater than 1, it is called concurrently for
+// different networks.
+type FitnessFunc func(nw network.Network) (float64, error)
+
+// Configuration defines how an Evolver evolves genomes. DefaultConfiguration
+// provides values which work well for small problems.
+type Configuration struct {
+	// PopulationSize is the number of genomes in every generation.
+	PopulationSize int
+	// Generations is the number of generations bred before evolution ends.
+	Generations int
+	// FitnessThreshold, when not zero, ends evolution once any genome's
+	// fitness reaches it.
+	FitnessThreshold float64
+	// Timeout ends evolution after this much time has passed. Setting to 0
+	// means there is no timeout.
+	Timeout time.Duration
+	// Workers is the maximum number of networks whose fitness is evaluated at
+	// once. Values less than 1 are treated as 1.
+	Workers int
+
+	// CompatibilityThreshold is the Distance below which a genome belongs to
+	// a species.
+	CompatibilityThreshold float64
+	// ExcessCoefficient, DisjointCoefficient and WeightCoefficient weigh the
+	// terms of Distance.
+	ExcessCoefficient, DisjointCoefficient, WeightCoefficient float64
+
+	// WeightMutationRate is the probability that each weight and bias of a
+	// child is mutated.
+	WeightMutationRate float64
+	// WeightPerturbStdDev is the standard deviation of the Gaussian noise
+	// added to a weight or bias when it is mutated.
+	WeightPerturbStdDev float64
+	// WeightReplaceRate is the probability that a mutated weight or bias is
+	// replaced with a new random value rather than perturbed.
+	WeightReplaceRate float64
+	// AddConnectionRate is the probability that a child gains a connection.
+	AddConnectionRate float64
+	// AddNodeRate is the probability that a child gains a hidden node.
+	AddNodeRate float64
+	// CrossoverRate is the probability that a child is bred by crossing over
+	// two parents of its species rather than copying one.
+	CrossoverRate float64
+	// SurvivalThreshold is the fraction of the fittest genomes of every
+	// species which may become parents.
+	SurvivalThreshold float64
+	// StagnationLimit, when greater than zero, removes species whose best
+	// fitness hasn't improved for this many generations, unless it holds the
+	// fittest genome.
+	StagnationLimit int
+	// Elitism is the number of the fittest genomes of every species which are
+	// carried into the next generation unchanged.
+	Elitism int
+}
+
+// DefaultConfiguration returns the parameters used for XOR in the original NEAT
+// paper.
+func DefaultConfiguration() Configuration {
+	return Configuration{
+		PopulationSize:         150,
+		Generations:            300,
+		CompatibilityThreshold: 3,
+		ExcessCoefficient:      1,
+		DisjointCoefficient:    1,
+		WeightCoefficient:      0.4,
+		WeightMutationRate:     0.8,
+		WeightPerturbStdDev:    0.5,
+		WeightReplaceRate:      0.1,
+		AddConnectionRate:      0.05,
+		AddNodeRate:            0.03,
+		CrossoverRate:          0.75,
+		SurvivalThreshold:      0.2,
+		StagnationLimit:        15,
+		Elitism:                1,
+	}
+}
+
+func (c Configuration) validate() error {
+	if c.PopulationSize < 1 {
+		return fmt.Errorf("population size (%v) must be at least 1", c.PopulationSize)
+	}
+	if c.CompatibilityThreshold <= 0 {
+		return fmt.Errorf("compatibility threshold (%v) must be positive", c.CompatibilityThreshold)
+	}
+	for name, rate := range map[string]float64{
+		"weight mutation rate": c.WeightMutationRate,
+		"weight replace rate":  c.WeightReplaceRate,
+		"add connection rate":  c.AddConnectionRate,
+		"add node rate":        c.AddNodeRate,
+		"crossover rate":       c.CrossoverRate,
+		"survival threshold":   c.SurvivalThreshold,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%v (%v) must be in [0, 1]", name, rate)
+		}
+	}
+	if c.Elitism < 0 {
+		return fmt.Errorf("elitism (%v) must not be negative", c.Elitism)
+	}
+	return nil
+}
+
+// Species is a group of genomes of similar topology.
+type Species struct {
+	ID int
+	// Representative is the genome others are compared to when deciding
+	// whether they belong to the species.
+	Representative *Genome
+	// Members are the genomes of the species in the current generation.
+	Members []*Genome
+	// BestFitness is the best fitness any member has ever had.
+	BestFitness float64
+	// Stagnation is the number of generations since BestFitness improved.
+	Stagnation int
+}
+
+// GenerationStats summarizes a generation.
+type GenerationStats struct {
+	Generation int
+	Best, Mean float64
+	Species    int
+	// Nodes and Connections are the number of nodes and enabled connections
+	// of the generation's fittest genome.
+	Nodes, Connections int
+}
+
+// Evolver evolves a population of genomes towards maximizing a fitness
+// function.
+type Evolver struct {
+	Configuration
+	// Spec provides the InputLabels, OutputLabels and ActivationFunctionName
+	// of every network. Its NeuronMap is ignored, since hidden neurons are
+	// evolved.
+	Spec network.Spec
+	// Fitness scores the network of every genome.
+	Fitness FitnessFunc
+	Log     io.Writer
+
+	// Population is the current population. If it is set before Evolve is
+	// called, evolution continues from it rather than from minimal genomes.
+	Population []*Genome
+	// Species are the species of the current population.
+	Species []*Species
+	// Best is the fittest genome found.
+	Best *Genome
+	// History holds the GenerationStats of every generation evaluated.
+	History []GenerationStats
+
+	innovations *innovations
+	nextSpecies int
+}
+
+// New creates an Evolver which evolves networks with the inputs, outputs and
+// activation function of spec to maximize fitness. Providing nil for log
+// results in logs being written to stdout.
+func New(c Configuration, spec network.Spec, fitness FitnessFunc, log io.Writer) Evolver {
+	if log == nil {
+		log = os.Stdout
+	}
+	return Evolver{
+		Configuration: c,
+		Spec:          spec,
+		Fitness:       fitness,
+		Log:           log,
+	}
+}
+
+// ErrTimedOut is returned by Evolve when the Timeout of its Configuration is
+// reached.
+var ErrTimedOut = errors.New("evolution process timed out")
+
+// Evolve evolves Generations generations of genomes, then returns the network
+// of the fittest genome found, which is also kept as Best. Evolution ends
+// early, returning the fittest network so far, if FitnessThreshold is reached,
+// or with ErrTimedOut if Timeout is reached.
+func (e *Evolver) Evolve() (network.Network, error) {
+	if err := e.Configuration.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if e.Fitness == nil {
+		return nil, errors.New("must provide a fitness function")
+	}
+	if len(e.Spec.InputLabels) == 0 || len(e.Spec.OutputLabels) == 0 {
+		return nil, errors.New("spec must have input and output labels")
+	}
+	if e.Log == nil {
+		e.Log = os.Stdout
+	}
+
+	var deadline time.Time
+	if e.Configuration.Timeout > 0 {
+		deadline = time.Now().Add(e.Configuration.Timeout)
+	}
+
+	if len(e.Population) == 0 {
+		e.initialize()
+	} else if e.innovations == nil {
+		e.track()
+	}
+
+	_, _ = fmt.Fprintln(e.Log, "Starting evolution process...")
+
+	for g := 0; ; g++ {
+		if err := e.evaluate(); err != nil {
+			return nil, fmt.Errorf("evaluating generation %v: %w", g, err)
+		}
+		e.speciate()
+		e.record(g)
+
+		done := ""
+		switch {
+		case e.Configuration.FitnessThreshold != 0 && e.Best.Fitness >= e.Configuration.FitnessThreshold:
+			done = "Reached fitness threshold, ending evolution process..."
+		case g >= e.Configuration.Generations:
+			done = "Reached maximum generations, ending evolution process..."
+		case !deadline.IsZero() && time.Now().After(deadline):
+			nw, err := e.Best.Network(e.Spec)
+			if err != nil {
+				return nil, err
+			}
+			return nw, ErrTimedOut
+		}
+		if done != "" {
+			_, _ = fmt.Fprintln(e.Log, done)
+			return e.Best.Network(e.Spec)
+		}
+
+		e.reproduce()
+	}
+}
+
+// MustEvolve calls Evolve but panics if an error is encountered.
+func (e *Evolver) MustEvolve() network.Network {
+	nw, err := e.Evolve()
+	if err != nil {
+		panic(errors.Wrap(err, "must evolve"))
+	}
+	return nw
+}
+
+// initialize creates a population of minimal genomes, whose every input is
+// connected to every output with a random weight.
+func (e *Evolver) initialize() {
+	inputs, outputs := len(e.Spec.InputLabels), len(e.Spec.OutputLabels)
+	e.innovations = newInnovations(inputs + outputs)
+
+	e.Population = make([]*Genome, e.Configuration.PopulationSize)
+	for i := range e.Population {
+		g := &Genome{}
+		for id := 0; id < inputs; id++ {
+			g.Nodes = append(g.Nodes, NodeGene{ID: id, Kind: NodeInput})
+		}
+		for id := inputs; id < inputs+outputs; id++ {
+			g.Nodes = append(g.Nodes, NodeGene{ID: id, Kind: NodeOutput})
+		}
+		for in := 0; in < inputs; in++ {
+			for out := inputs; out < inputs+outputs; out++ {
+				g.Connections = append(g.Connections, ConnectionGene{
+					Innovation: e.innovations.connection(in, out),
+					In:         in,
+					Out:        out,
+					Weight:     rand.NormFloat64(),
+					Enabled:    true,
+				})
+			}
+		}
+		e.Population[i] = g
+	}
+}
+
+// track rebuilds the innovation numbers of a population provided by the
+// caller, so that new mutations don't reuse them.
+func (e *Evolver) track() {
+	nodes := 0
+	for _, g := range e.Population {
+		for _, n := range g.Nodes {
+			if n.ID+1 > nodes {
+				nodes = n.ID + 1
+			}
+		}
+	}
+	e.innovations = newInnovations(nodes)
+	for _, g := range e.Population {
+		for _, c := range g.Connections {
+			e.innovations.connections[[2]int{c.In, c.Out}] = c.Innovation
+			if c.Innovation+1 > e.innovations.nextInnovation {
+				e.innovations.nextInnovation = c.Innovation + 1
+			}
+		}
+	}
+}
+
+// evaluate sets the Fitness of every genome of the population, and updates
+// Best.
+func (e *Evolver) evaluate() error {
+	workers := e.Configuration.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, len(e.Population))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range e.Population {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			g := e.Population[i]
+			nw, err := g.Network(e.Spec)
+			if err != nil {
+				errs[i] = fmt.Errorf("compiling genome %v: %w", i, err)
+				return
+			}
+			f, err := e.Fitness(nw)
+			if err != nil {
+				errs[i] = fmt.Errorf("genome %v: %w", i, err)
+				return
+			}
+			if math.IsNaN(f) {
+				errs[i] = fmt.Errorf("fitness of genome %v is NaN", i)
+				return
+			}
+			g.Fitness = f
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, g := range e.Population {
+		if e.Best == nil || g.Fitness > e.Best.Fitness {
+			e.Best = g.Clone()
+		}
+	}
+	return nil
+}
+
+// speciate assigns every genome of the population to the first species whose
+// representative it is compatible with, creating new species as needed, then
+// updates the stagnation of every species and picks its next representative.
+func (e *Evolver) speciate() {
+	for _, s := range e.Species {
+		s.Members = nil
+	}
+	for _, g := range e.Population {
+		var species *Species
+		for _, s := range e.Species {
+			if Distance(g, s.Representative, e.Configuration) < e.Configuration.CompatibilityThreshold {
+				species = s
+				break
+			}
+		}
+		if species == nil {
+			species = &Species{ID: e.nextSpecies, Representative: g, BestFitness: math.Inf(-1), Stagnation: -1}
+			e.nextSpecies++
+			e.Species = append(e.Species, species)
+		}
+		species.Members = append(species.Members, g)
+	}
+
+	var alive []*Species
+	for _, s := range e.Species {
+		if len(s.Members) == 0 {
+			continue
+		}
+		sort.SliceStable(s.Members, func(a, b int) bool {
+			return s.Members[a].Fitness > s.Members[b].Fitness
+		})
+		if best := s.Members[0].Fitness; best > s.BestFitness {
+			s.BestFitness, s.Stagnation = best, 0
+		} else {
+			s.Stagnation++
+		}
+		s.Representative = s.Members[rand.Intn(len(s.Members))]
+		alive = append(alive, s)
+	}
+	e.Species = alive
+}
+
+// record appends the GenerationStats of the population to History and logs
+// them.
+func (e *Evolver) record(generation int) {
+	s := GenerationStats{Generation: generation, Best: math.Inf(-1), Species: len(e.Species)}
+	var best *Genome
+	for _, g := range e.Population {
+		s.Mean += g.Fitness
+		if g.Fitness > s.Best {
+			s.Best, best = g.Fitness, g
+		}
+	}
+	s.Mean /= float64(len(e.Population))
+	s.Nodes = len(best.Nodes)
+	for _, c := range best.Connections {
+		if c.Enabled {
+			s.Connections++
+		}
+	}
+
+	e.History = append(e.History, s)
+	_, _ = fmt.Fprintf(e.Log, "Generation %v: best %.6f mean %.6f species %v nodes %v connections %v\n", generation, s.Best, s.Mean, s.Species, s.Nodes, s.Connections)
+}
+
+// reproduce replaces the population with the next generation. Stagnant species
+// are removed, then every species breeds a share of the next generation in
+// proportion to the average fitness of its members, which is its members'
+// fitness shared amongst them.
+func (e *Evolver) reproduce() {
+	c := e.Configuration
+
+	best := 0
+	for si, s := range e.Species {
+		if s.Members[0].Fitness > e.Species[best].Members[0].Fitness {
+			best = si
+		}
+	}
+	var species []*Species
+	for si, s := range e.Species {
+		if si == best || c.StagnationLimit <= 0 || s.Stagnation < c.StagnationLimit {
+			species = append(species, s)
+		}
+	}
+	e.Species = species
+
+	// NOTE: Fitness is shifted so that the least fit genome has a fitness of
+	// zero, since shares must not be negative.
+	min := math.Inf(1)
+	for _, s := range species {
+		for _, g := range s.Members {
+			min = math.Min(min, g.Fitness)
+		}
+	}
+	shares := make([]float64, len(species))
+	total := 0.0
+	for si, s := range species {
+		for _, g := range s.Members {
+			shares[si] += g.Fitness - min
+		}
+		shares[si] /= float64(len(s.Members))
+		total += shares[si]
+	}
+	counts := allocate(shares, total, c.PopulationSize)
+
+	next := make([]*Genome, 0, c.PopulationSize)
+	for si, s := range species {
+		n := counts[si]
+		for i := 0; i < c.Elitism && i < len(s.Members) && n > 0; i++ {
+			next = append(next, s.Members[i].Clone())
+			n--
+		}
+
+		survivors := int(math.Ceil(c.SurvivalThreshold * float64(len(s.Members))))
+		if survivors < 1 {
+			survivors = 1
+		}
+		parents := s.Members[:survivors]
+		for ; n > 0; n-- {
+			p1 := parents[rand.Intn(len(parents))]
+			var child *Genome
+			if len(parents) > 1 && rand.Float64() < c.CrossoverRate {
+				p2 := parents[rand.Intn(len(parents))]
+				for p2 == p1 {
+					p2 = parents[rand.Intn(len(parents))]
+				}
+				child = Crossover(p1, p2)
+			} else {
+				child = p1.Clone()
+			}
+
+			child.mutateWeights(c)
+			if rand.Float64() < c.AddNodeRate {
+				child.mutateAddNode(e.innovations)
+			}
+			if rand.Float64() < c.AddConnectionRate {
+				child.mutateAddConnection(e.innovations)
+			}
+			next = append(next, child)
+		}
+	}
+	e.Population = next
+}
+
+// allocate divides size between shares in proportion to them, rounding so
+// that the counts sum to size. If total is zero, size is divided equally.
+func allocate(shares []float64, total float64, size int) []int {
+	exact := make([]float64, len(shares))
+	for i, share := range shares {
+		if total > 0 {
+			exact[i] = share / total * float64(size)
+		} else {
+			exact[i] = float64(size) / float64(len(shares))
+		}
+	}
+
+	counts := make([]int, len(shares))
+	remaining := size
+	for i, x := range exact {
+		counts[i] = int(x)
+		remaining -= counts[i]
+	}
+
+	// The remainder goes to the shares with the largest fractional parts.
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return exact[order[a]]-float64(counts[order[a]]) > exact[order[b]]-float64(counts[order[b]])
+	})
+	for i := 0; remaining > 0; i++ {
+		counts[order[i%len(order)]]++
+		remaining--
+	}
+	return counts
+}
@@ -0,0 +1,285 @@
+package neat
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// xor scores a network by how closely it computes a xor b, as 4 less its sum of
+// squared errors.
+func xor(nw network.Network) (float64, error) {
+	loss := 0.0
+	for _, c := range [][3]float64{{0, 0, 0}, {0, 1, 1}, {1, 0, 1}, {1, 1, 0}} {
+		vs, err := nw.PredictVector([]float64{c[0], c[1]})
+		if err != nil {
+			return 0, err
+		}
+		loss += (vs[0] - c[2]) * (vs[0] - c[2])
+	}
+	return 4 - loss, nil
+}
+
+func Test_Genome_Network(t *testing.T) {
+	spec := network.Spec{
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"xor"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	}
+	s := spec
+	s.ActivationFunctionName = activationfunction.NameLinear
+	// Node 3 reads input 0 and feeds output 2, which also reads input 1
+	// directly.
+	g := &Genome{
+		Nodes: []NodeGene{
+			{ID: 0, Kind: NodeInput},
+			{ID: 1, Kind: NodeInput},
+			{ID: 2, Kind: NodeOutput, Bias: 0.5},
+			{ID: 3, Kind: NodeHidden, Bias: -1},
+		},
+		Connections: []ConnectionGene{
+			{Innovation: 0, In: 0, Out: 2, Weight: 7, Enabled: false},
+			{Innovation: 1, In: 1, Out: 2, Weight: -1, Enabled: true},
+			{Innovation: 2, In: 0, Out: 3, Weight: 2, Enabled: true},
+			{Innovation: 3, In: 3, Out: 2, Weight: 3, Enabled: true},
+		},
+	}
+	nw := g.MustNetwork(s)
+
+	if len(nw) != 3 || len(nw[0]) != 2 || len(nw[1]) != 1 || len(nw[2]) != 1 {
+		t.Fatalf("expected layers of 2, 1 and 1 neurons, got %v layers", len(nw))
+	}
+	if nw.IsFullyConnected() || !nw.IsFeedForward() {
+		t.Fatal("expected a sparse feed forward network")
+	}
+	labels, err := nw.RawInputLabels()
+	if err != nil || labels[0] != "a" || labels[1] != "b" {
+		t.Fatalf("expected input labels a and b, got %v (%v)", labels, err)
+	}
+	if label, _ := nw.MustPredict([]float64{0, 0}); label != "xor" {
+		t.Fatalf("expected output label xor, got %q", label)
+	}
+
+	vs, err := nw.PredictVector([]float64{1, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hidden = 2*1 - 1 = 1, output = 3*1 - 1*4 + 0.5 = -0.5.
+	if vs[0] != -0.5 {
+		t.Fatalf("expected -0.5, got %v", vs[0])
+	}
+
+	cyclic := g.Clone()
+	cyclic.Connections = append(cyclic.Connections, ConnectionGene{Innovation: 4, In: 2, Out: 3, Weight: 1, Enabled: true})
+	if _, err := cyclic.Network(s); err == nil {
+		t.Fatal("expected an error compiling a cyclic genome")
+	}
+
+	s.OutputLabels = []string{"x", "y"}
+	if _, err := g.Network(s); err == nil {
+		t.Fatal("expected an error compiling a genome against mismatched labels")
+	}
+}
+
+func Test_Distance(t *testing.T) {
+	c := DefaultConfiguration()
+	// Node 3 reads input 0 and feeds output 2, which also reads input 1
+	// directly.
+	a := &Genome{
+		Nodes: []NodeGene{
+			{ID: 0, Kind: NodeInput},
+			{ID: 1, Kind: NodeInput},
+			{ID: 2, Kind: NodeOutput, Bias: 0.5},
+			{ID: 3, Kind: NodeHidden, Bias: -1},
+		},
+		Connections: []ConnectionGene{
+			{Innovation: 0, In: 0, Out: 2, Weight: 7, Enabled: false},
+			{Innovation: 1, In: 1, Out: 2, Weight: -1, Enabled: true},
+			{Innovation: 2, In: 0, Out: 3, Weight: 2, Enabled: true},
+			{Innovation: 3, In: 3, Out: 2, Weight: 3, Enabled: true},
+		},
+	}
+	if d := Distance(a, a, c); d != 0 {
+		t.Fatalf("expected a genome to be 0 from itself, got %v", d)
+	}
+
+	// Against a, b has innovations 0 and 1, where the weight of 1 differs by 2,
+	// and 5, so 2 and 3 are disjoint and 5 is excess.
+	b := a.Clone()
+	b.Connections = append(b.Connections[:2], ConnectionGene{Innovation: 5, In: 0, Out: 2})
+	b.Connections[1].Weight = 1
+	want := c.ExcessCoefficient*1 + c.DisjointCoefficient*2 + c.WeightCoefficient*2/2
+	if d := Distance(a, b, c); math.Abs(d-want) > 1e-12 {
+		t.Fatalf("expected distance %v, got %v", want, d)
+	}
+	if Distance(a, b, c) != Distance(b, a, c) {
+		t.Fatal("expected distance to be symmetric")
+	}
+}
+
+func Test_Crossover(t *testing.T) {
+	spec := network.Spec{
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"xor"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	}
+	// Node 3 reads input 0 and feeds output 2, which also reads input 1
+	// directly.
+	a := &Genome{
+		Nodes: []NodeGene{
+			{ID: 0, Kind: NodeInput},
+			{ID: 1, Kind: NodeInput},
+			{ID: 2, Kind: NodeOutput, Bias: 0.5},
+			{ID: 3, Kind: NodeHidden, Bias: -1},
+		},
+		Connections: []ConnectionGene{
+			{Innovation: 0, In: 0, Out: 2, Weight: 7, Enabled: false},
+			{Innovation: 1, In: 1, Out: 2, Weight: -1, Enabled: true},
+			{Innovation: 2, In: 0, Out: 3, Weight: 2, Enabled: true},
+			{Innovation: 3, In: 3, Out: 2, Weight: 3, Enabled: true},
+		},
+	}
+	b := a.Clone()
+	a.Fitness, b.Fitness = 1, 0
+	b.Connections = b.Connections[:2]
+	for i := range b.Connections {
+		b.Connections[i].Weight = -100
+	}
+
+	for i := 0; i < 20; i++ {
+		child := Crossover(b, a)
+		if len(child.Connections) != len(a.Connections) || len(child.Nodes) != len(a.Nodes) {
+			t.Fatal("expected the child to have the structure of the fitter parent")
+		}
+		for ci, c := range child.Connections {
+			if c.Weight != a.Connections[ci].Weight && (ci >= 2 || c.Weight != -100) {
+				t.Fatalf("connection %v of child has weight %v from neither parent", ci, c.Weight)
+			}
+		}
+		if _, err := child.Network(spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_Mutations(t *testing.T) {
+	spec := network.Spec{
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"xor"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	}
+	// Node 3 reads input 0 and feeds output 2, which also reads input 1
+	// directly.
+	h := &Genome{
+		Nodes: []NodeGene{
+			{ID: 0, Kind: NodeInput},
+			{ID: 1, Kind: NodeInput},
+			{ID: 2, Kind: NodeOutput, Bias: 0.5},
+			{ID: 3, Kind: NodeHidden, Bias: -1},
+		},
+		Connections: []ConnectionGene{
+			{Innovation: 0, In: 0, Out: 2, Weight: 7, Enabled: false},
+			{Innovation: 1, In: 1, Out: 2, Weight: -1, Enabled: true},
+			{Innovation: 2, In: 0, Out: 3, Weight: 2, Enabled: true},
+			{Innovation: 3, In: 3, Out: 2, Weight: 3, Enabled: true},
+		},
+	}
+	g := h.Clone()
+	in := newInnovations(4)
+	in.nextInnovation = 4
+
+	for i := 0; i < 200; i++ {
+		g.mutateAddNode(in)
+		g.mutateAddConnection(in)
+		if _, err := g.Network(spec); err != nil {
+			t.Fatalf("mutation %v: %v", i, err)
+		}
+	}
+	for i := 1; i < len(g.Connections); i++ {
+		if g.Connections[i].Innovation <= g.Connections[i-1].Innovation {
+			t.Fatal("expected connections to be sorted by unique innovations")
+		}
+	}
+
+	// The same split in another genome gets the same node and innovations.
+	a, b := h.Clone(), h.Clone()
+	in = newInnovations(4)
+	in.nextInnovation = 4
+	a.Connections = a.Connections[1:2]
+	b.Connections = b.Connections[1:2]
+	a.mutateAddNode(in)
+	b.mutateAddNode(in)
+	if Distance(a, b, Configuration{ExcessCoefficient: 1, DisjointCoefficient: 1}) != 0 {
+		t.Fatal("expected identical mutations to share innovations")
+	}
+}
+
+func Test_Evolve(t *testing.T) {
+	spec := network.Spec{
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"xor"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	}
+	c := DefaultConfiguration()
+	c.FitnessThreshold = 3.9
+	c.Workers = 4
+	e := New(c, spec, xor, io.Discard)
+
+	nw, err := e.Evolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fitness, _ := xor(nw); fitness < 3.9 {
+		t.Fatalf("expected evolution to solve xor, got fitness %v after %v generations", fitness, len(e.History))
+	}
+	if e.Best == nil || e.Best.Fitness < 3.9 || len(e.Species) == 0 {
+		t.Fatal("expected the best genome and species to be kept")
+	}
+	if len(e.Population) != c.PopulationSize {
+		t.Fatalf("expected a population of %v, got %v", c.PopulationSize, len(e.Population))
+	}
+}
+
+func Test_Evolve_InvalidConfiguration(t *testing.T) {
+	spec := network.Spec{
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"xor"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	}
+	c := DefaultConfiguration()
+	c.AddNodeRate = 2
+	tests := []Configuration{
+		{},
+		c,
+	}
+	for i, c := range tests {
+		e := New(c, spec, xor, io.Discard)
+		if _, err := e.Evolve(); err == nil {
+			t.Errorf("expected an error for configuration %v", i)
+		}
+	}
+
+	e := New(DefaultConfiguration(), network.Spec{OutputLabels: []string{"y"}}, xor, io.Discard)
+	if _, err := e.Evolve(); err == nil {
+		t.Error("expected an error for a spec without inputs")
+	}
+}
+
+func Test_allocate(t *testing.T) {
+	counts := allocate([]float64{1, 1, 1}, 3, 10)
+	if counts[0]+counts[1]+counts[2] != 10 {
+		t.Fatalf("expected counts to sum to 10, got %v", counts)
+	}
+	counts = allocate([]float64{0, 0}, 0, 5)
+	if counts[0]+counts[1] != 5 {
+		t.Fatalf("expected counts to sum to 5, got %v", counts)
+	}
+	counts = allocate([]float64{3, 1}, 4, 8)
+	if counts[0] != 6 || counts[1] != 2 {
+		t.Fatalf("expected 6 and 2, got %v", counts)
+	}
+}
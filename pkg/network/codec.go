@@ -0,0 +1,284 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/lzw"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// CodecName identifies a Codec in the codec registry.
+	CodecName string
+
+	// Codec compresses and decompresses streams of bytes. Codecs are made
+	// available to WithCodec by registering them via RegisterCodec.
+	Codec interface {
+		// NewWriter returns a writer which compresses everything written to
+		// it at the given level before writing it to w. Closing the returned
+		// writer must flush any buffered data to w, but must not close w.
+		NewWriter(w io.Writer, level int) (io.WriteCloser, error)
+		// NewReader returns a reader which decompresses everything read from
+		// r.
+		NewReader(r io.Reader) (io.ReadCloser, error)
+	}
+)
+
+const (
+	CodecGzip  CodecName = "gzip"
+	CodecZlib  CodecName = "zlib"
+	CodecFlate CodecName = "flate"
+	CodecLZW   CodecName = "lzw"
+)
+
+const (
+	// DefaultCompressionLevel asks a Codec to use its default trade off
+	// between speed and size. Levels otherwise follow those of compress/flate,
+	// from BestSpeed (1) to BestCompression (9), with 0 meaning no compression.
+	DefaultCompressionLevel = flate.DefaultCompression
+
+	// DefaultMaxDecompressedSize is the number of bytes WithCodec will
+	// decompress before giving up, unless overridden via
+	// WithMaxDecompressedSize.
+	DefaultMaxDecompressedSize = 1 << 30
+)
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[CodecName]Codec{
+		CodecGzip:  gzipCodec{},
+		CodecZlib:  zlibCodec{},
+		CodecFlate: flateCodec{},
+		CodecLZW:   lzwCodec{},
+	}
+)
+
+// RegisterCodec makes c available to WithCodec under name, replacing any Codec
+// previously registered under it.
+func RegisterCodec(name CodecName, c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[name] = c
+}
+
+// GetCodec returns the Codec registered under name.
+func GetCodec(name CodecName) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, found := codecs[name]
+	if !found {
+		return nil, fmt.Errorf("no codec found with name \"%v\"", name)
+	}
+	return c, nil
+}
+
+// MustGetCodec calls GetCodec but panics if an error is encountered.
+func MustGetCodec(name CodecName) Codec {
+	c, err := GetCodec(name)
+	if err != nil {
+		panic(errors.Wrap(err, "must get codec"))
+	}
+	return c
+}
+
+// DecompressionLimitError is returned when decompressing would produce more
+// bytes than permitted by WithMaxDecompressedSize, which guards against
+// decompression bombs in untrusted model files.
+type DecompressionLimitError struct {
+	Limit int64
+}
+
+func (e *DecompressionLimitError) Error() string {
+	return fmt.Sprintf("decompressed size exceeds the limit of %v bytes", e.Limit)
+}
+
+// CodecOption configures a TranslatorOption returned by WithCodec.
+type CodecOption func(*codecConfig)
+
+type codecConfig struct {
+	level   int
+	maxSize int64
+}
+
+// WithCompressionLevel sets the level a Codec compresses at. Codecs which have
+// no notion of a level, such as lzw, ignore it.
+func WithCompressionLevel(level int) CodecOption {
+	return func(cc *codecConfig) {
+		cc.level = level
+	}
+}
+
+// WithMaxDecompressedSize sets the number of bytes that will be decompressed
+// before failing with a *DecompressionLimitError. A limit that is not positive
+// disables the check, which should only be done for trusted model files.
+func WithMaxDecompressedSize(n int64) CodecOption {
+	return func(cc *codecConfig) {
+		cc.maxSize = n
+	}
+}
+
+// WithCompression compresses the serialized bytes with gzip at the default
+// level. It is equivalent to WithCodec(CodecGzip).
+func WithCompression() TranslatorOption {
+	return WithCodec(CodecGzip)
+}
+
+// WithCodec compresses the serialized bytes with the Codec registered under
+// name, and decompresses them when deserializing. By default the Codec's
+// default level is used and at most DefaultMaxDecompressedSize bytes are
+// decompressed, both of which can be changed via opts.
+//
+// The Codec is looked up when the option is used rather than when it is
+// created, so an unknown name results in an error from the Translator.
+func WithCodec(name CodecName, opts ...CodecOption) TranslatorOption {
+	cc := codecConfig{
+		level:   DefaultCompressionLevel,
+		maxSize: DefaultMaxDecompressedSize,
+	}
+	for _, opt := range opts {
+		opt(&cc)
+	}
+
+	newWriter := func(w io.Writer) (io.WriteCloser, error) {
+		c, err := GetCodec(name)
+		if err != nil {
+			return nil, err
+		}
+		wc, err := c.NewWriter(w, cc.level)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%v new writer", name)
+		}
+		return wc, nil
+	}
+	newReader := func(r io.Reader) (io.ReadCloser, error) {
+		c, err := GetCodec(name)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := c.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%v new reader", name)
+		}
+		if cc.maxSize <= 0 {
+			return rc, nil
+		}
+		return &limitedReadCloser{rc: rc, remaining: cc.maxSize, limit: cc.maxSize}, nil
+	}
+
+	return TranslatorOption{
+		Name: string(name),
+		Serialize: func(bs []byte) ([]byte, error) {
+			var b bytes.Buffer
+			wc, err := newWriter(&b)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := wc.Write(bs); err != nil {
+				return nil, errors.Wrapf(err, "%v write", name)
+			}
+			if err := wc.Close(); err != nil {
+				return nil, errors.Wrapf(err, "%v close", name)
+			}
+			return b.Bytes(), nil
+		},
+		Deserialize: func(bs []byte) ([]byte, error) {
+			rc, err := newReader(bytes.NewReader(bs))
+			if err != nil {
+				return nil, err
+			}
+			bs, err = io.ReadAll(rc)
+			if err != nil {
+				_ = rc.Close()
+				return nil, errors.Wrapf(err, "%v read", name)
+			}
+			if err := rc.Close(); err != nil {
+				return nil, errors.Wrapf(err, "%v close", name)
+			}
+			return bs, nil
+		},
+		NewWriter: newWriter,
+		NewReader: newReader,
+	}
+}
+
+// limitedReadCloser reads from rc until more than limit bytes have been read,
+// at which point it fails with a *DecompressionLimitError. Unlike
+// io.LimitReader, exceeding the limit is an error rather than a silent
+// truncation.
+type limitedReadCloser struct {
+	rc        io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (lr *limitedReadCloser) Read(p []byte) (int, error) {
+	if lr.remaining <= 0 {
+		// NOTE: Exactly limit bytes is fine, so only fail if there is more to
+		// be read.
+		var b [1]byte
+		n, err := lr.rc.Read(b[:])
+		if n > 0 {
+			return 0, &DecompressionLimitError{Limit: lr.limit}
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > lr.remaining {
+		p = p[:lr.remaining]
+	}
+	n, err := lr.rc.Read(p)
+	lr.remaining -= int64(n)
+	return n, err
+}
+
+func (lr *limitedReadCloser) Close() error {
+	return lr.rc.Close()
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type zlibCodec struct{}
+
+func (zlibCodec) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return zlib.NewWriterLevel(w, level)
+}
+
+func (zlibCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+
+type flateCodec struct{}
+
+func (flateCodec) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return flate.NewWriter(w, level)
+}
+
+func (flateCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+
+// lzwCodec uses the least significant bit first ordering and 8 bit literals
+// used by GIF. LZW has no notion of a compression level, so it is ignored.
+type lzwCodec struct{}
+
+func (lzwCodec) NewWriter(w io.Writer, _ int) (io.WriteCloser, error) {
+	return lzw.NewWriter(w, lzw.LSB, 8), nil
+}
+
+func (lzwCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return lzw.NewReader(r, lzw.LSB, 8), nil
+}
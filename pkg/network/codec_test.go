@@ -0,0 +1,126 @@
+package network
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+// nopWriteCloser adds a no-op Close to an io.Writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// identityCodec is a Codec that doesn't compress at all, used to exercise the
+// codec registry.
+type identityCodec struct{}
+
+func (identityCodec) NewWriter(w io.Writer, _ int) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (identityCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+func Test_WithCodec_RoundTrips(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{4, 6, 3},
+		InputLabels:            []string{"a", "b", "c", "d"},
+		OutputLabels:           []string{"x", "y", "z"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	RegisterCodec("identity", identityCodec{})
+
+	names := []CodecName{CodecGzip, CodecZlib, CodecFlate, CodecLZW, "identity"}
+	levels := []int{DefaultCompressionLevel, 0, 1, 9}
+
+	for _, name := range names {
+		for _, level := range levels {
+			pt := NewProtoTranslator(WithCodec(name, WithCompressionLevel(level)))
+
+			s := pt.MustSerialize(nw)
+			if err := pt.MustDeserialize(s).Equals(nw); err != nil {
+				t.Fatalf("%v level %v: deserialized network does not equal original: %v", name, level, err)
+			}
+
+			var b bytes.Buffer
+			pt.MustEncode(&b, nw)
+			if !bytes.Equal(b.Bytes(), s) {
+				t.Fatalf("%v level %v: encoded bytes do not match serialized bytes", name, level)
+			}
+			if err := pt.MustDecode(&b).Equals(nw); err != nil {
+				t.Fatalf("%v level %v: decoded network does not equal original: %v", name, level, err)
+			}
+		}
+	}
+}
+
+func Test_WithCodec_Level(t *testing.T) {
+	bs := bytes.Repeat([]byte("jnet "), 1000)
+
+	none, err := WithCodec(CodecZlib, WithCompressionLevel(0)).Serialize(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best, err := WithCodec(CodecZlib, WithCompressionLevel(9)).Serialize(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(best) >= len(none) {
+		t.Fatalf("expected best compression (%v bytes) to be smaller than no compression (%v bytes)", len(best), len(none))
+	}
+
+	if _, err := WithCodec(CodecGzip, WithCompressionLevel(42)).Serialize(bs); err == nil {
+		t.Fatalf("expected an error for an invalid compression level")
+	}
+}
+
+func Test_WithCodec_UnknownCodec(t *testing.T) {
+	if _, err := WithCodec("nope").Serialize([]byte("jnet")); err == nil {
+		t.Fatalf("expected an error for an unknown codec")
+	}
+	if _, err := WithCodec("nope").Deserialize([]byte("jnet")); err == nil {
+		t.Fatalf("expected an error for an unknown codec")
+	}
+}
+
+func Test_WithCodec_MaxDecompressedSize(t *testing.T) {
+	bs := make([]byte, 1<<20)
+
+	for _, name := range []CodecName{CodecGzip, CodecZlib, CodecFlate, CodecLZW} {
+		bomb, err := WithCodec(name).Serialize(bs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var dle *DecompressionLimitError
+		if _, err := WithCodec(name, WithMaxDecompressedSize(1<<10)).Deserialize(bomb); !errors.As(err, &dle) || dle.Limit != 1<<10 {
+			t.Fatalf("%v: expected a DecompressionLimitError, got %v", name, err)
+		}
+
+		rc, err := WithCodec(name, WithMaxDecompressedSize(1<<10)).NewReader(bytes.NewReader(bomb))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadAll(rc); !errors.As(err, &dle) {
+			t.Fatalf("%v: expected a DecompressionLimitError while streaming, got %v", name, err)
+		}
+
+		for _, limit := range []int64{int64(len(bs)), 0} {
+			out, err := WithCodec(name, WithMaxDecompressedSize(limit)).Deserialize(bomb)
+			if err != nil {
+				t.Fatalf("%v: unexpected error with a limit of %v: %v", name, limit, err)
+			}
+			if !bytes.Equal(out, bs) {
+				t.Fatalf("%v: decompressed bytes do not match", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+// compactJsonVersion is the schema version written by the compact JSON
+// translator.
+const compactJsonVersion = 1
+
+// compactJsonNetwork is the human-friendly schema written by the compact JSON
+// translator. Only what is needed to make predictions is stored: the shape of
+// the network, its labels, activation functions, weights and biases. Every
+// nested array is laid out identically to the arguments of the corresponding
+// Network.Set* operation.
+type compactJsonNetwork struct {
+	Version                 int                         `json:"version"`
+	NeuronMap               []int                       `json:"neuronMap"`
+	Labels                  [][]string                  `json:"labels"`
+	ActivationFunctionNames [][]activationfunction.Name `json:"activationFunctionNames"`
+	Biases                  [][]float64                 `json:"biases"`
+	Weights                 [][][]float64               `json:"weights"`
+	InputTransforms         []InputTransform            `json:"inputTransforms,omitempty"`
+}
+
+type compactJsonTranslator struct {
+	opts []TranslatorOption
+}
+
+var _ Translator = new(compactJsonTranslator)
+
+// NewCompactJsonTranslator returns a Translator which stores only the
+// architecture, labels, activation functions, weights and biases of a Network,
+// as indented nested arrays. Unlike NewJsonTranslator, none of the transient
+// state of a forward pass, backward pass or mini batch is stored, so the same
+// model always produces the same output, making it suitable for storing models
+// in version control and reviewing changes to them.
+func NewCompactJsonTranslator(opts ...TranslatorOption) Translator {
+	return compactJsonTranslator{
+		opts: opts,
+	}
+}
+
+func (ct compactJsonTranslator) Serialize(nw Network) ([]byte, error) {
+	if err := checkFullyConnected(nw); err != nil {
+		return nil, err
+	}
+
+	bs, err := marshalCompactJson(nw)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err = serialize(bs, ct.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+// MustSerialize calls Serialize but panics if an error is encountered.
+func (ct compactJsonTranslator) MustSerialize(nw Network) []byte {
+	bs, err := ct.Serialize(nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must serialize"))
+	}
+	return bs
+}
+
+func (ct compactJsonTranslator) Deserialize(bs []byte) (Network, error) {
+	bs, err := deserialize(bs, ct.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var cnw compactJsonNetwork
+	if err := json.Unmarshal(bs, &cnw); err != nil {
+		return nil, errors.Wrap(err, "json unmarshalling")
+	}
+	nw, err := fromCompactJson(cnw)
+	if err != nil {
+		return nil, errors.Wrap(err, "from compact json")
+	}
+	return nw, nil
+}
+
+// MustDeserialize calls Deserialize but panics if an error is encountered.
+func (ct compactJsonTranslator) MustDeserialize(bs []byte) Network {
+	nw, err := ct.Deserialize(bs)
+	if err != nil {
+		panic(errors.Wrap(err, "must deserialize"))
+	}
+	return nw
+}
+
+// Encode writes the compact JSON encoding of nw to w, applying ct's options as
+// it is written.
+func (ct compactJsonTranslator) Encode(w io.Writer, nw Network) error {
+	if err := checkFullyConnected(nw); err != nil {
+		return err
+	}
+
+	return encode(w, ct.opts, func(w io.Writer) error {
+		bs, err := marshalCompactJson(nw)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	})
+}
+
+// MustEncode calls Encode but panics if an error is encountered.
+func (ct compactJsonTranslator) MustEncode(w io.Writer, nw Network) {
+	err := ct.Encode(w, nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must encode"))
+	}
+}
+
+// Decode reads a compact JSON encoded Network from r, reversing ct's options
+// as it is read.
+func (ct compactJsonTranslator) Decode(r io.Reader) (Network, error) {
+	return decode(r, ct.opts, func(r io.Reader) (Network, error) {
+		var cnw compactJsonNetwork
+		if err := json.NewDecoder(r).Decode(&cnw); err != nil {
+			return nil, errors.Wrap(err, "json unmarshalling")
+		}
+		nw, err := fromCompactJson(cnw)
+		if err != nil {
+			return nil, errors.Wrap(err, "from compact json")
+		}
+		return nw, nil
+	})
+}
+
+// MustDecode calls Decode but panics if an error is encountered.
+func (ct compactJsonTranslator) MustDecode(rd io.Reader) Network {
+	nw, err := ct.Decode(rd)
+	if err != nil {
+		panic(errors.Wrap(err, "must decode"))
+	}
+	return nw
+}
+
+// marshalCompactJson returns the indented compact JSON encoding of nw,
+// terminated by a newline.
+func marshalCompactJson(nw Network) ([]byte, error) {
+	bs, err := json.MarshalIndent(toCompactJson(nw), "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "json marshalling")
+	}
+	return append(bs, '\n'), nil
+}
+
+func toCompactJson(nw Network) compactJsonNetwork {
+	cnw := compactJsonNetwork{
+		Version:                 compactJsonVersion,
+		NeuronMap:               make([]int, len(nw)),
+		Labels:                  make([][]string, len(nw)),
+		ActivationFunctionNames: make([][]activationfunction.Name, len(nw)),
+		Biases:                  make([][]float64, len(nw)),
+		Weights:                 make([][][]float64, len(nw)),
+		InputTransforms:         nw.InputTransforms(),
+	}
+
+	for li, l := range nw {
+		cnw.NeuronMap[li] = len(l)
+		cnw.Labels[li] = make([]string, len(l))
+		cnw.ActivationFunctionNames[li] = make([]activationfunction.Name, len(l))
+		cnw.Biases[li] = make([]float64, len(l))
+		cnw.Weights[li] = make([][]float64, len(l))
+
+		for ni, n := range l {
+			cnw.Labels[li][ni] = n.label
+			cnw.ActivationFunctionNames[li][ni] = n.ActivationFunctionName
+			cnw.Biases[li][ni] = n.bias
+			cnw.Weights[li][ni] = make([]float64, len(n.Connections))
+
+			for ci, c := range n.Connections {
+				cnw.Weights[li][ni][ci] = c.weight
+			}
+		}
+	}
+
+	return cnw
+}
+
+func fromCompactJson(cnw compactJsonNetwork) (Network, error) {
+	if cnw.Version > compactJsonVersion {
+		return nil, fmt.Errorf("compact json schema version %v is newer than the latest supported version %v", cnw.Version, compactJsonVersion)
+	}
+	if len(cnw.NeuronMap) < 2 {
+		return nil, fmt.Errorf("neuron map must have at least 2 layers, got %v", len(cnw.NeuronMap))
+	}
+	if len(cnw.Labels) != len(cnw.NeuronMap) {
+		return nil, fmt.Errorf("invalid number of sets of labels provided (%v), does not match number of layers in neuron map (%v)", len(cnw.Labels), len(cnw.NeuronMap))
+	}
+	if len(cnw.ActivationFunctionNames) == 0 || len(cnw.ActivationFunctionNames[0]) == 0 {
+		return nil, errors.New("must provide activation function names")
+	}
+
+	nw, err := From(Spec{
+		NeuronMap:              cnw.NeuronMap,
+		InputLabels:            cnw.Labels[0],
+		OutputLabels:           cnw.Labels[len(cnw.Labels)-1],
+		ActivationFunctionName: cnw.ActivationFunctionNames[0][0],
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "building network")
+	}
+
+	if err := nw.SetNeuronLabels(cnw.Labels); err != nil {
+		return nil, errors.Wrap(err, "setting labels")
+	}
+	if err := nw.SetNeuronActivationFunctions(cnw.ActivationFunctionNames); err != nil {
+		return nil, errors.Wrap(err, "setting activation functions")
+	}
+	if err := nw.SetNeuronBiases(cnw.Biases); err != nil {
+		return nil, errors.Wrap(err, "setting biases")
+	}
+	if err := nw.SetConnectionWeights(cnw.Weights); err != nil {
+		return nil, errors.Wrap(err, "setting weights")
+	}
+	if err := nw.SetInputTransforms(cnw.InputTransforms); err != nil {
+		return nil, errors.Wrap(err, "setting input transforms")
+	}
+
+	return nw, nil
+}
@@ -0,0 +1,139 @@
+package network
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_compactJson_Golden(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 1},
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.MustSetNeuronBiases([][]float64{{0, 0}, {0.5}})
+	nw.MustSetConnectionWeights([][][]float64{{{}, {}}, {{0.25, -1}}})
+
+	expected := `{
+  "version": 1,
+  "neuronMap": [
+    2,
+    1
+  ],
+  "labels": [
+    [
+      "a",
+      "b"
+    ],
+    [
+      "x"
+    ]
+  ],
+  "activationFunctionNames": [
+    [
+      "sigmoid",
+      "sigmoid"
+    ],
+    [
+      "sigmoid"
+    ]
+  ],
+  "biases": [
+    [
+      0,
+      0
+    ],
+    [
+      0.5
+    ]
+  ],
+  "weights": [
+    [
+      [],
+      []
+    ],
+    [
+      [
+        0.25,
+        -1
+      ]
+    ]
+  ]
+}
+`
+
+	if got := string(NewCompactJsonTranslator().MustSerialize(nw)); got != expected {
+		t.Fatalf("unexpected compact json encoding:\n%v", got)
+	}
+}
+
+func Test_compactJson_SerializeAndDeserializeAreInverses(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetActivationFunctions([]activationfunction.Name{
+		activationfunction.NameTanh,
+		activationfunction.NameRelu,
+		activationfunction.NameLinear,
+		activationfunction.NameTanh,
+	})
+
+	ct := NewCompactJsonTranslator(WithCompression())
+	s := ct.MustSerialize(nw)
+	nw2 := ct.MustDeserialize(s)
+
+	if s2 := ct.MustSerialize(nw2); string(s) != string(s2) {
+		t.Fatalf("original compact json encoding and deserialized network compact json encoding do not equal each other")
+	}
+	input := []float64{0.1, 0.5, 0.9}
+	if got, want := nw2.MustPredictVector(input), nw.MustPredictVector(input); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected predictions %v, got %v", want, got)
+	}
+}
+
+func Test_compactJson_IgnoresTransientState(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	ct := NewCompactJsonTranslator()
+	before := ct.MustSerialize(nw)
+
+	nw.MustForwardPass([]float64{1, 0})
+	nw.MustBackwardPass([]float64{0, 1})
+	nw.RecordNudges()
+
+	if after := ct.MustSerialize(nw); string(before) != string(after) {
+		t.Fatalf("compact json encoding changed after a pass:\n%s\n%s", before, after)
+	}
+}
+
+func Test_compactJson_InvalidShape(t *testing.T) {
+	tcs := map[string]string{
+		"missing labels":        `{"version":1,"neuronMap":[1,1],"labels":[["a"]],"activationFunctionNames":[["linear"],["linear"]],"biases":[[0],[0]],"weights":[[[]],[[1]]]}`,
+		"too few weights":       `{"version":1,"neuronMap":[1,1],"labels":[["a"],["b"]],"activationFunctionNames":[["linear"],["linear"]],"biases":[[0],[0]],"weights":[[[]],[[]]]}`,
+		"unknown activation":    `{"version":1,"neuronMap":[1,1],"labels":[["a"],["b"]],"activationFunctionNames":[["linear"],["nope"]],"biases":[[0],[0]],"weights":[[[]],[[1]]]}`,
+		"newer schema version":  `{"version":2,"neuronMap":[1,1],"labels":[["a"],["b"]],"activationFunctionNames":[["linear"],["linear"]],"biases":[[0],[0]],"weights":[[[]],[[1]]]}`,
+		"missing activations":   `{"version":1,"neuronMap":[1,1],"labels":[["a"],["b"]],"biases":[[0],[0]],"weights":[[[]],[[1]]]}`,
+		"missing neuron map":    `{"version":1,"activationFunctionNames":[["linear"]]}`,
+		"too many biases layer": `{"version":1,"neuronMap":[1,1],"labels":[["a"],["b"]],"activationFunctionNames":[["linear"],["linear"]],"biases":[[0],[0,1]],"weights":[[[]],[[1]]]}`,
+	}
+
+	for name, j := range tcs {
+		if _, err := NewCompactJsonTranslator().Deserialize([]byte(j)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		} else if !strings.Contains(err.Error(), "compact json") {
+			t.Errorf("%v: unexpected error %v", name, err)
+		}
+	}
+}
@@ -33,13 +33,15 @@ type Connection struct {
 	// training process.
 	dNetDPrevValue float64
 
-	// weightNudges is the set of nudges that would push this Connection's
-	// weight towards having a value which more effectively reduces the output
-	// of the loss function. The average value across this slice is the
-	// direction of greatest descent and is used when gradient descent is
-	// executed. These values are (and should be) lost when resetFromBatch is
-	// called.
-	weightNudges []float64
+	// weightNudgeSum is the running sum of the nudges that would push this
+	// Connection's weight towards having a value which more effectively reduces
+	// the output of the loss function. Divided by nudges, it is the direction
+	// of greatest descent and is used when gradient descent is executed. This
+	// value is (and should be) lost when resetFromBatch is called.
+	weightNudgeSum float64
+	// nudges is the number of weight nudges that have been summed into
+	// weightNudgeSum since the last call to resetFromBatch.
+	nudges int
 }
 
 // NewConnection creates a new connection assigning To to pn, which should be a
@@ -59,14 +61,15 @@ func NewConnection(pn *Neuron) *Connection {
 // This function should always be called after running a full batch/minibatch
 // before you run another one.
 //
-// This function differs from resetFromPass in that it clears c.weightNudges
+// This function differs from resetFromPass in that it clears c.weightNudgeSum
 // while resetFromPass does not.
 func (c *Connection) resetFromBatch() {
 	c.dNetDWeight = 0.0
 	c.dLossDWeight = 0.0
 	c.dNetDPrevValue = 0.0
 
-	c.weightNudges = nil
+	c.weightNudgeSum = 0.0
+	c.nudges = 0
 }
 
 // resetFromPass will reset the internal state of c from the perspective that a
@@ -77,30 +80,32 @@ func (c *Connection) resetFromBatch() {
 // before you run another one.
 //
 // This function differs from resetFromBatch in that it does not clear
-// c.weightNudges while resetFromBatch does.
+// c.weightNudgeSum while resetFromBatch does.
 func (c *Connection) resetFromPass() {
 	c.dNetDWeight = 0.0
 	c.dLossDWeight = 0.0
 	c.dNetDPrevValue = 0.0
 }
 
-// recordNudge records the current value of dLossDWeight as a new entry in
-// c.weightNudges. After a forward + backward pass, dLossDWeight is set to the
-// direction of greatest improvement of the loss function if it were to be
-// applied to c.weight. It should be recorded so that when c.adjustWeights is
-// called, it can be applied to reduce the loss function.
+// recordNudge adds the current value of dLossDWeight to c.weightNudgeSum.
+// After a forward + backward pass, dLossDWeight is set to the direction of
+// greatest improvement of the loss function if it were to be applied to
+// c.weight. It should be recorded so that when c.adjustWeights is called, it
+// can be applied to reduce the loss function. Only a running sum and count are
+// kept, so the memory used does not grow with the number of passes recorded.
 func (c *Connection) recordNudge() {
-	c.weightNudges = append(c.weightNudges, c.dLossDWeight)
+	c.weightNudgeSum += c.dLossDWeight
+	c.nudges++
 }
 
-// averageWeightNudge determines the average value across all values in
-// c.weightNudges.
+// averageWeightNudge determines the average value of all weight nudges recorded
+// since the last call to resetFromBatch. If none have been recorded, it
+// returns 0.
 func (c *Connection) averageWeightNudge() float64 {
-	sum := 0.0
-	for _, wn := range c.weightNudges {
-		sum += wn
+	if c.nudges == 0 {
+		return 0
 	}
-	return sum / float64(len(c.weightNudges))
+	return c.weightNudgeSum / float64(c.nudges)
 }
 
 // adjustWeight adjusts c's weight in the direction of greatest improvement of
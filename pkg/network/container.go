@@ -0,0 +1,417 @@
+package network
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+// ContainerVersion is the schema version of the model files written by the
+// container translator. It is incremented whenever the layout of the header or
+// the meaning of its fields changes, and a Migration from the previous version
+// is registered alongside it.
+const ContainerVersion = 1
+
+// containerMagic prefixes every model file written by the container
+// translator. It is always followed by the schema version as a big endian
+// uint16, both of which will never change so that any version of a model file
+// can be recognized.
+var containerMagic = []byte("JNET")
+
+// ErrChecksumMismatch is returned when the payload of a model file does not
+// match the checksum recorded in its header, indicating the file has been
+// corrupted or modified.
+var ErrChecksumMismatch = errors.New("model file checksum does not match its payload")
+
+// ContainerHeader is the self-describing metadata stored at the start of every
+// model file written by the container translator.
+type ContainerHeader struct {
+	// Version is the schema version the model file was written with.
+	Version int `json:"version"`
+	// Created is when the model file was written.
+	Created time.Time `json:"created"`
+	// Encoding names the translator used to encode the payload, such as
+	// "json", "gob" or "proto".
+	Encoding string `json:"encoding"`
+	// Options names the TranslatorOptions the translator applied to the
+	// payload after encoding it, in the order they were applied, such as
+	// "gzip" then "base64". Options without a Name are recorded as "custom".
+	Options []string `json:"options,omitempty"`
+	// Spec describes the shape and labels of the encoded network.
+	Spec Spec `json:"spec"`
+	// ActivationFunctionNames holds the name of the activation function used
+	// by the first neuron of each layer in the encoded network.
+	ActivationFunctionNames []activationfunction.Name `json:"activationFunctionNames"`
+	// LossFunctionName is the name of the loss function the encoded network
+	// was trained against.
+	LossFunctionName string `json:"lossFunctionName"`
+	// Metadata holds free-form key/value pairs, such as training provenance.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Checksum is the hex encoded SHA-256 digest of the payload.
+	Checksum string `json:"checksum"`
+}
+
+// Container is a model file whose header has been decoded and verified, but
+// whose payload has not yet been deserialized into a Network.
+type Container struct {
+	Header  ContainerHeader
+	Payload []byte
+}
+
+// RawContainer is a model file that has been split into its parts, but whose
+// header has not been interpreted. It is what migrations operate on, since the
+// header of an older version may not decode into the current ContainerHeader.
+type RawContainer struct {
+	Version int
+	Header  []byte
+	Payload []byte
+}
+
+// Migration upgrades a RawContainer written with some schema version to the
+// next schema version. A Migration must set the Version of the RawContainer it
+// returns.
+type Migration func(RawContainer) (RawContainer, error)
+
+var (
+	migrationsMu sync.RWMutex
+	// migrations maps a schema version to the Migration that upgrades it to
+	// the next schema version.
+	migrations = map[int]Migration{
+		0: migrateBarePayload,
+	}
+)
+
+// RegisterMigration registers m as the Migration that upgrades model files
+// written with schema version from to schema version from+1, replacing any
+// Migration previously registered for from.
+//
+// Schema version 0 refers to bare payloads written by a translator without a
+// container. It is migrated by default so that they remain readable.
+func RegisterMigration(from int, m Migration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	migrations[from] = m
+}
+
+// migrateBarePayload upgrades a bare payload, written by a translator without
+// a container, into a version 1 container. Nothing is known about the payload,
+// so the header only records its checksum.
+func migrateBarePayload(rc RawContainer) (RawContainer, error) {
+	h, err := json.Marshal(ContainerHeader{
+		Version:  1,
+		Checksum: checksum(rc.Payload),
+	})
+	if err != nil {
+		return RawContainer{}, errors.Wrap(err, "json marshalling header")
+	}
+	return RawContainer{Version: 1, Header: h, Payload: rc.Payload}, nil
+}
+
+// ContainerOption configures the header written by the container translator.
+type ContainerOption func(*ContainerHeader)
+
+// WithMetadata adds the key/value pair to the free-form metadata stored in the
+// header of every model file written.
+func WithMetadata(key, value string) ContainerOption {
+	return func(h *ContainerHeader) {
+		if h.Metadata == nil {
+			h.Metadata = map[string]string{}
+		}
+		h.Metadata[key] = value
+	}
+}
+
+// WithCreationTime overrides the creation time stored in the header of every
+// model file written, which otherwise defaults to the time of serialization.
+// This is useful when reproducible output is required.
+func WithCreationTime(t time.Time) ContainerOption {
+	return func(h *ContainerHeader) {
+		h.Created = t
+	}
+}
+
+type containerTranslator struct {
+	t    Translator
+	opts []ContainerOption
+}
+
+var _ Translator = new(containerTranslator)
+
+// NewContainerTranslator returns a Translator which wraps the payload produced
+// by t in a versioned, self-describing model file. The header of the model file
+// records the schema version, creation time, Spec, activation and loss function
+// names, the names of the TranslatorOptions of t, any metadata provided via
+// opts, and a checksum of the payload. When
+// deserializing, older schema versions are upgraded via their registered
+// migrations and the checksum is verified before the payload is handed to t.
+func NewContainerTranslator(t Translator, opts ...ContainerOption) Translator {
+	return containerTranslator{
+		t:    t,
+		opts: opts,
+	}
+}
+
+func (ct containerTranslator) Serialize(nw Network) ([]byte, error) {
+	payload, err := ct.t.Serialize(nw)
+	if err != nil {
+		return nil, errors.Wrap(err, "container payload")
+	}
+
+	h := ContainerHeader{
+		Version:          ContainerVersion,
+		Created:          time.Now().UTC(),
+		Encoding:         translatorEncoding(ct.t),
+		Options:          translatorOptionNames(ct.t),
+		Spec:             nw.Spec(),
+		LossFunctionName: LossFunctionName,
+		Checksum:         checksum(payload),
+	}
+	for _, l := range nw {
+		var afn activationfunction.Name
+		if len(l) > 0 {
+			afn = l[0].ActivationFunctionName
+		}
+		h.ActivationFunctionNames = append(h.ActivationFunctionNames, afn)
+	}
+	for _, opt := range ct.opts {
+		opt(&h)
+	}
+
+	hbs, err := json.Marshal(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "json marshalling header")
+	}
+
+	var b bytes.Buffer
+	b.Write(containerMagic)
+	_ = binary.Write(&b, binary.BigEndian, uint16(ContainerVersion))
+	_ = binary.Write(&b, binary.BigEndian, uint32(len(hbs)))
+	b.Write(hbs)
+	b.Write(payload)
+
+	return b.Bytes(), nil
+}
+
+// MustSerialize calls Serialize but panics if an error is encountered.
+func (ct containerTranslator) MustSerialize(nw Network) []byte {
+	bs, err := ct.Serialize(nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must serialize"))
+	}
+	return bs
+}
+
+func (ct containerTranslator) Deserialize(bs []byte) (Network, error) {
+	c, err := ReadContainer(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	nw, err := ct.t.Deserialize(c.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "container payload")
+	}
+	return nw, nil
+}
+
+// MustDeserialize calls Deserialize but panics if an error is encountered.
+func (ct containerTranslator) MustDeserialize(bs []byte) Network {
+	nw, err := ct.Deserialize(bs)
+	if err != nil {
+		panic(errors.Wrap(err, "must deserialize"))
+	}
+	return nw
+}
+
+// Encode writes the model file for nw to w.
+//
+// NOTE: The header records a checksum of the payload, and so must be written
+// after the payload has been produced in its entirety. Because of this the
+// payload is held in memory before being written.
+func (ct containerTranslator) Encode(w io.Writer, nw Network) error {
+	bs, err := ct.Serialize(nw)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bs)
+	return err
+}
+
+// MustEncode calls Encode but panics if an error is encountered.
+func (ct containerTranslator) MustEncode(w io.Writer, nw Network) {
+	err := ct.Encode(w, nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must encode"))
+	}
+}
+
+// Decode reads a model file from r.
+//
+// NOTE: The checksum of the payload must be verified before it is handed to
+// the wrapped translator, so the model file is held in memory before being
+// decoded.
+func (ct containerTranslator) Decode(r io.Reader) (Network, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading")
+	}
+	return ct.Deserialize(bs)
+}
+
+// MustDecode calls Decode but panics if an error is encountered.
+func (ct containerTranslator) MustDecode(r io.Reader) Network {
+	nw, err := ct.Decode(r)
+	if err != nil {
+		panic(errors.Wrap(err, "must decode"))
+	}
+	return nw
+}
+
+// ReadContainer decodes the model file in bs without deserializing its
+// payload, which allows its header to be inspected. Model files written with an
+// older schema version are upgraded via their registered migrations, and an
+// ErrChecksumMismatch is returned if the payload has been modified.
+//
+// Bytes which do not start with the container's magic header are treated as a
+// bare payload of schema version 0.
+func ReadContainer(bs []byte) (Container, error) {
+	rc, err := splitContainer(bs)
+	if err != nil {
+		return Container{}, err
+	}
+
+	for rc.Version < ContainerVersion {
+		migrationsMu.RLock()
+		m, found := migrations[rc.Version]
+		migrationsMu.RUnlock()
+		if !found {
+			return Container{}, fmt.Errorf("no migration registered for model file schema version %v", rc.Version)
+		}
+
+		from := rc.Version
+		rc, err = m(rc)
+		if err != nil {
+			return Container{}, errors.Wrapf(err, "migrating model file from schema version %v", from)
+		}
+		if rc.Version <= from {
+			return Container{}, fmt.Errorf("migration from model file schema version %v did not advance the version", from)
+		}
+	}
+	if rc.Version > ContainerVersion {
+		return Container{}, fmt.Errorf("model file schema version %v is newer than the latest supported version %v", rc.Version, ContainerVersion)
+	}
+
+	var h ContainerHeader
+	if err := json.Unmarshal(rc.Header, &h); err != nil {
+		return Container{}, errors.Wrap(err, "json unmarshalling header")
+	}
+	if h.Checksum != checksum(rc.Payload) {
+		return Container{}, ErrChecksumMismatch
+	}
+
+	return Container{Header: h, Payload: rc.Payload}, nil
+}
+
+// MustReadContainer calls ReadContainer but panics if an error is encountered.
+func MustReadContainer(bs []byte) Container {
+	c, err := ReadContainer(bs)
+	if err != nil {
+		panic(errors.Wrap(err, "must read container"))
+	}
+	return c
+}
+
+// isContainer reports whether bs starts with the container's magic header.
+func isContainer(bs []byte) bool {
+	return bytes.HasPrefix(bs, containerMagic)
+}
+
+// splitContainer splits bs into its schema version, header and payload.
+func splitContainer(bs []byte) (RawContainer, error) {
+	if !isContainer(bs) {
+		return RawContainer{Version: 0, Payload: bs}, nil
+	}
+
+	r := bytes.NewReader(bs[len(containerMagic):])
+
+	var version uint16
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return RawContainer{}, errors.Wrap(err, "reading model file schema version")
+	}
+	var hl uint32
+	if err := binary.Read(r, binary.BigEndian, &hl); err != nil {
+		return RawContainer{}, errors.Wrap(err, "reading model file header length")
+	}
+	if uint64(hl) > uint64(r.Len()) {
+		return RawContainer{}, fmt.Errorf("model file header length (%v) exceeds remaining file length (%v)", hl, r.Len())
+	}
+
+	rest := bs[len(bs)-r.Len():]
+	return RawContainer{
+		Version: int(version),
+		Header:  rest[:hl],
+		Payload: rest[hl:],
+	}, nil
+}
+
+// checksum returns the hex encoded SHA-256 digest of bs.
+func checksum(bs []byte) string {
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
+
+// translatorOptionNames returns the name of every TranslatorOption t applies,
+// in order.
+func translatorOptionNames(t Translator) []string {
+	var opts []TranslatorOption
+	switch t := t.(type) {
+	case gobTranslator:
+		opts = t.opts
+	case jsonTranslator:
+		opts = t.opts
+	case compactJsonTranslator:
+		opts = t.opts
+	case protoTranslator:
+		opts = t.opts
+	case onnxTranslator:
+		opts = t.opts
+	}
+
+	var names []string
+	for _, opt := range opts {
+		name := opt.Name
+		if name == "" {
+			name = "custom"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// translatorEncoding returns the name of the encoding produced by t.
+func translatorEncoding(t Translator) string {
+	switch t.(type) {
+	case gobTranslator:
+		return "gob"
+	case jsonTranslator:
+		return "json"
+	case compactJsonTranslator:
+		return "compact-json"
+	case protoTranslator:
+		return "proto"
+	case onnxTranslator:
+		return "onnx"
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
@@ -0,0 +1,108 @@
+package network
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_container_SerializeAndDeserializeAreInverses(t *testing.T) {
+	spec := Spec{
+		NeuronMap:              []int{3, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameTanh,
+	}
+	nw := MustFrom(spec)
+
+	created := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	ct := NewContainerTranslator(NewProtoTranslator(WithCompression()), WithCreationTime(created), WithMetadata("dataset", "digits"))
+	s := ct.MustSerialize(nw)
+	nw2 := ct.MustDeserialize(s)
+	s2 := ct.MustSerialize(nw2)
+
+	if err := nw.Equals(nw2); err != nil {
+		t.Fatalf("original network and deserialized network do not equal each other: %v", err)
+	}
+	if string(s) != string(s2) {
+		t.Fatalf("original container encoding and deserialized network container encoding do not equal each other")
+	}
+
+	h := MustReadContainer(s).Header
+	if h.Version != ContainerVersion {
+		t.Fatalf("expected version %v, got %v", ContainerVersion, h.Version)
+	}
+	if !h.Created.Equal(created) {
+		t.Fatalf("expected creation time %v, got %v", created, h.Created)
+	}
+	if h.Encoding != "proto" {
+		t.Fatalf("expected proto encoding, got %q", h.Encoding)
+	}
+	if !reflect.DeepEqual(h.Options, []string{"gzip"}) {
+		t.Fatalf("expected gzip option, got %v", h.Options)
+	}
+	if !reflect.DeepEqual(h.Spec, spec) {
+		t.Fatalf("expected spec %+v, got %+v", spec, h.Spec)
+	}
+	afns := []activationfunction.Name{activationfunction.NameTanh, activationfunction.NameTanh, activationfunction.NameTanh}
+	if !reflect.DeepEqual(h.ActivationFunctionNames, afns) {
+		t.Fatalf("expected activation function names %v, got %v", afns, h.ActivationFunctionNames)
+	}
+	if h.LossFunctionName != LossFunctionName {
+		t.Fatalf("expected loss function name %q, got %q", LossFunctionName, h.LossFunctionName)
+	}
+	if h.Metadata["dataset"] != "digits" {
+		t.Fatalf("expected metadata to be preserved, got %v", h.Metadata)
+	}
+}
+
+func Test_container_DetectsTampering(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	ct := NewContainerTranslator(NewJsonTranslator())
+	s := ct.MustSerialize(nw)
+	s[len(s)-2] ^= 0xff
+
+	if _, err := ct.Deserialize(s); errors.Cause(err) != ErrChecksumMismatch {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+}
+
+func Test_container_MigratesBarePayloads(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	jt := NewJsonTranslator()
+	nw2 := NewContainerTranslator(jt).MustDeserialize(jt.MustSerialize(nw))
+
+	if err := nw.Equals(nw2); err != nil {
+		t.Fatalf("bare payload was not migrated: %v", err)
+	}
+}
+
+func Test_container_RejectsNewerVersions(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	s := NewContainerTranslator(NewJsonTranslator()).MustSerialize(nw)
+	binary.BigEndian.PutUint16(s[len(containerMagic):], ContainerVersion+1)
+
+	if _, err := ReadContainer(s); err == nil {
+		t.Fatalf("expected an error reading a newer schema version")
+	}
+}
@@ -0,0 +1,155 @@
+package network
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// DecryptionError is returned when bytes can't be decrypted by WithEncryption,
+// either because the wrong key was provided or because the bytes have been
+// tampered with. AES-GCM can't tell these apart.
+type DecryptionError struct {
+	Err error
+}
+
+func (e *DecryptionError) Error() string {
+	return fmt.Sprintf("decrypting: %v", e.Err)
+}
+
+func (e *DecryptionError) Unwrap() error {
+	return e.Err
+}
+
+// SignatureError is returned when bytes fail verification by WithSignature or
+// WithVerification, either because they have been tampered with, were signed
+// by a different key, or were never signed at all.
+type SignatureError struct {
+	Reason string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("invalid signature: %v", e.Reason)
+}
+
+// WithEncryption encrypts and authenticates the serialized bytes with AES-GCM
+// using key, which must be 16, 24 or 32 bytes long to select AES-128, AES-192
+// or AES-256 respectively. A random nonce is generated for every
+// serialization and is prepended to the ciphertext.
+//
+// Deserializing with the wrong key, or bytes that have been modified, fails
+// with a *DecryptionError.
+func WithEncryption(key []byte) TranslatorOption {
+	return TranslatorOption{
+		Name: "aes-gcm",
+		Serialize: func(bs []byte) ([]byte, error) {
+			aead, err := newGCM(key)
+			if err != nil {
+				return nil, err
+			}
+
+			nonce := make([]byte, aead.NonceSize())
+			if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+				return nil, errors.Wrap(err, "generating nonce")
+			}
+
+			return aead.Seal(nonce, nonce, bs, nil), nil
+		},
+		Deserialize: func(bs []byte) ([]byte, error) {
+			aead, err := newGCM(key)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(bs) < aead.NonceSize() {
+				return nil, &DecryptionError{Err: fmt.Errorf("ciphertext length (%v) is shorter than the nonce (%v)", len(bs), aead.NonceSize())}
+			}
+			nonce, ciphertext := bs[:aead.NonceSize()], bs[aead.NonceSize():]
+
+			bs, err = aead.Open(nil, nonce, ciphertext, nil)
+			if err != nil {
+				return nil, &DecryptionError{Err: err}
+			}
+			return bs, nil
+		},
+	}
+}
+
+// newGCM returns an AES-GCM AEAD for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "aes new cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "gcm new")
+	}
+	return aead, nil
+}
+
+// WithSignature appends an ed25519 signature of the serialized bytes, made
+// with privateKey, to the end of them. When deserializing, the signature is
+// verified against the public key of privateKey and removed.
+//
+// Deserializing bytes whose signature does not verify fails with a
+// *SignatureError. Use WithVerification where only the public key is
+// available.
+func WithSignature(privateKey ed25519.PrivateKey) TranslatorOption {
+	return TranslatorOption{
+		Name: "ed25519",
+		Serialize: func(bs []byte) ([]byte, error) {
+			if len(privateKey) != ed25519.PrivateKeySize {
+				return nil, fmt.Errorf("invalid ed25519 private key length (%v), must be %v", len(privateKey), ed25519.PrivateKeySize)
+			}
+			sig := ed25519.Sign(privateKey, bs)
+			return append(append([]byte(nil), bs...), sig...), nil
+		},
+		Deserialize: func(bs []byte) ([]byte, error) {
+			if len(privateKey) != ed25519.PrivateKeySize {
+				return nil, fmt.Errorf("invalid ed25519 private key length (%v), must be %v", len(privateKey), ed25519.PrivateKeySize)
+			}
+			return verify(privateKey.Public().(ed25519.PublicKey), bs)
+		},
+	}
+}
+
+// WithVerification verifies and removes the ed25519 signature appended by
+// WithSignature when deserializing, using publicKey. It can't sign, so
+// serializing with it always fails.
+//
+// Deserializing bytes whose signature does not verify fails with a
+// *SignatureError.
+func WithVerification(publicKey ed25519.PublicKey) TranslatorOption {
+	return TranslatorOption{
+		Name: "ed25519",
+		Serialize: func(bs []byte) ([]byte, error) {
+			return nil, errors.New("can't sign with only a public key, use WithSignature instead")
+		},
+		Deserialize: func(bs []byte) ([]byte, error) {
+			if len(publicKey) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid ed25519 public key length (%v), must be %v", len(publicKey), ed25519.PublicKeySize)
+			}
+			return verify(publicKey, bs)
+		},
+	}
+}
+
+// verify checks the ed25519 signature at the end of bs against publicKey, and
+// returns bs without it.
+func verify(publicKey ed25519.PublicKey, bs []byte) ([]byte, error) {
+	if len(bs) < ed25519.SignatureSize {
+		return nil, &SignatureError{Reason: fmt.Sprintf("length (%v) is shorter than a signature (%v)", len(bs), ed25519.SignatureSize)}
+	}
+
+	msg, sig := bs[:len(bs)-ed25519.SignatureSize], bs[len(bs)-ed25519.SignatureSize:]
+	if !ed25519.Verify(publicKey, msg, sig) {
+		return nil, &SignatureError{Reason: "signature does not match the public key"}
+	}
+	return msg, nil
+}
@@ -0,0 +1,118 @@
+package network
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_WithEncryption(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	key := bytes.Repeat([]byte{7}, 32)
+
+	pt := NewProtoTranslator(WithCompression(), WithEncryption(key))
+	s := pt.MustSerialize(nw)
+
+	if bytes.Contains(s, []byte("sigmoid")) {
+		t.Fatalf("encrypted bytes contain plaintext")
+	}
+	if s2 := pt.MustSerialize(nw); bytes.Equal(s, s2) {
+		t.Fatalf("expected a fresh nonce for every serialization")
+	}
+
+	if err := pt.MustDeserialize(s).Equals(nw); err != nil {
+		t.Fatalf("decrypted network does not equal original: %v", err)
+	}
+
+	var de *DecryptionError
+	_, err := NewProtoTranslator(WithCompression(), WithEncryption(bytes.Repeat([]byte{8}, 32))).Deserialize(s)
+	if !errors.As(err, &de) {
+		t.Fatalf("expected a DecryptionError for the wrong key, got %v", err)
+	}
+
+	s[len(s)-1] ^= 1
+	if _, err := pt.Deserialize(s); !errors.As(err, &de) {
+		t.Fatalf("expected a DecryptionError for tampered bytes, got %v", err)
+	}
+
+	if _, err := pt.Deserialize(s[:4]); !errors.As(err, &de) {
+		t.Fatalf("expected a DecryptionError for truncated bytes, got %v", err)
+	}
+
+	if _, err := NewProtoTranslator(WithEncryption([]byte("short"))).Serialize(nw); err == nil {
+		t.Fatalf("expected an error for an invalid key length")
+	}
+}
+
+func Test_WithSignature(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	seed := bytes.Repeat([]byte{1}, ed25519.SeedSize)
+	priv := ed25519.NewKeyFromSeed(seed)
+	pub := priv.Public().(ed25519.PublicKey)
+
+	s := NewJsonTranslator(WithSignature(priv)).MustSerialize(nw)
+
+	for name, jt := range map[string]Translator{
+		"signature":    NewJsonTranslator(WithSignature(priv)),
+		"verification": NewJsonTranslator(WithVerification(pub)),
+	} {
+		if err := jt.MustDeserialize(s).Equals(nw); err != nil {
+			t.Fatalf("%v: verified network does not equal original: %v", name, err)
+		}
+	}
+
+	var se *SignatureError
+
+	other := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+	if _, err := NewJsonTranslator(WithVerification(other.Public().(ed25519.PublicKey))).Deserialize(s); !errors.As(err, &se) {
+		t.Fatalf("expected a SignatureError for the wrong key, got %v", err)
+	}
+
+	tampered := append([]byte(nil), s...)
+	tampered[0] = ' '
+	if _, err := NewJsonTranslator(WithVerification(pub)).Deserialize(tampered); !errors.As(err, &se) {
+		t.Fatalf("expected a SignatureError for tampered bytes, got %v", err)
+	}
+
+	if _, err := NewJsonTranslator(WithVerification(pub)).Deserialize(NewJsonTranslator().MustSerialize(nw)[:10]); !errors.As(err, &se) {
+		t.Fatalf("expected a SignatureError for unsigned bytes, got %v", err)
+	}
+
+	if _, err := NewJsonTranslator(WithVerification(pub)).Serialize(nw); err == nil {
+		t.Fatalf("expected an error signing with only a public key")
+	}
+}
+
+func Test_WithSignatureAndEncryption_Stream(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	key := bytes.Repeat([]byte{3}, 16)
+	priv := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{4}, ed25519.SeedSize))
+
+	pt := NewProtoTranslator(WithCompression(), WithEncryption(key), WithSignature(priv))
+
+	var b bytes.Buffer
+	pt.MustEncode(&b, nw)
+
+	verifier := NewProtoTranslator(WithCompression(), WithEncryption(key), WithVerification(priv.Public().(ed25519.PublicKey)))
+	if err := verifier.MustDecode(&b).Equals(nw); err != nil {
+		t.Fatalf("decoded network does not equal original: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package network
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Encoding names a single layer of encoding that may be applied to a
+// serialized Network, either by a Translator or a TranslatorOption.
+type Encoding string
+
+const (
+	EncodingContainer   Encoding = "container"
+	EncodingGzip        Encoding = "gzip"
+	EncodingZlib        Encoding = "zlib"
+	EncodingBase64      Encoding = "base64"
+	EncodingJSON        Encoding = "json"
+	EncodingCompactJSON Encoding = "compact-json"
+	EncodingGob         Encoding = "gob"
+	EncodingProto       Encoding = "proto"
+)
+
+// maxDetectDepth bounds the number of encodings Detect will peel off before
+// giving up, so that pathological input can't keep it busy forever.
+const maxDetectDepth = 16
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Detect deserializes bs into a Network without needing to know which
+// Translator or TranslatorOptions produced it. Encodings are sniffed from the
+// contents of bs and peeled off one at a time until a Network is found.
+//
+// The detected encodings are returned outermost first. For bytes produced by
+// NewProtoTranslator(WithCompression(), WithBase64()) that would be
+// []Encoding{EncodingBase64, EncodingGzip, EncodingProto}, which is the reverse
+// of the order the options were provided in followed by the translator.
+//
+// Of the codecs usable via WithCodec, only gzip and zlib carry a header that
+// can be recognized. Raw flate and lzw can't be detected.
+//
+// If no supported encoding can be recognized, an error is returned.
+func Detect(bs []byte) (Network, []Encoding, error) {
+	var es []Encoding
+
+	for depth := 0; depth < maxDetectDepth; depth++ {
+		e, nbs, nw, err := detectOne(bs)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "detecting encoding %v", depth)
+		}
+		es = append(es, e)
+		if nw != nil {
+			return nw, es, nil
+		}
+		bs = nbs
+	}
+
+	return nil, nil, fmt.Errorf("gave up detecting encodings after %v layers: %v", maxDetectDepth, es)
+}
+
+// MustDetect calls Detect but panics if an error is encountered.
+func MustDetect(bs []byte) (Network, []Encoding) {
+	nw, es, err := Detect(bs)
+	if err != nil {
+		panic(errors.Wrap(err, "must detect"))
+	}
+	return nw, es
+}
+
+// Load reads r to completion and deserializes its contents into a Network via
+// Detect.
+func Load(r io.Reader) (Network, []Encoding, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reading")
+	}
+	return Detect(bs)
+}
+
+// MustLoad calls Load but panics if an error is encountered.
+func MustLoad(r io.Reader) (Network, []Encoding) {
+	nw, es, err := Load(r)
+	if err != nil {
+		panic(errors.Wrap(err, "must load"))
+	}
+	return nw, es
+}
+
+// detectOne recognizes the outermost encoding of bs. If that encoding wraps
+// further bytes, they are returned with a nil Network. If it is a translator
+// encoding, the deserialized Network is returned instead.
+func detectOne(bs []byte) (Encoding, []byte, Network, error) {
+	switch {
+	case isContainer(bs):
+		c, err := ReadContainer(bs)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return EncodingContainer, c.Payload, nil, nil
+
+	case bytes.HasPrefix(bs, gzipMagic):
+		nbs, err := WithCompression().Deserialize(bs)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return EncodingGzip, nbs, nil, nil
+
+	case bytes.HasPrefix(bytes.TrimSpace(bs), []byte("[")):
+		nw, err := NewJsonTranslator().Deserialize(bs)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return EncodingJSON, nil, nw, nil
+
+	case bytes.HasPrefix(bytes.TrimSpace(bs), []byte("{")):
+		nw, err := NewCompactJsonTranslator().Deserialize(bs)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return EncodingCompactJSON, nil, nw, nil
+
+	case isBase64(bs):
+		nbs, err := WithBase64().Deserialize(bytes.TrimSpace(bs))
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return EncodingBase64, nbs, nil, nil
+	}
+
+	// NOTE: A zlib header is only two bytes long and is checked last, so it
+	// is only trusted if what follows it actually decompresses.
+	if isZlib(bs) {
+		if nbs, err := WithCodec(CodecZlib).Deserialize(bs); err == nil {
+			return EncodingZlib, nbs, nil, nil
+		}
+	}
+
+	// NOTE: Gob and proto have no magic number of their own, so the only way
+	// to recognize them is to try decoding. Gob is attempted first because it
+	// is self-describing and strictly type checked, whereas almost any bytes
+	// are a syntactically valid proto message.
+	if nw, err := NewGobTranslator().Deserialize(bs); err == nil && len(nw) > 0 {
+		return EncodingGob, nil, nw, nil
+	}
+	if nw, err := NewProtoTranslator().Deserialize(bs); err == nil && len(nw) > 0 {
+		return EncodingProto, nil, nw, nil
+	}
+
+	return "", nil, nil, errors.New("unrecognized encoding")
+}
+
+// isZlib reports whether bs starts with a valid zlib header using the deflate
+// compression method.
+func isZlib(bs []byte) bool {
+	if len(bs) < 2 {
+		return false
+	}
+	return bs[0]&0x0f == 8 && bs[0]>>4 <= 7 && (uint16(bs[0])<<8|uint16(bs[1]))%31 == 0
+}
+
+// isBase64 reports whether bs, ignoring surrounding whitespace, is non-empty
+// and made up entirely of characters from the standard base64 alphabet with
+// valid padding.
+func isBase64(bs []byte) bool {
+	bs = bytes.TrimSpace(bs)
+	if len(bs) == 0 || len(bs)%4 != 0 {
+		return false
+	}
+
+	for i, b := range bs {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '+', b == '/':
+		case b == '=' && i >= len(bs)-2:
+		default:
+			return false
+		}
+	}
+
+	_, err := base64.StdEncoding.DecodeString(string(bs))
+	return err == nil
+}
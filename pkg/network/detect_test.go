@@ -0,0 +1,79 @@
+package network
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_Detect(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	tcs := []struct {
+		name     string
+		t        Translator
+		expected []Encoding
+	}{
+		{"json", NewJsonTranslator(), []Encoding{EncodingJSON}},
+		{"compact json", NewCompactJsonTranslator(), []Encoding{EncodingCompactJSON}},
+		{"proto", NewProtoTranslator(), []Encoding{EncodingProto}},
+		{"gob", NewGobTranslator(), []Encoding{EncodingGob}},
+		{"compressed json", NewJsonTranslator(WithCompression()), []Encoding{EncodingGzip, EncodingJSON}},
+		{"base64 json", NewJsonTranslator(WithBase64()), []Encoding{EncodingBase64, EncodingJSON}},
+		{"zlib proto", NewProtoTranslator(WithCodec(CodecZlib)), []Encoding{EncodingZlib, EncodingProto}},
+		{"best zlib base64 gob", NewGobTranslator(WithCodec(CodecZlib, WithCompressionLevel(9)), WithBase64()), []Encoding{EncodingBase64, EncodingZlib, EncodingGob}},
+		{"compressed base64 proto", NewProtoTranslator(WithCompression(), WithBase64()), []Encoding{EncodingBase64, EncodingGzip, EncodingProto}},
+		{"base64 compressed gob", NewGobTranslator(WithBase64(), WithCompression()), []Encoding{EncodingGzip, EncodingBase64, EncodingGob}},
+		{"container", NewContainerTranslator(NewProtoTranslator(WithCompression())), []Encoding{EncodingContainer, EncodingGzip, EncodingProto}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			nw2, es := MustLoad(bytes.NewReader(tc.t.MustSerialize(nw)))
+
+			if !reflect.DeepEqual(es, tc.expected) {
+				t.Fatalf("expected encodings %v, got %v", tc.expected, es)
+			}
+
+			// NOTE: Gob only encodes exported fields, so only the shape of the
+			// network survives it.
+			if tc.expected[len(tc.expected)-1] == EncodingGob {
+				if !reflect.DeepEqual(nw2.Spec().NeuronMap, nw.Spec().NeuronMap) {
+					t.Fatalf("expected neuron map %v, got %v", nw.Spec().NeuronMap, nw2.Spec().NeuronMap)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(nw.Spec(), nw2.Spec()) {
+				t.Fatalf("expected spec %+v, got %+v", nw.Spec(), nw2.Spec())
+			}
+			for li := range nw {
+				for ni := range nw[li] {
+					if nw[li][ni].bias != nw2[li][ni].bias {
+						t.Fatalf("bias of layer %v neuron %v does not match", li, ni)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_Detect_UnrecognizedEncoding(t *testing.T) {
+	if _, _, err := Detect([]byte("not a network")); err == nil {
+		t.Fatalf("expected an error for unrecognized bytes")
+	}
+}
+
+func Test_Detect_InvalidCompactJson(t *testing.T) {
+	if _, _, err := Detect([]byte(`{"version":1,"activationFunctionNames":[["linear"]]}`)); err == nil {
+		t.Fatalf("expected an error for compact json without a neuron map")
+	}
+}
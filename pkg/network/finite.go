@@ -0,0 +1,85 @@
+package network
+
+import (
+	"fmt"
+	"math"
+)
+
+// NonFiniteError is returned when a value in a Network or Gradients is found to
+// be NaN or infinite, which almost always indicates that training has diverged.
+// It identifies exactly where the offending value lives.
+type NonFiniteError struct {
+	// Layer is the index of the layer containing the offending value.
+	Layer int
+	// Neuron is the index of the neuron in Layer containing the offending
+	// value.
+	Neuron int
+	// Connection is the index of the connection in Neuron containing the
+	// offending value. It is -1 if the value does not belong to a connection.
+	Connection int
+	// Field names the kind of value that was found to be non-finite, such as
+	// "value", "bias", "weight", "bias gradient" or "weight gradient".
+	Field string
+	// Value is the offending value itself.
+	Value float64
+}
+
+func (e *NonFiniteError) Error() string {
+	if e.Connection < 0 {
+		return fmt.Sprintf("non-finite %v (%v) in layer %v neuron %v", e.Field, e.Value, e.Layer, e.Neuron)
+	}
+	return fmt.Sprintf("non-finite %v (%v) in layer %v neuron %v connection %v", e.Field, e.Value, e.Layer, e.Neuron, e.Connection)
+}
+
+// CheckFinite scans nw from its input layer towards its output layer and
+// returns a *NonFiniteError describing the first bias, weight or neuron value
+// that is NaN or infinite. Because a forward pass carries values in the same
+// direction, the reported location is where a blowup first occurred. If every
+// value is finite, nil is returned.
+func (nw Network) CheckFinite() error {
+	for li, l := range nw {
+		for ni, n := range l {
+			if !isFinite(n.bias) {
+				return &NonFiniteError{Layer: li, Neuron: ni, Connection: -1, Field: "bias", Value: n.bias}
+			}
+			for ci, c := range n.Connections {
+				if !isFinite(c.weight) {
+					return &NonFiniteError{Layer: li, Neuron: ni, Connection: ci, Field: "weight", Value: c.weight}
+				}
+			}
+			if !isFinite(n.value) {
+				return &NonFiniteError{Layer: li, Neuron: ni, Connection: -1, Field: "value", Value: n.value}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckFinite scans g from its output layer towards its input layer and returns
+// a *NonFiniteError describing the first gradient that is NaN or infinite.
+// Because a backward pass carries gradients in the same direction, the reported
+// location is where a blowup first occurred. If every gradient is finite, nil
+// is returned.
+func (g Gradients) CheckFinite() error {
+	for li := len(g.Biases) - 1; li >= 0; li-- {
+		for ni := range g.Biases[li] {
+			if v := g.Biases[li][ni]; !isFinite(v) {
+				return &NonFiniteError{Layer: li, Neuron: ni, Connection: -1, Field: "bias gradient", Value: v}
+			}
+			if li >= len(g.Weights) || ni >= len(g.Weights[li]) {
+				continue
+			}
+			for ci, v := range g.Weights[li][ni] {
+				if !isFinite(v) {
+					return &NonFiniteError{Layer: li, Neuron: ni, Connection: ci, Field: "weight gradient", Value: v}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isFinite reports whether v is neither NaN nor infinite.
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
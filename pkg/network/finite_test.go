@@ -0,0 +1,60 @@
+package network
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_CheckFinite_ReportsFirstBlowup(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.SetConnectionWeightsTo(1)
+
+	nw.MustForwardPass([]float64{1, 1})
+	if err := nw.CheckFinite(); err != nil {
+		t.Fatalf("expected finite network, got %v", err)
+	}
+
+	nw.ResetFromPass()
+	nw[1][1].MustSetConnectionWeights([]float64{math.MaxFloat64, math.MaxFloat64})
+	nw.MustForwardPass([]float64{1, 1})
+
+	var nfe *NonFiniteError
+	if err := nw.CheckFinite(); !errors.As(err, &nfe) {
+		t.Fatalf("expected a non-finite error, got %v", err)
+	}
+	if nfe.Layer != 1 || nfe.Neuron != 1 || nfe.Field != "value" {
+		t.Fatalf("expected blowup in value of layer 1 neuron 1, got %v", nfe)
+	}
+}
+
+func Test_Gradients_CheckFinite(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+
+	g := NewGradients(nw)
+	if err := g.CheckFinite(); err != nil {
+		t.Fatalf("expected finite gradients, got %v", err)
+	}
+
+	g.Weights[1][0][1] = math.NaN()
+	g.Biases[2][1] = math.Inf(1)
+
+	var nfe *NonFiniteError
+	if err := g.CheckFinite(); !errors.As(err, &nfe) {
+		t.Fatalf("expected a non-finite error, got %v", err)
+	}
+	if nfe.Layer != 2 || nfe.Neuron != 1 || nfe.Field != "bias gradient" {
+		t.Fatalf("expected blowup in bias gradient of layer 2 neuron 1, got %v", nfe)
+	}
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"bytes"
 	"encoding/gob"
+	"io"
 
 	"github.com/pkg/errors"
 
@@ -26,6 +27,10 @@ func NewGobTranslator(opts ...TranslatorOption) Translator {
 }
 
 func (gt gobTranslator) Serialize(nw Network) ([]byte, error) {
+	if err := checkFullyConnected(nw); err != nil {
+		return nil, err
+	}
+
 	var b bytes.Buffer
 	err := gob.NewEncoder(&b).Encode(nw)
 	if err != nil {
@@ -33,11 +38,9 @@ func (gt gobTranslator) Serialize(nw Network) ([]byte, error) {
 	}
 	bs := b.Bytes()
 
-	for i, opt := range gt.opts {
-		bs, err = opt.Serialize(bs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "translator serialize option %v", i)
-		}
+	bs, err = serialize(bs, gt.opts)
+	if err != nil {
+		return nil, err
 	}
 
 	return bs, nil
@@ -53,12 +56,9 @@ func (gt gobTranslator) MustSerialize(nw Network) []byte {
 }
 
 func (gt gobTranslator) Deserialize(bs []byte) (Network, error) {
-	var err error
-	for i, opt := range gt.opts {
-		bs, err = opt.Deserialize(bs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "translator deserialize option %v", i)
-		}
+	bs, err := deserialize(bs, gt.opts)
+	if err != nil {
+		return nil, err
 	}
 
 	var nw Network
@@ -77,3 +77,47 @@ func (gt gobTranslator) MustDeserialize(bs []byte) Network {
 	}
 	return nw
 }
+
+// Encode writes the gob encoding of nw to w, applying gt's options as it is
+// written.
+func (gt gobTranslator) Encode(w io.Writer, nw Network) error {
+	if err := checkFullyConnected(nw); err != nil {
+		return err
+	}
+
+	return encode(w, gt.opts, func(w io.Writer) error {
+		if err := gob.NewEncoder(w).Encode(nw); err != nil {
+			return errors.Wrap(err, "gob marshalling")
+		}
+		return nil
+	})
+}
+
+// MustEncode calls Encode but panics if an error is encountered.
+func (gt gobTranslator) MustEncode(w io.Writer, nw Network) {
+	err := gt.Encode(w, nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must encode"))
+	}
+}
+
+// Decode reads a gob encoded Network from r, reversing gt's options as it is
+// read.
+func (gt gobTranslator) Decode(r io.Reader) (Network, error) {
+	return decode(r, gt.opts, func(r io.Reader) (Network, error) {
+		var nw Network
+		if err := gob.NewDecoder(r).Decode(&nw); err != nil {
+			return Network{}, errors.Wrap(err, "gob unmarshalling")
+		}
+		return nw, nil
+	})
+}
+
+// MustDecode calls Decode but panics if an error is encountered.
+func (gt gobTranslator) MustDecode(rd io.Reader) Network {
+	nw, err := gt.Decode(rd)
+	if err != nil {
+		panic(errors.Wrap(err, "must decode"))
+	}
+	return nw
+}
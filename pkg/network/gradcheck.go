@@ -0,0 +1,188 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// gradCheckFloor is the smallest denominator used when computing the relative
+// error between an analytic and numeric gradient. Without it, parameters whose
+// gradients are both effectively zero would report enormous relative errors
+// caused by nothing more than floating point noise.
+const gradCheckFloor = 1e-6
+
+// ParameterGradient describes a single learnable parameter in a Network along
+// with the gradient computed for it by BackwardPass and the gradient estimated
+// for it by central finite differences.
+type ParameterGradient struct {
+	// Layer is the index of the layer the parameter belongs to.
+	Layer int
+	// Neuron is the index of the neuron in Layer the parameter belongs to.
+	Neuron int
+	// Connection is the index of the connection in Neuron the parameter
+	// belongs to. It is -1 if the parameter is the neuron's bias.
+	Connection int
+	// Analytic is the gradient of the loss with respect to this parameter as
+	// computed by BackwardPass.
+	Analytic float64
+	// Numeric is the gradient of the loss with respect to this parameter as
+	// estimated by central finite differences.
+	Numeric float64
+	// RelativeError is the relative error between Analytic and Numeric.
+	RelativeError float64
+}
+
+// IsBias reports whether pg describes a neuron's bias rather than a
+// connection's weight.
+func (pg ParameterGradient) IsBias() bool {
+	return pg.Connection < 0
+}
+
+func (pg ParameterGradient) String() string {
+	if pg.IsBias() {
+		return fmt.Sprintf("bias of layer %v neuron %v: analytic %v, numeric %v, relative error %v", pg.Layer, pg.Neuron, pg.Analytic, pg.Numeric, pg.RelativeError)
+	}
+	return fmt.Sprintf("weight of layer %v neuron %v connection %v: analytic %v, numeric %v, relative error %v", pg.Layer, pg.Neuron, pg.Connection, pg.Analytic, pg.Numeric, pg.RelativeError)
+}
+
+// GradCheckResult is the outcome of running GradCheck against a Network.
+type GradCheckResult struct {
+	// MaxRelativeError is the largest relative error found across every
+	// parameter in the network.
+	MaxRelativeError float64
+	// Parameters contains every parameter that was checked, sorted from the
+	// largest relative error to the smallest.
+	Parameters []ParameterGradient
+}
+
+// Worst returns the k parameters with the largest relative errors. If k is
+// larger than the number of parameters checked, all of them are returned.
+func (gcr GradCheckResult) Worst(k int) []ParameterGradient {
+	if k > len(gcr.Parameters) {
+		k = len(gcr.Parameters)
+	}
+	if k < 0 {
+		k = 0
+	}
+	return gcr.Parameters[:k]
+}
+
+// GradCheck validates the gradients computed by BackwardPass by comparing them
+// against gradients estimated via central finite differences. For every weight
+// and bias in nw (excluding the input layer, whose biases never take part in a
+// forward pass), the parameter is nudged by +eps and -eps, the loss is
+// recomputed for input and truth, and the slope between the two is compared to
+// the analytic gradient.
+//
+// The weights and biases of nw are restored once checking is complete, but any
+// pass or batch state (including recorded nudges) is reset.
+//
+// If eps is not positive, or input or truth do not fit nw, an error is
+// returned.
+func GradCheck(nw Network, input, truth []float64, eps float64) (GradCheckResult, error) {
+	if eps <= 0 {
+		return GradCheckResult{}, errors.New("cannot grad check network: eps must be greater than zero")
+	}
+	if len(truth) != len(nw.LastLayer()) {
+		return GradCheckResult{}, fmt.Errorf("cannot grad check network: truth data length (%v) is not of same length as last layer of neurons (%v)", len(truth), len(nw.LastLayer()))
+	}
+
+	nw.ResetFromBatch()
+	if err := nw.ForwardPass(input); err != nil {
+		return GradCheckResult{}, err
+	}
+	if err := nw.BackwardPass(truth); err != nil {
+		return GradCheckResult{}, err
+	}
+
+	// The analytic gradients must be captured before any parameter is nudged,
+	// because every subsequent pass overwrites them.
+	var pgs []ParameterGradient
+	for li := 1; li < len(nw); li++ {
+		for ni, n := range nw[li] {
+			pgs = append(pgs, ParameterGradient{Layer: li, Neuron: ni, Connection: -1, Analytic: n.dLossDBias})
+			for ci, c := range n.Connections {
+				pgs = append(pgs, ParameterGradient{Layer: li, Neuron: ni, Connection: ci, Analytic: c.dLossDWeight})
+			}
+		}
+	}
+
+	for i := range pgs {
+		pg := &pgs[i]
+
+		p := nw.parameter(pg.Layer, pg.Neuron, pg.Connection)
+		op := *p
+
+		*p = op + eps
+		lp, err := nw.passLoss(input, truth)
+		if err != nil {
+			*p = op
+			return GradCheckResult{}, err
+		}
+
+		*p = op - eps
+		lm, err := nw.passLoss(input, truth)
+		*p = op
+		if err != nil {
+			return GradCheckResult{}, err
+		}
+
+		pg.Numeric = (lp - lm) / (2 * eps)
+		pg.RelativeError = relativeError(pg.Analytic, pg.Numeric)
+	}
+
+	nw.ResetFromBatch()
+
+	sort.SliceStable(pgs, func(i, j int) bool {
+		return pgs[i].RelativeError > pgs[j].RelativeError
+	})
+
+	gcr := GradCheckResult{Parameters: pgs}
+	if len(pgs) > 0 {
+		gcr.MaxRelativeError = pgs[0].RelativeError
+	}
+
+	return gcr, nil
+}
+
+// MustGradCheck calls GradCheck but panics if an error is encountered.
+func MustGradCheck(nw Network, input, truth []float64, eps float64) GradCheckResult {
+	gcr, err := GradCheck(nw, input, truth, eps)
+	if err != nil {
+		panic(err)
+	}
+	return gcr
+}
+
+// parameter returns a pointer to the bias of the neuron at ni in the layer at
+// li if ci is negative, otherwise it returns a pointer to the weight of that
+// neuron's connection at ci.
+func (nw Network) parameter(li, ni, ci int) *float64 {
+	n := nw[li][ni]
+	if ci < 0 {
+		return &n.bias
+	}
+	return &n.Connections[ci].weight
+}
+
+// passLoss resets nw from its previous pass, executes a forward pass with input
+// and returns the loss as compared with truth.
+func (nw Network) passLoss(input, truth []float64) (float64, error) {
+	nw.ResetFromPass()
+	if err := nw.ForwardPass(input); err != nil {
+		return 0, err
+	}
+	return nw.CalculateLoss(truth)
+}
+
+// relativeError returns the relative error between a and b, using
+// gradCheckFloor as the smallest allowed denominator.
+func relativeError(a, b float64) float64 {
+	d := math.Max(math.Abs(a), math.Abs(b))
+	if d < gradCheckFloor {
+		d = gradCheckFloor
+	}
+	return math.Abs(a-b) / d
+}
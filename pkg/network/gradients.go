@@ -0,0 +1,251 @@
+package network
+
+import (
+	"fmt"
+	"math"
+)
+
+// Gradients holds a gradient for every weight and bias in a Network. Weights
+// and Biases are laid out identically to the arguments of
+// Network.SetConnectionWeights and Network.SetNeuronBiases respectively, that
+// is Weights[li][ni][ci] is the gradient of the weight of connection ci of
+// neuron ni in layer li, and Biases[li][ni] is the gradient of the bias of
+// neuron ni in layer li.
+//
+// Gradients point in the direction of greatest ascent of the loss function, so
+// they are subtracted from the network's parameters when applied.
+//
+// The operations on Gradients modify it in place. Use Clone first if the
+// original values must be preserved.
+type Gradients struct {
+	Weights [][][]float64
+	Biases  [][]float64
+}
+
+// NewGradients returns a zeroed Gradients shaped to fit nw.
+func NewGradients(nw Network) Gradients {
+	g := Gradients{
+		Weights: make([][][]float64, len(nw)),
+		Biases:  make([][]float64, len(nw)),
+	}
+	for li, l := range nw {
+		g.Weights[li] = make([][]float64, len(l))
+		g.Biases[li] = make([]float64, len(l))
+		for ni, n := range l {
+			g.Weights[li][ni] = make([]float64, len(n.Connections))
+		}
+	}
+	return g
+}
+
+// Gradients returns the mini batch gradients of nw, which is the average of all
+// the nudges recorded via RecordNudges since the last call to ResetFromBatch.
+// These are the exact values AdjustWeights would apply. Parameters without any
+// recorded nudges have a gradient of zero.
+func (nw Network) Gradients() Gradients {
+	g := NewGradients(nw)
+	for li, l := range nw {
+		for ni, n := range l {
+			g.Biases[li][ni] = n.averageBiasNudge()
+			for ci, c := range n.Connections {
+				g.Weights[li][ni][ci] = c.averageWeightNudge()
+			}
+		}
+	}
+	return g
+}
+
+// PassGradients returns the gradients calculated by the most recent
+// BackwardPass on nw, without regard to any nudges that have been recorded.
+func (nw Network) PassGradients() Gradients {
+	g := NewGradients(nw)
+	for li, l := range nw {
+		for ni, n := range l {
+			g.Biases[li][ni] = n.dLossDBias
+			for ci, c := range n.Connections {
+				g.Weights[li][ni][ci] = c.dLossDWeight
+			}
+		}
+	}
+	return g
+}
+
+// ApplyGradients nudges every weight and bias in nw against its corresponding
+// gradient in g scaled by learningRate. Calling nw.ApplyGradients(nw.Gradients(),
+// learningRate) is equivalent to calling nw.AdjustWeights(learningRate).
+//
+// If g is not shaped to fit nw, an error is returned and nw is left unchanged.
+func (nw Network) ApplyGradients(g Gradients, learningRate float64) error {
+	if err := g.fits(nw); err != nil {
+		return err
+	}
+
+	for li, l := range nw {
+		for ni, n := range l {
+			n.bias -= g.Biases[li][ni] * learningRate
+			for ci, c := range n.Connections {
+				c.weight -= g.Weights[li][ni][ci] * learningRate
+			}
+		}
+	}
+
+	return nil
+}
+
+// MustApplyGradients calls ApplyGradients but panics if an error is
+// encountered.
+func (nw Network) MustApplyGradients(g Gradients, learningRate float64) {
+	err := nw.ApplyGradients(g, learningRate)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Clone returns a deep copy of g.
+func (g Gradients) Clone() Gradients {
+	g2 := Gradients{
+		Weights: make([][][]float64, len(g.Weights)),
+		Biases:  make([][]float64, len(g.Biases)),
+	}
+	for li := range g.Weights {
+		g2.Weights[li] = make([][]float64, len(g.Weights[li]))
+		for ni := range g.Weights[li] {
+			g2.Weights[li][ni] = append([]float64(nil), g.Weights[li][ni]...)
+		}
+	}
+	for li := range g.Biases {
+		g2.Biases[li] = append([]float64(nil), g.Biases[li]...)
+	}
+	return g2
+}
+
+// Add adds every gradient in g2 to its corresponding gradient in g. If g and g2
+// are not shaped the same, an error is returned and g is left unchanged.
+func (g Gradients) Add(g2 Gradients) error {
+	if err := g.matches(g2); err != nil {
+		return err
+	}
+
+	g.each(func(li, ni, ci int, v *float64) {
+		if ci < 0 {
+			*v += g2.Biases[li][ni]
+			return
+		}
+		*v += g2.Weights[li][ni][ci]
+	})
+
+	return nil
+}
+
+// MustAdd calls Add but panics if an error is encountered.
+func (g Gradients) MustAdd(g2 Gradients) {
+	err := g.Add(g2)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Scale multiplies every gradient in g by factor.
+func (g Gradients) Scale(factor float64) {
+	g.each(func(_, _, _ int, v *float64) {
+		*v *= factor
+	})
+}
+
+// ClipValue clamps every gradient in g to the range [-limit, limit]. A limit
+// that is not positive leaves g unchanged.
+func (g Gradients) ClipValue(limit float64) {
+	if limit <= 0 {
+		return
+	}
+	g.each(func(_, _, _ int, v *float64) {
+		*v = math.Max(-limit, math.Min(limit, *v))
+	})
+}
+
+// ClipNorm rescales g so that its global L2 norm, as reported by Norm, does not
+// exceed maxNorm. The direction of g is preserved. A maxNorm that is not
+// positive leaves g unchanged.
+func (g Gradients) ClipNorm(maxNorm float64) {
+	if maxNorm <= 0 {
+		return
+	}
+	norm := g.Norm()
+	if norm <= maxNorm {
+		return
+	}
+	g.Scale(maxNorm / norm)
+}
+
+// Norm returns the global L2 norm of g, treating every weight and bias gradient
+// as a component of a single vector.
+func (g Gradients) Norm() float64 {
+	sum := 0.0
+	g.each(func(_, _, _ int, v *float64) {
+		sum += *v * *v
+	})
+	return math.Sqrt(sum)
+}
+
+// Flatten returns every gradient in g as a single vector, laid out as the
+// parameters returned by Network.Parameters, so that element i of the result is
+// the gradient of parameter i. Like those parameters, it leaves out the biases
+// of the input layer.
+func (g Gradients) Flatten() []float64 {
+	var vs []float64
+	g.each(func(li, _, ci int, v *float64) {
+		if li == 0 && ci == -1 {
+			return
+		}
+		vs = append(vs, *v)
+	})
+	return vs
+}
+
+// each calls fn with a pointer to every gradient in g. ci is -1 when v is a
+// bias gradient.
+func (g Gradients) each(fn func(li, ni, ci int, v *float64)) {
+	for li := range g.Biases {
+		for ni := range g.Biases[li] {
+			fn(li, ni, -1, &g.Biases[li][ni])
+		}
+	}
+	for li := range g.Weights {
+		for ni := range g.Weights[li] {
+			for ci := range g.Weights[li][ni] {
+				fn(li, ni, ci, &g.Weights[li][ni][ci])
+			}
+		}
+	}
+}
+
+// fits returns an error if g is not shaped to fit nw.
+func (g Gradients) fits(nw Network) error {
+	return g.matches(NewGradients(nw))
+}
+
+// matches returns an error if g and g2 are not shaped the same.
+func (g Gradients) matches(g2 Gradients) error {
+	if len(g.Weights) != len(g2.Weights) || len(g.Biases) != len(g2.Biases) {
+		return fmt.Errorf("gradients have different numbers of layers, %v != %v", len(g.Biases), len(g2.Biases))
+	}
+
+	for li := range g.Biases {
+		if len(g.Biases[li]) != len(g2.Biases[li]) {
+			return fmt.Errorf("gradients have different numbers of biases in layer %v, %v != %v", li, len(g.Biases[li]), len(g2.Biases[li]))
+		}
+	}
+
+	for li := range g.Weights {
+		if len(g.Weights[li]) != len(g2.Weights[li]) {
+			return fmt.Errorf("gradients have different numbers of neurons in layer %v, %v != %v", li, len(g.Weights[li]), len(g2.Weights[li]))
+		}
+		for ni := range g.Weights[li] {
+			if len(g.Weights[li][ni]) != len(g2.Weights[li][ni]) {
+				return fmt.Errorf("gradients have different numbers of weights in layer %v neuron %v, %v != %v", li, ni, len(g.Weights[li][ni]), len(g2.Weights[li][ni]))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package network
+
+import (
+	"math"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_ApplyGradients_MatchesAdjustWeights(t *testing.T) {
+	spec := Spec{
+		NeuronMap:              []int{3, 4, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	}
+	nw := MustFrom(spec)
+
+	jt := NewJsonTranslator()
+	nw2 := jt.MustDeserialize(jt.MustSerialize(nw))
+
+	for _, nw := range []Network{nw, nw2} {
+		nw.ResetFromBatch()
+		for _, input := range [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+			nw.ResetFromPass()
+			nw.MustForwardPass(input)
+			nw.MustBackwardPass([]float64{1, 0})
+			nw.RecordNudges()
+		}
+	}
+
+	nw.AdjustWeights(0.1)
+	nw2.MustApplyGradients(nw2.Gradients(), 0.1)
+
+	if err := nw.Equals(nw2); err != nil {
+		t.Fatalf("applying gradients did not match adjusting weights: %v", err)
+	}
+}
+
+func Test_Gradients_Operations(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	g := NewGradients(nw)
+	g.Biases[1] = []float64{3, 0}
+	g.Weights[1] = [][]float64{{0, 4}, {0, 0}}
+
+	if n := g.Norm(); n != 5 {
+		t.Fatalf("expected norm of 5, got %v", n)
+	}
+
+	g2 := g.Clone()
+	g2.MustAdd(g)
+	if g2.Biases[1][0] != 6 || g2.Weights[1][0][1] != 8 {
+		t.Fatalf("add did not sum gradients: %v", g2)
+	}
+	if g.Biases[1][0] != 3 {
+		t.Fatalf("clone shares memory with the original gradients")
+	}
+
+	g2.Scale(0.5)
+	if g2.Biases[1][0] != 3 || g2.Weights[1][0][1] != 4 {
+		t.Fatalf("scale did not halve gradients: %v", g2)
+	}
+
+	g2.ClipValue(3.5)
+	if g2.Biases[1][0] != 3 || g2.Weights[1][0][1] != 3.5 {
+		t.Fatalf("clip value did not clamp gradients: %v", g2)
+	}
+
+	g.ClipNorm(1)
+	if n := g.Norm(); math.Abs(n-1) > 1e-12 {
+		t.Fatalf("expected clipped norm of 1, got %v", n)
+	}
+	if math.Abs(g.Biases[1][0]-0.6) > 1e-12 || math.Abs(g.Weights[1][0][1]-0.8) > 1e-12 {
+		t.Fatalf("clip norm did not preserve direction: %v", g)
+	}
+}
+
+func Test_Gradients_ShapeMismatch(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw2 := MustFrom(Spec{
+		NeuronMap:              []int{2, 3},
+		OutputLabels:           []string{"a", "b", "c"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	if err := NewGradients(nw).Add(NewGradients(nw2)); err == nil {
+		t.Fatalf("expected an error adding mismatched gradients")
+	}
+	if err := nw.ApplyGradients(NewGradients(nw2), 0.1); err == nil {
+		t.Fatalf("expected an error applying mismatched gradients")
+	}
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"encoding/json"
+	"io"
 
 	"github.com/pkg/errors"
 
@@ -21,16 +22,18 @@ func NewJsonTranslator(opts ...TranslatorOption) Translator {
 }
 
 func (jt jsonTranslator) Serialize(nw Network) ([]byte, error) {
+	if err := checkFullyConnected(nw); err != nil {
+		return nil, err
+	}
+
 	bs, err := json.Marshal(nw)
 	if err != nil {
 		return nil, errors.Wrap(err, "json marshalling")
 	}
 
-	for i, opt := range jt.opts {
-		bs, err = opt.Serialize(bs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "translator serialize option %v", i)
-		}
+	bs, err = serialize(bs, jt.opts)
+	if err != nil {
+		return nil, err
 	}
 
 	return bs, nil
@@ -46,12 +49,9 @@ func (jt jsonTranslator) MustSerialize(nw Network) []byte {
 }
 
 func (jt jsonTranslator) Deserialize(bs []byte) (Network, error) {
-	var err error
-	for i, opt := range jt.opts {
-		bs, err = opt.Deserialize(bs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "translator deserialize option %v", i)
-		}
+	bs, err := deserialize(bs, jt.opts)
+	if err != nil {
+		return nil, err
 	}
 
 	var nw Network
@@ -129,6 +129,10 @@ func (nw *Network) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if err := nnw.checkDecodedInputTransforms(); err != nil {
+		return err
+	}
+
 	// Overwrite the pointer with the updated network to persist the changes to
 	// the caller.
 	*nw = nnw
@@ -142,6 +146,7 @@ type jsonNeuron struct {
 	Connections            []*Connection           `json:"connections"`
 	ActivationFunctionName activationfunction.Name `json:"activationFunctionName"`
 	Label                  string                  `json:"label"`
+	Transform              *InputTransform         `json:"transform,omitempty"`
 	Value                  float64                 `json:"value"`
 	WSum                   float64                 `json:"wSum"`
 	Bias                   float64                 `json:"bias"`
@@ -149,7 +154,12 @@ type jsonNeuron struct {
 	DLossDBias             float64                 `json:"dLossDBias"`
 	DValueDNet             float64                 `json:"dValueDNet"`
 	DNetDBias              float64                 `json:"dNetDBias"`
-	BiasNudges             []float64               `json:"biasNudges"`
+	BiasNudgeSum           float64                 `json:"biasNudgeSum"`
+	Nudges                 int                     `json:"nudges"`
+
+	// BiasNudges is only read, never written. It supports networks encoded
+	// before nudges were accumulated as a running sum.
+	BiasNudges []float64 `json:"biasNudges,omitempty"`
 }
 
 func (n *Neuron) MarshalJSON() ([]byte, error) {
@@ -157,6 +167,7 @@ func (n *Neuron) MarshalJSON() ([]byte, error) {
 		Connections:            n.Connections,
 		ActivationFunctionName: n.ActivationFunctionName,
 		Label:                  n.label,
+		Transform:              n.transform,
 		Value:                  n.value,
 		WSum:                   n.wSum,
 		Bias:                   n.bias,
@@ -164,7 +175,8 @@ func (n *Neuron) MarshalJSON() ([]byte, error) {
 		DLossDBias:             n.dLossDBias,
 		DValueDNet:             n.dValueDNet,
 		DNetDBias:              n.dNetDBias,
-		BiasNudges:             n.biasNudges,
+		BiasNudgeSum:           n.biasNudgeSum,
+		Nudges:                 n.nudges,
 	})
 	if err != nil {
 		return nil, err
@@ -182,6 +194,7 @@ func (n *Neuron) UnmarshalJSON(j []byte) error {
 	n.Connections = t.Connections
 	n.ActivationFunctionName = t.ActivationFunctionName
 	n.label = t.Label
+	n.transform = t.Transform
 	n.value = t.Value
 	n.wSum = t.WSum
 	n.bias = t.Bias
@@ -189,7 +202,11 @@ func (n *Neuron) UnmarshalJSON(j []byte) error {
 	n.dLossDBias = t.DLossDBias
 	n.dValueDNet = t.DValueDNet
 	n.dNetDBias = t.DNetDBias
-	n.biasNudges = t.BiasNudges
+	n.biasNudgeSum = t.BiasNudgeSum
+	n.nudges = t.Nudges
+	if len(t.BiasNudges) > 0 {
+		n.biasNudgeSum, n.nudges = sumNudges(t.BiasNudges)
+	}
 
 	af, err := activationfunction.GetFunction(n.ActivationFunctionName)
 	if err != nil {
@@ -204,12 +221,17 @@ func (n *Neuron) UnmarshalJSON(j []byte) error {
 // exported fields so that they may be exposed in a json body by the JSON
 // marshaller.
 type jsonConnection struct {
-	To             *Neuron   `json:"-"`
-	Weight         float64   `json:"weight"`
-	DNetDWeight    float64   `json:"dNetDWeight"`
-	DLossDWeight   float64   `json:"dLossDWeight"`
-	DNetDPrevValue float64   `json:"dNetDPrevValue"`
-	WeightNudges   []float64 `json:"weightNudges"`
+	To             *Neuron `json:"-"`
+	Weight         float64 `json:"weight"`
+	DNetDWeight    float64 `json:"dNetDWeight"`
+	DLossDWeight   float64 `json:"dLossDWeight"`
+	DNetDPrevValue float64 `json:"dNetDPrevValue"`
+	WeightNudgeSum float64 `json:"weightNudgeSum"`
+	Nudges         int     `json:"nudges"`
+
+	// WeightNudges is only read, never written. It supports networks encoded
+	// before nudges were accumulated as a running sum.
+	WeightNudges []float64 `json:"weightNudges,omitempty"`
 }
 
 func (c *Connection) MarshalJSON() ([]byte, error) {
@@ -219,7 +241,8 @@ func (c *Connection) MarshalJSON() ([]byte, error) {
 		DNetDWeight:    c.dNetDWeight,
 		DLossDWeight:   c.dLossDWeight,
 		DNetDPrevValue: c.dNetDPrevValue,
-		WeightNudges:   c.weightNudges,
+		WeightNudgeSum: c.weightNudgeSum,
+		Nudges:         c.nudges,
 	})
 	if err != nil {
 		return nil, err
@@ -239,7 +262,66 @@ func (c *Connection) UnmarshalJSON(j []byte) error {
 	c.dNetDWeight = t.DNetDWeight
 	c.dLossDWeight = t.DLossDWeight
 	c.dNetDPrevValue = t.DNetDPrevValue
-	c.weightNudges = t.WeightNudges
+	c.weightNudgeSum = t.WeightNudgeSum
+	c.nudges = t.Nudges
+	if len(t.WeightNudges) > 0 {
+		c.weightNudgeSum, c.nudges = sumNudges(t.WeightNudges)
+	}
 
 	return nil
 }
+
+// sumNudges returns the sum and count of a legacy slice of nudges.
+func sumNudges(nudges []float64) (float64, int) {
+	sum := 0.0
+	for _, nudge := range nudges {
+		sum += nudge
+	}
+	return sum, len(nudges)
+}
+
+// Encode writes the JSON encoding of nw to w, applying jt's options as it is
+// written.
+func (jt jsonTranslator) Encode(w io.Writer, nw Network) error {
+	if err := checkFullyConnected(nw); err != nil {
+		return err
+	}
+
+	return encode(w, jt.opts, func(w io.Writer) error {
+		bs, err := json.Marshal(nw)
+		if err != nil {
+			return errors.Wrap(err, "json marshalling")
+		}
+		_, err = w.Write(bs)
+		return err
+	})
+}
+
+// MustEncode calls Encode but panics if an error is encountered.
+func (jt jsonTranslator) MustEncode(w io.Writer, nw Network) {
+	err := jt.Encode(w, nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must encode"))
+	}
+}
+
+// Decode reads a JSON encoded Network from r, reversing jt's options as it is
+// read.
+func (jt jsonTranslator) Decode(r io.Reader) (Network, error) {
+	return decode(r, jt.opts, func(r io.Reader) (Network, error) {
+		var nw Network
+		if err := json.NewDecoder(r).Decode(&nw); err != nil {
+			return Network{}, errors.Wrap(err, "json unmarshalling")
+		}
+		return nw, nil
+	})
+}
+
+// MustDecode calls Decode but panics if an error is encountered.
+func (jt jsonTranslator) MustDecode(rd io.Reader) Network {
+	nw, err := jt.Decode(rd)
+	if err != nil {
+		panic(errors.Wrap(err, "must decode"))
+	}
+	return nw
+}
@@ -31,3 +31,18 @@ func Test_SerializeAndDeserializeAreInverses(t *testing.T) {
 		t.Fatalf("original JSON encoding and deserialized network JSON encoding do not equal each other")
 	}
 }
+
+func Test_json_DeserializesLegacyNudges(t *testing.T) {
+	legacy := `[[{"connections":null,"activationFunctionName":"linear","label":"x","bias":0.5,"biasNudges":[1,2,3]}],` +
+		`[{"connections":[{"weight":0.25,"weightNudges":[4,8]}],"activationFunctionName":"linear","label":"y","bias":0.1,"biasNudges":[2,4]}]]`
+
+	nw := NewJsonTranslator().MustDeserialize([]byte(legacy))
+
+	g := nw.Gradients()
+	if g.Biases[0][0] != 2 || g.Biases[1][0] != 3 {
+		t.Fatalf("legacy bias nudges were not averaged, got %v", g.Biases)
+	}
+	if g.Weights[1][0][0] != 6 {
+		t.Fatalf("legacy weight nudges were not averaged, got %v", g.Weights)
+	}
+}
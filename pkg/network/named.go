@@ -0,0 +1,134 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FeatureError is returned when named features don't match the input labels of
+// a Network. It lists every offending feature, sorted, rather than only the
+// first.
+type FeatureError struct {
+	// Missing are the input labels that no feature was provided for.
+	Missing []string
+	// Unknown are the provided features that aren't input labels.
+	Unknown []string
+}
+
+func (e *FeatureError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing features %q", e.Missing))
+	}
+	if len(e.Unknown) > 0 {
+		parts = append(parts, fmt.Sprintf("unknown features %q", e.Unknown))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RawInputLabels returns the name of every raw input a prediction made by nw
+// expects, index-wise. Without input transforms these are the labels of the
+// input layer. With input transforms, each raw input is named by the label of
+// the first input neuron computed from it, so input neurons sharing a raw input,
+// such as those of a one-hot encoding, should share its label too.
+//
+// An error is returned if any name is empty or is used more than once.
+func (nw Network) RawInputLabels() ([]string, error) {
+	if len(nw) == 0 {
+		return nil, errors.New("network has no layers")
+	}
+
+	labels := make([]string, nw.RawInputs())
+	named := make([]bool, len(labels))
+	for i, n := range nw.FirstLayer() {
+		source := i
+		if n.transform != nil {
+			source = n.transform.Source
+		}
+		if !named[source] {
+			labels[source], named[source] = n.label, true
+		}
+	}
+
+	seen := make(map[string]bool, len(labels))
+	for i, label := range labels {
+		if label == "" {
+			return nil, fmt.Errorf("raw input %v has no label", i)
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("raw input label %q is not unique", label)
+		}
+		seen[label] = true
+	}
+	return labels, nil
+}
+
+// NamedInput converts features, keyed by input label, into the positional raw
+// input expected by the predictions of nw, so that callers don't depend on the
+// order of nw's inputs. See RawInputLabels for how inputs are named.
+//
+// If any input label has no feature, or any feature isn't an input label, a
+// *FeatureError is returned.
+func (nw Network) NamedInput(features map[string]float64) ([]float64, error) {
+	labels, err := nw.RawInputLabels()
+	if err != nil {
+		return nil, fmt.Errorf("cannot name inputs: %w", err)
+	}
+
+	var fe FeatureError
+	input := make([]float64, len(labels))
+	for i, label := range labels {
+		v, found := features[label]
+		if !found {
+			fe.Missing = append(fe.Missing, label)
+			continue
+		}
+		input[i] = v
+	}
+	if len(features) != len(labels)-len(fe.Missing) {
+		isLabel := make(map[string]bool, len(labels))
+		for _, label := range labels {
+			isLabel[label] = true
+		}
+		for name := range features {
+			if !isLabel[name] {
+				fe.Unknown = append(fe.Unknown, name)
+			}
+		}
+	}
+	if len(fe.Missing) > 0 || len(fe.Unknown) > 0 {
+		sort.Strings(fe.Missing)
+		sort.Strings(fe.Unknown)
+		return nil, &fe
+	}
+	return input, nil
+}
+
+// MustNamedInput calls NamedInput but panics if an error is encountered.
+func (nw Network) MustNamedInput(features map[string]float64) []float64 {
+	input, err := nw.NamedInput(features)
+	if err != nil {
+		panic(err)
+	}
+	return input
+}
+
+// PredictNamed calls Predict with features converted via NamedInput.
+func (nw Network) PredictNamed(features map[string]float64) (string, float64, error) {
+	input, err := nw.NamedInput(features)
+	if err != nil {
+		return "", 0, err
+	}
+	return nw.Predict(input)
+}
+
+// MustPredictNamed calls PredictNamed but panics if an error is encountered.
+func (nw Network) MustPredictNamed(features map[string]float64) (string, float64) {
+	prediction, value, err := nw.PredictNamed(features)
+	if err != nil {
+		panic(err)
+	}
+	return prediction, value
+}
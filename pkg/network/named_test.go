@@ -0,0 +1,79 @@
+package network
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_PredictNamed(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 2},
+		InputLabels:            []string{"age", "height", "weight"},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	label, value := nw.MustPredictNamed(map[string]float64{"weight": 3, "age": 1, "height": 2})
+
+	expectedLabel, expectedValue := nw.MustPredict([]float64{1, 2, 3})
+	if label != expectedLabel || value != expectedValue {
+		t.Fatalf("expected %v %v, got %v %v", expectedLabel, expectedValue, label, value)
+	}
+}
+
+func Test_NamedInput_FeatureErrors(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 2},
+		InputLabels:            []string{"age", "height", "weight"},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	_, err := nw.NamedInput(map[string]float64{"age": 1, "shoe": 2, "hat": 3})
+
+	var fe *FeatureError
+	if !errors.As(err, &fe) {
+		t.Fatalf("expected a feature error, got %v", err)
+	}
+	if !reflect.DeepEqual(fe.Missing, []string{"height", "weight"}) {
+		t.Fatalf("unexpected missing features %v", fe.Missing)
+	}
+	if !reflect.DeepEqual(fe.Unknown, []string{"hat", "shoe"}) {
+		t.Fatalf("unexpected unknown features %v", fe.Unknown)
+	}
+}
+
+func Test_NamedInput_InputTransforms(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 1},
+		InputLabels:            []string{"size", "color", "color"},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.MustSetInputTransforms([]InputTransform{
+		{Source: 0},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 0}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 1}}},
+	})
+
+	input := nw.MustNamedInput(map[string]float64{"color": 1, "size": 5})
+
+	if expected := []float64{5, 1}; !reflect.DeepEqual(input, expected) {
+		t.Fatalf("expected %v, got %v", expected, input)
+	}
+}
+
+func Test_RawInputLabels_Invalid(t *testing.T) {
+	unlabeled := MustFrom(Spec{NeuronMap: []int{2, 1}, OutputLabels: []string{"y"}, ActivationFunctionName: activationfunction.NameSigmoid})
+	if _, _, err := unlabeled.PredictNamed(map[string]float64{}); err == nil {
+		t.Fatalf("expected error for unlabeled inputs")
+	}
+
+	duplicated := MustFrom(Spec{NeuronMap: []int{2, 1}, InputLabels: []string{"x", "x"}, OutputLabels: []string{"y"}, ActivationFunctionName: activationfunction.NameSigmoid})
+	if _, _, err := duplicated.PredictNamed(map[string]float64{"x": 1}); err == nil {
+		t.Fatalf("expected error for duplicate input labels")
+	}
+}
@@ -20,19 +20,24 @@ type Spec struct {
 	// well as the number of neurons in each Layer. For example if index 3
 	// contains the value 5, that would mean that the third Layer of the network
 	// should contain 5 neurons.
-	NeuronMap []int
+	NeuronMap []int `json:"neuronMap"`
 	// InputLabels defines the labels for each of the input neurons.
 	// len(InputLabels) must equal NeuronMap[0].
-	InputLabels []string
+	InputLabels []string `json:"inputLabels"`
 	// OutputLabels defines the labels for each of the output neurons.
 	// len(OutputLabels) must equal NeuronMap[len(NeuronMap)-1].
-	OutputLabels []string
+	OutputLabels []string `json:"outputLabels"`
 	// ActivationFunctionName is a name corresponding to an ActivationFunction
 	// found in the activationfunction package. All neurons created for this
 	// network will use this activation function.
-	ActivationFunctionName activationfunction.Name
+	ActivationFunctionName activationfunction.Name `json:"activationFunctionName"`
 }
 
+// LossFunctionName is the name of the loss function every Network is trained
+// against. It is the sum of the squared differences between the output layer
+// and the truth, as calculated by CalculateLoss.
+const LossFunctionName = "sse"
+
 // From creates a new fully-connected Network from the construction details in
 // spec and returns an error if spec is invalid.
 func From(spec Spec) (Network, error) {
@@ -95,6 +100,34 @@ func MustFrom(spec Spec) Network {
 	return nw
 }
 
+// Spec returns a Spec describing the shape and labels of nw. Because a Spec
+// only supports a single activation function, the ActivationFunctionName of the
+// first neuron in nw's last layer is used.
+//
+// Calling From with the returned Spec creates a Network of the same shape as
+// nw, but with new randomized weights and biases.
+func (nw Network) Spec() Spec {
+	spec := Spec{}
+	if len(nw) == 0 {
+		return spec
+	}
+
+	for li := range nw {
+		spec.NeuronMap = append(spec.NeuronMap, len(nw[li]))
+	}
+	for _, n := range nw.FirstLayer() {
+		spec.InputLabels = append(spec.InputLabels, n.label)
+	}
+	for _, n := range nw.LastLayer() {
+		spec.OutputLabels = append(spec.OutputLabels, n.label)
+	}
+	if len(nw.LastLayer()) > 0 {
+		spec.ActivationFunctionName = nw.LastLayer()[0].ActivationFunctionName
+	}
+
+	return spec
+}
+
 // Reconnect connects all the neurons in nw to all the neurons in their previous
 // layers using brand new connections. This function will scan over the entire
 // network and recreate all connections between contiguous layers.
@@ -144,7 +177,7 @@ func (nw Network) ReconnectNeurons() error {
 // IsFullyConnected reports whether all neurons in the network are connected to
 // another neuron by checking that the neuron has the same number of connections
 // as there are neurons in the previous layer and that each of those connections
-// has its To field populated with a non-nil neuron.
+// leads, in order, to the neuron of the previous layer at its index.
 func (nw Network) IsFullyConnected() bool {
 	for li := 1; li < len(nw); li++ {
 		l := nw[li]
@@ -160,7 +193,7 @@ func (nw Network) IsFullyConnected() bool {
 			for ci := 0; ci < len(n.Connections); ci++ {
 				c := n.Connections[ci]
 
-				if c.To == nil {
+				if c.To != pl[ci] {
 					return false
 				}
 			}
@@ -173,17 +206,11 @@ func (nw Network) IsFullyConnected() bool {
 // Predict will execute a ForwardPass on nw using input as its input, then
 // returns the label and value of the neuron with the highest confidence. If you
 // wish to see all output neurons instead of just the neuron with highest
-// confidence then use LastLayer to inspect them all.
+// confidence then use PredictAll, PredictVector or TopK.
 //
-// If len(input) != len(nw.FirstLayer()) then an error will be returned.
+// If len(input) != nw.RawInputs() then an error will be returned.
 func (nw Network) Predict(input []float64) (string, float64, error) {
-	if len(input) != len(nw.FirstLayer()) {
-		return "", 0, fmt.Errorf("invalid number of values provided (%v), does no match number of neurons in Layer (%v)", len(input), len(nw.FirstLayer()))
-	}
-
-	nw.ResetFromBatch()
-
-	err := nw.ForwardPass(input)
+	err := nw.predict(input)
 	if err != nil {
 		return "", 0, err
 	}
@@ -254,7 +281,8 @@ func (nw Network) MustForwardPass(input []float64) {
 // its fed through the network as well as the calculus required to do back
 // propagation and adjust the weights accordingly.
 //
-// if len(truth) != len(nw.LastLayer()) then an error will be returned.
+// if len(truth) != len(nw.LastLayer()) then an error will be returned, as it
+// will if nw isn't fully connected, such as a network built via AddConnection.
 // TODO(justin): Break up
 func (nw Network) BackwardPass(truth []float64) error {
 	ll := nw.LastLayer()
@@ -262,6 +290,9 @@ func (nw Network) BackwardPass(truth []float64) error {
 	if len(truth) != len(ll) {
 		return fmt.Errorf("cannot perform backwards pass: truth data length (%v) is not of same length as last layer of neurons (%v)", len(truth), len(ll))
 	}
+	if !nw.IsFullyConnected() {
+		return errors.New("cannot perform backwards pass: only fully connected networks are supported")
+	}
 
 	for ni := range ll {
 		ll[ni].dLossDValue = 2 * (ll[ni].value - truth[ni])
@@ -0,0 +1,913 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: pkg/network/networkspb/v2/networks.proto
+
+package networkspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LayerKind is the role of a layer within a network, which determines how the
+// labels of its neurons are interpreted.
+type LayerKind int32
+
+const (
+	LayerKind_LAYER_KIND_UNSPECIFIED LayerKind = 0
+	// The labels of neurons in an input layer name the network's inputs.
+	LayerKind_LAYER_KIND_INPUT LayerKind = 1
+	// The labels of neurons in a hidden layer carry no meaning.
+	LayerKind_LAYER_KIND_HIDDEN LayerKind = 2
+	// The labels of neurons in an output layer name the network's outputs.
+	LayerKind_LAYER_KIND_OUTPUT LayerKind = 3
+)
+
+// Enum value maps for LayerKind.
+var (
+	LayerKind_name = map[int32]string{
+		0: "LAYER_KIND_UNSPECIFIED",
+		1: "LAYER_KIND_INPUT",
+		2: "LAYER_KIND_HIDDEN",
+		3: "LAYER_KIND_OUTPUT",
+	}
+	LayerKind_value = map[string]int32{
+		"LAYER_KIND_UNSPECIFIED": 0,
+		"LAYER_KIND_INPUT":       1,
+		"LAYER_KIND_HIDDEN":      2,
+		"LAYER_KIND_OUTPUT":      3,
+	}
+)
+
+func (x LayerKind) Enum() *LayerKind {
+	p := new(LayerKind)
+	*p = x
+	return p
+}
+
+func (x LayerKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LayerKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_network_networkspb_v2_networks_proto_enumTypes[0].Descriptor()
+}
+
+func (LayerKind) Type() protoreflect.EnumType {
+	return &file_pkg_network_networkspb_v2_networks_proto_enumTypes[0]
+}
+
+func (x LayerKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LayerKind.Descriptor instead.
+func (LayerKind) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{0}
+}
+
+// Network is wire compatible with the original schema in networks.proto, so
+// bytes written with either schema can be read as a Network.
+type Network struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// activation_function_name is the activation function of every neuron in
+	// the network. It is only meaningful for networks written with the original
+	// schema, and is otherwise populated solely so that readers of the original
+	// schema can still read the network.
+	ActivationFunctionName string   `protobuf:"bytes,1,opt,name=activation_function_name,json=activationFunctionName,proto3" json:"activation_function_name,omitempty"`
+	Layers                 []*Layer `protobuf:"bytes,2,rep,name=layers,proto3" json:"layers,omitempty"`
+	// schema_version is 2 for networks written with this schema, and 0 for
+	// networks written with the original schema.
+	SchemaVersion uint32 `protobuf:"varint,3,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// metadata holds free-form key/value pairs describing the network.
+	Metadata map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Network) Reset() {
+	*x = Network{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Network) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Network) ProtoMessage() {}
+
+func (x *Network) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Network.ProtoReflect.Descriptor instead.
+func (*Network) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Network) GetActivationFunctionName() string {
+	if x != nil {
+		return x.ActivationFunctionName
+	}
+	return ""
+}
+
+func (x *Network) GetLayers() []*Layer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *Network) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Network) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Layer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Neurons []*Neuron `protobuf:"bytes,1,rep,name=neurons,proto3" json:"neurons,omitempty"`
+	// activation_function_name is the activation function of every neuron in
+	// the layer which does not specify its own.
+	ActivationFunctionName string    `protobuf:"bytes,2,opt,name=activation_function_name,json=activationFunctionName,proto3" json:"activation_function_name,omitempty"`
+	Kind                   LayerKind `protobuf:"varint,3,opt,name=kind,proto3,enum=jnet.network.v2.LayerKind" json:"kind,omitempty"`
+}
+
+func (x *Layer) Reset() {
+	*x = Layer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Layer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Layer) ProtoMessage() {}
+
+func (x *Layer) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Layer.ProtoReflect.Descriptor instead.
+func (*Layer) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Layer) GetNeurons() []*Neuron {
+	if x != nil {
+		return x.Neurons
+	}
+	return nil
+}
+
+func (x *Layer) GetActivationFunctionName() string {
+	if x != nil {
+		return x.ActivationFunctionName
+	}
+	return ""
+}
+
+func (x *Layer) GetKind() LayerKind {
+	if x != nil {
+		return x.Kind
+	}
+	return LayerKind_LAYER_KIND_UNSPECIFIED
+}
+
+type Neuron struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Label       string        `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Bias        float64       `protobuf:"fixed64,2,opt,name=bias,proto3" json:"bias,omitempty"`
+	Connections []*Connection `protobuf:"bytes,3,rep,name=connections,proto3" json:"connections,omitempty"`
+	// activation_function_name overrides the activation function of the layer
+	// for this neuron.
+	ActivationFunctionName string `protobuf:"bytes,4,opt,name=activation_function_name,json=activationFunctionName,proto3" json:"activation_function_name,omitempty"`
+	// training_state is only present for networks serialized part way through a
+	// mini batch.
+	TrainingState *NeuronTrainingState `protobuf:"bytes,5,opt,name=training_state,json=trainingState,proto3" json:"training_state,omitempty"`
+	// input_transform is only present for input neurons whose value is computed
+	// from the raw input given to a prediction.
+	InputTransform *InputTransform `protobuf:"bytes,6,opt,name=input_transform,json=inputTransform,proto3" json:"input_transform,omitempty"`
+}
+
+func (x *Neuron) Reset() {
+	*x = Neuron{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Neuron) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Neuron) ProtoMessage() {}
+
+func (x *Neuron) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Neuron.ProtoReflect.Descriptor instead.
+func (*Neuron) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Neuron) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Neuron) GetBias() float64 {
+	if x != nil {
+		return x.Bias
+	}
+	return 0
+}
+
+func (x *Neuron) GetConnections() []*Connection {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+func (x *Neuron) GetActivationFunctionName() string {
+	if x != nil {
+		return x.ActivationFunctionName
+	}
+	return ""
+}
+
+func (x *Neuron) GetTrainingState() *NeuronTrainingState {
+	if x != nil {
+		return x.TrainingState
+	}
+	return nil
+}
+
+func (x *Neuron) GetInputTransform() *InputTransform {
+	if x != nil {
+		return x.InputTransform
+	}
+	return nil
+}
+
+// InputTransform computes the value of an input neuron by applying steps, in
+// order, to the raw input at index source.
+type InputTransform struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source uint32           `protobuf:"varint,1,opt,name=source,proto3" json:"source,omitempty"`
+	Steps  []*TransformStep `protobuf:"bytes,2,rep,name=steps,proto3" json:"steps,omitempty"`
+}
+
+func (x *InputTransform) Reset() {
+	*x = InputTransform{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InputTransform) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputTransform) ProtoMessage() {}
+
+func (x *InputTransform) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputTransform.ProtoReflect.Descriptor instead.
+func (*InputTransform) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InputTransform) GetSource() uint32 {
+	if x != nil {
+		return x.Source
+	}
+	return 0
+}
+
+func (x *InputTransform) GetSteps() []*TransformStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+// TransformStep is a single operation of an InputTransform. Only the fields
+// used by its kind are meaningful.
+type TransformStep struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// kind is one of "standardize", "minMax", "oneHot", "log" or "clip".
+	Kind     string  `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Mean     float64 `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	Std      float64 `protobuf:"fixed64,3,opt,name=std,proto3" json:"std,omitempty"`
+	Min      float64 `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max      float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	Category float64 `protobuf:"fixed64,6,opt,name=category,proto3" json:"category,omitempty"`
+	Offset   float64 `protobuf:"fixed64,7,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *TransformStep) Reset() {
+	*x = TransformStep{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransformStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransformStep) ProtoMessage() {}
+
+func (x *TransformStep) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransformStep.ProtoReflect.Descriptor instead.
+func (*TransformStep) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransformStep) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TransformStep) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *TransformStep) GetStd() float64 {
+	if x != nil {
+		return x.Std
+	}
+	return 0
+}
+
+func (x *TransformStep) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *TransformStep) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *TransformStep) GetCategory() float64 {
+	if x != nil {
+		return x.Category
+	}
+	return 0
+}
+
+func (x *TransformStep) GetOffset() float64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// NeuronTrainingState holds the bias nudges recorded since the start of the
+// current mini batch.
+type NeuronTrainingState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BiasNudgeSum float64 `protobuf:"fixed64,1,opt,name=bias_nudge_sum,json=biasNudgeSum,proto3" json:"bias_nudge_sum,omitempty"`
+	Nudges       uint64  `protobuf:"varint,2,opt,name=nudges,proto3" json:"nudges,omitempty"`
+}
+
+func (x *NeuronTrainingState) Reset() {
+	*x = NeuronTrainingState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NeuronTrainingState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NeuronTrainingState) ProtoMessage() {}
+
+func (x *NeuronTrainingState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NeuronTrainingState.ProtoReflect.Descriptor instead.
+func (*NeuronTrainingState) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NeuronTrainingState) GetBiasNudgeSum() float64 {
+	if x != nil {
+		return x.BiasNudgeSum
+	}
+	return 0
+}
+
+func (x *NeuronTrainingState) GetNudges() uint64 {
+	if x != nil {
+		return x.Nudges
+	}
+	return 0
+}
+
+type Connection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Weight float64 `protobuf:"fixed64,1,opt,name=weight,proto3" json:"weight,omitempty"`
+	// training_state is only present for networks serialized part way through a
+	// mini batch.
+	TrainingState *ConnectionTrainingState `protobuf:"bytes,2,opt,name=training_state,json=trainingState,proto3" json:"training_state,omitempty"`
+}
+
+func (x *Connection) Reset() {
+	*x = Connection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Connection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Connection) ProtoMessage() {}
+
+func (x *Connection) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Connection.ProtoReflect.Descriptor instead.
+func (*Connection) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Connection) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Connection) GetTrainingState() *ConnectionTrainingState {
+	if x != nil {
+		return x.TrainingState
+	}
+	return nil
+}
+
+// ConnectionTrainingState holds the weight nudges recorded since the start of
+// the current mini batch.
+type ConnectionTrainingState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WeightNudgeSum float64 `protobuf:"fixed64,1,opt,name=weight_nudge_sum,json=weightNudgeSum,proto3" json:"weight_nudge_sum,omitempty"`
+	Nudges         uint64  `protobuf:"varint,2,opt,name=nudges,proto3" json:"nudges,omitempty"`
+}
+
+func (x *ConnectionTrainingState) Reset() {
+	*x = ConnectionTrainingState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectionTrainingState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionTrainingState) ProtoMessage() {}
+
+func (x *ConnectionTrainingState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_networkspb_v2_networks_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionTrainingState.ProtoReflect.Descriptor instead.
+func (*ConnectionTrainingState) Descriptor() ([]byte, []int) {
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConnectionTrainingState) GetWeightNudgeSum() float64 {
+	if x != nil {
+		return x.WeightNudgeSum
+	}
+	return 0
+}
+
+func (x *ConnectionTrainingState) GetNudges() uint64 {
+	if x != nil {
+		return x.Nudges
+	}
+	return 0
+}
+
+var File_pkg_network_networkspb_v2_networks_proto protoreflect.FileDescriptor
+
+var file_pkg_network_networkspb_v2_networks_proto_rawDesc = []byte{
+	0x0a, 0x28, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x62, 0x2f, 0x76, 0x32, 0x2f, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x6a, 0x6e, 0x65, 0x74,
+	0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x22, 0x9b, 0x02, 0x0a, 0x07,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x38, 0x0a, 0x18, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x2e, 0x0a, 0x06, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x2e, 0x76, 0x32, 0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x06, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6a, 0x6e, 0x65,
+	0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x2e, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa4, 0x01, 0x0a, 0x05, 0x4c, 0x61,
+	0x79, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x6e, 0x65, 0x75, 0x72, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x2e, 0x4e, 0x65, 0x75, 0x72, 0x6f, 0x6e, 0x52, 0x07, 0x6e,
+	0x65, 0x75, 0x72, 0x6f, 0x6e, 0x73, 0x12, 0x38, 0x0a, 0x18, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x2e, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a,
+	0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32,
+	0x2e, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64,
+	0x22, 0xc2, 0x02, 0x0a, 0x06, 0x4e, 0x65, 0x75, 0x72, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x69, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x04, 0x62, 0x69, 0x61, 0x73, 0x12, 0x3d, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6a, 0x6e, 0x65,
+	0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x2e, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x38, 0x0a, 0x18, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x4b,
+	0x0a, 0x0e, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x2e, 0x4e, 0x65, 0x75, 0x72, 0x6f, 0x6e, 0x54,
+	0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x48, 0x0a, 0x0f, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x0e, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x6f, 0x72, 0x6d, 0x22, 0x5e, 0x0a, 0x0e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x34, 0x0a, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x65, 0x70, 0x52, 0x05,
+	0x73, 0x74, 0x65, 0x70, 0x73, 0x22, 0xa1, 0x01, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x6f, 0x72, 0x6d, 0x53, 0x74, 0x65, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x65, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x74, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x73, 0x74,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x53, 0x0a, 0x13, 0x4e, 0x65, 0x75,
+	0x72, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x24, 0x0a, 0x0e, 0x62, 0x69, 0x61, 0x73, 0x5f, 0x6e, 0x75, 0x64, 0x67, 0x65, 0x5f, 0x73,
+	0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x62, 0x69, 0x61, 0x73, 0x4e, 0x75,
+	0x64, 0x67, 0x65, 0x53, 0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x64, 0x67, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x75, 0x64, 0x67, 0x65, 0x73, 0x22, 0x75,
+	0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x4f, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x6a,
+	0x6e, 0x65, 0x74, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x76, 0x32, 0x2e, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e,
+	0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x5b, 0x0a, 0x17, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x28, 0x0a, 0x10, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x6e, 0x75, 0x64, 0x67, 0x65,
+	0x5f, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x4e, 0x75, 0x64, 0x67, 0x65, 0x53, 0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75,
+	0x64, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x75, 0x64, 0x67,
+	0x65, 0x73, 0x2a, 0x6b, 0x0a, 0x09, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x1a, 0x0a, 0x16, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4c,
+	0x41, 0x59, 0x45, 0x52, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x10,
+	0x01, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x48, 0x49, 0x44, 0x44, 0x45, 0x4e, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x41, 0x59, 0x45,
+	0x52, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x10, 0x03, 0x42,
+	0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x49, 0x6e,
+	0x73, 0x75, 0x6c, 0x69, 0x6e, 0x63, 0x65, 0x2f, 0x6a, 0x6e, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x62, 0x2f, 0x76, 0x32, 0x3b, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_network_networkspb_v2_networks_proto_rawDescOnce sync.Once
+	file_pkg_network_networkspb_v2_networks_proto_rawDescData = file_pkg_network_networkspb_v2_networks_proto_rawDesc
+)
+
+func file_pkg_network_networkspb_v2_networks_proto_rawDescGZIP() []byte {
+	file_pkg_network_networkspb_v2_networks_proto_rawDescOnce.Do(func() {
+		file_pkg_network_networkspb_v2_networks_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_network_networkspb_v2_networks_proto_rawDescData)
+	})
+	return file_pkg_network_networkspb_v2_networks_proto_rawDescData
+}
+
+var file_pkg_network_networkspb_v2_networks_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_network_networkspb_v2_networks_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_pkg_network_networkspb_v2_networks_proto_goTypes = []interface{}{
+	(LayerKind)(0),                  // 0: jnet.network.v2.LayerKind
+	(*Network)(nil),                 // 1: jnet.network.v2.Network
+	(*Layer)(nil),                   // 2: jnet.network.v2.Layer
+	(*Neuron)(nil),                  // 3: jnet.network.v2.Neuron
+	(*InputTransform)(nil),          // 4: jnet.network.v2.InputTransform
+	(*TransformStep)(nil),           // 5: jnet.network.v2.TransformStep
+	(*NeuronTrainingState)(nil),     // 6: jnet.network.v2.NeuronTrainingState
+	(*Connection)(nil),              // 7: jnet.network.v2.Connection
+	(*ConnectionTrainingState)(nil), // 8: jnet.network.v2.ConnectionTrainingState
+	nil,                             // 9: jnet.network.v2.Network.MetadataEntry
+}
+var file_pkg_network_networkspb_v2_networks_proto_depIdxs = []int32{
+	2, // 0: jnet.network.v2.Network.layers:type_name -> jnet.network.v2.Layer
+	9, // 1: jnet.network.v2.Network.metadata:type_name -> jnet.network.v2.Network.MetadataEntry
+	3, // 2: jnet.network.v2.Layer.neurons:type_name -> jnet.network.v2.Neuron
+	0, // 3: jnet.network.v2.Layer.kind:type_name -> jnet.network.v2.LayerKind
+	7, // 4: jnet.network.v2.Neuron.connections:type_name -> jnet.network.v2.Connection
+	6, // 5: jnet.network.v2.Neuron.training_state:type_name -> jnet.network.v2.NeuronTrainingState
+	4, // 6: jnet.network.v2.Neuron.input_transform:type_name -> jnet.network.v2.InputTransform
+	5, // 7: jnet.network.v2.InputTransform.steps:type_name -> jnet.network.v2.TransformStep
+	8, // 8: jnet.network.v2.Connection.training_state:type_name -> jnet.network.v2.ConnectionTrainingState
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_pkg_network_networkspb_v2_networks_proto_init() }
+func file_pkg_network_networkspb_v2_networks_proto_init() {
+	if File_pkg_network_networkspb_v2_networks_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Network); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Layer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Neuron); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InputTransform); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransformStep); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NeuronTrainingState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Connection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_networkspb_v2_networks_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectionTrainingState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_network_networkspb_v2_networks_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_network_networkspb_v2_networks_proto_goTypes,
+		DependencyIndexes: file_pkg_network_networkspb_v2_networks_proto_depIdxs,
+		EnumInfos:         file_pkg_network_networkspb_v2_networks_proto_enumTypes,
+		MessageInfos:      file_pkg_network_networkspb_v2_networks_proto_msgTypes,
+	}.Build()
+	File_pkg_network_networkspb_v2_networks_proto = out.File
+	file_pkg_network_networkspb_v2_networks_proto_rawDesc = nil
+	file_pkg_network_networkspb_v2_networks_proto_goTypes = nil
+	file_pkg_network_networkspb_v2_networks_proto_depIdxs = nil
+}
@@ -29,6 +29,9 @@ type Neuron struct {
 	// that this neuron corresponds to. It should only be set on input and/or
 	// output Neurons. Hidden layer neurons should not use this field.
 	label string
+	// transform, if set, computes the value of an input Neuron from the raw
+	// input given to a prediction. It should only be set on input Neurons.
+	transform *InputTransform
 	// value represents how much or little this Neuron fired on the last pass.
 	// It is safe to interpret this field after a pass is completed, but if a
 	// pass has not been completed OR a reset has been executed, then value will
@@ -59,12 +62,15 @@ type Neuron struct {
 	// is solely to aid in the training process.
 	dNetDBias float64
 
-	// biasNudges is the set of nudges that would push this Neuron's bias
-	// towards having a value which more effectively reduces the output of the
-	// loss function. The average value across this slice is the direction of
-	// greatest descent and is used when gradient descent is executed. These
-	// values are (and should be) lost when resetFromBatch is called.
-	biasNudges []float64
+	// biasNudgeSum is the running sum of the nudges that would push this
+	// Neuron's bias towards having a value which more effectively reduces the
+	// output of the loss function. Divided by nudges, it is the direction of
+	// greatest descent and is used when gradient descent is executed. This
+	// value is (and should be) lost when resetFromBatch is called.
+	biasNudgeSum float64
+	// nudges is the number of bias nudges that have been summed into
+	// biasNudgeSum since the last call to resetFromBatch.
+	nudges int
 }
 
 // NewNeuron creates a new Neuron and connects it to pl with randomized weights
@@ -137,8 +143,8 @@ func (n *Neuron) ConnectNeurons(pl Layer) error {
 // This function should always be called after running a full batch/minibatch
 // before you run another one.
 //
-// This function differs from resetFromPass in that it clears n.biasNudges while
-// resetFromPass does not.
+// This function differs from resetFromPass in that it clears n.biasNudgeSum
+// while resetFromPass does not.
 func (n *Neuron) resetFromBatch() {
 	n.value = 0.0
 	n.wSum = 0.0
@@ -148,7 +154,8 @@ func (n *Neuron) resetFromBatch() {
 	n.dValueDNet = 0.0
 	n.dNetDBias = 0.0
 
-	n.biasNudges = nil
+	n.biasNudgeSum = 0.0
+	n.nudges = 0
 
 	for ci := range n.Connections {
 		n.Connections[ci].resetFromBatch()
@@ -164,7 +171,7 @@ func (n *Neuron) resetFromBatch() {
 // before you run another one.
 //
 // This function differs from resetFromBatch in that it does not clear
-// n.biasNudges while resetFromBatch does.
+// n.biasNudgeSum while resetFromBatch does.
 func (n *Neuron) resetFromPass() {
 	n.value = 0.0
 	n.wSum = 0.0
@@ -179,15 +186,17 @@ func (n *Neuron) resetFromPass() {
 	}
 }
 
-// recordNudges records the current value of dLossDBias as a new entry in
-// n.biasNudges. After a forward + backward pass, dLossDBias is set to the
-// direction of greatest improvement of the loss function if it were to be
-// applied to n.bias. It should be recorded so that when n.adjustWeights is
-// called, it can be applied to reduce the loss function.
+// recordNudges adds the current value of dLossDBias to n.biasNudgeSum. After a
+// forward + backward pass, dLossDBias is set to the direction of greatest
+// improvement of the loss function if it were to be applied to n.bias. It
+// should be recorded so that when n.adjustWeights is called, it can be applied
+// to reduce the loss function. Only a running sum and count are kept, so the
+// memory used does not grow with the number of passes recorded.
 //
 // recordNudges also records all nudges to n's Connections.
 func (n *Neuron) recordNudges() {
-	n.biasNudges = append(n.biasNudges, n.dLossDBias)
+	n.biasNudgeSum += n.dLossDBias
+	n.nudges++
 
 	// For every Connection from this Neuron to the previous Layer's neurons...
 	for ci := range n.Connections {
@@ -195,14 +204,14 @@ func (n *Neuron) recordNudges() {
 	}
 }
 
-// averageBiasNudge determines the average value across all values in
-// n.biasNudges.
+// averageBiasNudge determines the average value of all bias nudges recorded
+// since the last call to resetFromBatch. If none have been recorded, it
+// returns 0.
 func (n *Neuron) averageBiasNudge() float64 {
-	sum := 0.0
-	for _, bn := range n.biasNudges {
-		sum += bn
+	if n.nudges == 0 {
+		return 0
 	}
-	return sum / float64(len(n.biasNudges))
+	return n.biasNudgeSum / float64(n.nudges)
 }
 
 // adjustWeights adjusts n's bias in the direction of greatest improvement of
@@ -0,0 +1,399 @@
+package network
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+// NPZSidecarName is the name of the JSON file stored alongside the arrays in
+// an .npz archive written by WriteNPZ. From Python it can be read with
+// json.loads(zipfile.ZipFile(path).read("jnet.json")).
+const NPZSidecarName = "jnet.json"
+
+// npyMagic prefixes every .npy file.
+var npyMagic = []byte("\x93NUMPY")
+
+// NPZSidecar describes the network whose parameters are stored in an .npz
+// archive.
+type NPZSidecar struct {
+	NeuronMap               []int                       `json:"neuronMap"`
+	InputLabels             []string                    `json:"inputLabels"`
+	OutputLabels            []string                    `json:"outputLabels"`
+	ActivationFunctionNames [][]activationfunction.Name `json:"activationFunctionNames"`
+	InputTransforms         []InputTransform            `json:"inputTransforms,omitempty"`
+}
+
+// npzWeightsName and npzBiasesName return the names of the arrays holding the
+// weights and biases of layer li in an .npz archive.
+func npzWeightsName(li int) string { return fmt.Sprintf("layer%v_weights", li) }
+func npzBiasesName(li int) string  { return fmt.Sprintf("layer%v_biases", li) }
+
+// WriteNPZ writes the parameters of nw to w as an .npz archive, which can be
+// loaded in Python via numpy.load.
+//
+// For every layer li after the input layer, the archive holds a float64 array
+// named "layer<li>_weights" of shape (neurons in layer, neurons in previous
+// layer) and one named "layer<li>_biases" of shape (neurons in layer,). These
+// are laid out identically to layer li of the arguments of SetConnectionWeights
+// and SetNeuronBiases, so weights[j][i] is the weight between neuron i of the
+// previous layer and neuron j of layer li. The archive also holds an
+// NPZSidecar named NPZSidecarName.
+func (nw Network) WriteNPZ(w io.Writer) error {
+	if err := checkFullyConnected(nw); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	weights, biases := nw.ConnectionWeights(), nw.NeuronBiases()
+	for li := 1; li < len(nw); li++ {
+		if err := writeNPZEntry(zw, npzWeightsName(li)+".npy", func(w io.Writer) error {
+			return writeNPY(w, []int{len(nw[li]), len(nw[li-1])}, weights[li])
+		}); err != nil {
+			return err
+		}
+		if err := writeNPZEntry(zw, npzBiasesName(li)+".npy", func(w io.Writer) error {
+			return writeNPY(w, []int{len(nw[li])}, [][]float64{biases[li]})
+		}); err != nil {
+			return err
+		}
+	}
+
+	spec := nw.Spec()
+	sc := NPZSidecar{
+		NeuronMap:               spec.NeuronMap,
+		InputLabels:             spec.InputLabels,
+		OutputLabels:            spec.OutputLabels,
+		ActivationFunctionNames: nw.NeuronActivationFunctions(),
+		InputTransforms:         nw.InputTransforms(),
+	}
+	if err := writeNPZEntry(zw, NPZSidecarName, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sc)
+	}); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return errors.Wrap(err, "zip close")
+	}
+	return nil
+}
+
+// MustWriteNPZ calls WriteNPZ but panics if an error is encountered.
+func (nw Network) MustWriteNPZ(w io.Writer) {
+	err := nw.WriteNPZ(w)
+	if err != nil {
+		panic(errors.Wrap(err, "must write npz"))
+	}
+}
+
+// ReadNPZ loads the weights and biases stored in the .npz archive r, of the
+// given size, into nw, which must already have the shape of the network they
+// were written from. If the archive holds an NPZSidecar, its neuron map must
+// match nw's and its activation functions and input transforms are loaded into
+// nw too. Labels are left as they are.
+//
+// Arrays may be float32 or float64 in either byte order, and in C or Fortran
+// order, so archives saved from Python via numpy.savez can be read as long as
+// they use the array names documented on WriteNPZ. If any array is missing or
+// has the wrong shape, an error is returned and nw is left unchanged.
+func (nw Network) ReadNPZ(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return errors.Wrap(err, "zip new reader")
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var (
+		afns       [][]activationfunction.Name
+		sidecar    bool
+		transforms []InputTransform
+	)
+	if f, found := files[NPZSidecarName]; found {
+		var sc NPZSidecar
+		if err := readNPZEntry(f, func(r io.Reader) error {
+			return json.NewDecoder(r).Decode(&sc)
+		}); err != nil {
+			return err
+		}
+		if got, want := fmt.Sprint(sc.NeuronMap), fmt.Sprint(nw.Spec().NeuronMap); got != want {
+			return fmt.Errorf("npz archive is for a network with neuron map %v, but the network has neuron map %v", got, want)
+		}
+		afns = sc.ActivationFunctionNames
+		sidecar, transforms = true, sc.InputTransforms
+	}
+
+	weights, biases := nw.ConnectionWeights(), nw.NeuronBiases()
+	for li := 1; li < len(nw); li++ {
+		w, err := readNPZArray(files, npzWeightsName(li), len(nw[li]), len(nw[li-1]))
+		if err != nil {
+			return err
+		}
+		weights[li] = w
+
+		b, err := readNPZArray(files, npzBiasesName(li), 1, len(nw[li]))
+		if err != nil {
+			return err
+		}
+		biases[li] = b[0]
+	}
+
+	// NOTE: Every array shape has already been checked, and SetInputTransforms
+	// checks the input transforms before setting any, so the only thing left
+	// that can fail is an activation function of the wrong shape or an unknown
+	// one. Check them all before setting anything so that nw is left
+	// unchanged.
+	if afns != nil && len(afns) != len(nw) {
+		return fmt.Errorf("npz archive has activation functions for %v layers, but the network has %v layers", len(afns), len(nw))
+	}
+	for li, l := range afns {
+		if len(l) != len(nw[li]) {
+			return fmt.Errorf("npz archive has activation functions for %v neurons in layer %v, but the network has %v neurons in it", len(l), li, len(nw[li]))
+		}
+		for _, afn := range l {
+			if _, err := activationfunction.GetFunction(afn); err != nil {
+				return err
+			}
+		}
+	}
+	if sidecar {
+		if err := nw.SetInputTransforms(transforms); err != nil {
+			return errors.Wrap(err, "setting input transforms")
+		}
+	}
+	if afns != nil {
+		if err := nw.SetNeuronActivationFunctions(afns); err != nil {
+			return errors.Wrap(err, "setting activation functions")
+		}
+	}
+	if err := nw.SetConnectionWeights(weights); err != nil {
+		return errors.Wrap(err, "setting weights")
+	}
+	if err := nw.SetNeuronBiases(biases); err != nil {
+		return errors.Wrap(err, "setting biases")
+	}
+
+	return nil
+}
+
+// MustReadNPZ calls ReadNPZ but panics if an error is encountered.
+func (nw Network) MustReadNPZ(r io.ReaderAt, size int64) {
+	err := nw.ReadNPZ(r, size)
+	if err != nil {
+		panic(errors.Wrap(err, "must read npz"))
+	}
+}
+
+// writeNPZEntry adds a file named name to zw whose contents are written by fn.
+func writeNPZEntry(zw *zip.Writer, name string, fn func(io.Writer) error) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return errors.Wrapf(err, "zip create %v", name)
+	}
+	if err := fn(w); err != nil {
+		return errors.Wrapf(err, "writing %v", name)
+	}
+	return nil
+}
+
+// readNPZEntry opens the file f and passes its contents to fn.
+func readNPZEntry(f *zip.File, fn func(io.Reader) error) error {
+	rc, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "zip open %v", f.Name)
+	}
+	defer rc.Close()
+	if err := fn(rc); err != nil {
+		return errors.Wrapf(err, "reading %v", f.Name)
+	}
+	return nil
+}
+
+// readNPZArray reads the array named name from files, which must have shape
+// (rows, cols). 1 dimensional arrays are returned as a single row.
+func readNPZArray(files map[string]*zip.File, name string, rows, cols int) ([][]float64, error) {
+	f, found := files[name+".npy"]
+	if !found {
+		return nil, fmt.Errorf("npz archive has no array named %v", name)
+	}
+
+	var a [][]float64
+	if err := readNPZEntry(f, func(r io.Reader) error {
+		var err error
+		a, err = readNPY(r, rows, cols)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// writeNPY writes the rows of a as a version 1.0 .npy file of little endian
+// float64s with the given shape.
+func writeNPY(w io.Writer, dims []int, a [][]float64) error {
+	var shape string
+	if len(dims) == 1 {
+		shape = fmt.Sprintf("(%v,)", dims[0])
+	} else {
+		shape = fmt.Sprintf("(%v, %v)", dims[0], dims[1])
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': %v, }", shape)
+
+	// NOTE: The header is padded with spaces and terminated by a newline so
+	// that the data starts on a 64 byte boundary, as numpy does.
+	prefix := len(npyMagic) + 2 + 2
+	pad := 64 - (prefix+len(header)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	header += strings.Repeat(" ", pad) + "\n"
+
+	var b bytes.Buffer
+	b.Write(npyMagic)
+	b.Write([]byte{1, 0})
+	_ = binary.Write(&b, binary.LittleEndian, uint16(len(header)))
+	b.WriteString(header)
+	for _, row := range a {
+		for _, v := range row {
+			_ = binary.Write(&b, binary.LittleEndian, math.Float64bits(v))
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+var (
+	npyDescrRe   = regexp.MustCompile(`'descr':\s*'([^']*)'`)
+	npyFortranRe = regexp.MustCompile(`'fortran_order':\s*(True|False)`)
+	npyShapeRe   = regexp.MustCompile(`'shape':\s*\(([^)]*)\)`)
+)
+
+// readNPY reads a 1 or 2 dimensional array of floats from a .npy file of any
+// version, which must have shape (rows, cols). 1 dimensional arrays are
+// returned as a single row, and so must have shape (cols,) with rows of 1.
+//
+// NOTE: The shape is checked before anything is allocated for the data, so
+// that a malformed or hostile header can't cause a huge allocation.
+func readNPY(r io.Reader, rows, cols int) ([][]float64, error) {
+	magic := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, errors.Wrap(err, "reading npy magic")
+	}
+	if !bytes.HasPrefix(magic, npyMagic) {
+		return nil, errors.New("not an npy file")
+	}
+
+	var hl uint32
+	switch major := magic[len(npyMagic)]; major {
+	case 1:
+		var hl16 uint16
+		if err := binary.Read(r, binary.LittleEndian, &hl16); err != nil {
+			return nil, errors.Wrap(err, "reading npy header length")
+		}
+		hl = uint32(hl16)
+	case 2, 3:
+		if err := binary.Read(r, binary.LittleEndian, &hl); err != nil {
+			return nil, errors.Wrap(err, "reading npy header length")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported npy version %v", major)
+	}
+	header := make([]byte, hl)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.Wrap(err, "reading npy header")
+	}
+
+	descr := npyDescrRe.FindSubmatch(header)
+	fortran := npyFortranRe.FindSubmatch(header)
+	shape := npyShapeRe.FindSubmatch(header)
+	if descr == nil || fortran == nil || shape == nil {
+		return nil, fmt.Errorf("malformed npy header %q", header)
+	}
+
+	var dims []int
+	for _, d := range strings.Split(string(shape[1]), ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing npy shape %q", shape[1])
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("npy shape (%v) has a negative dimension", shape[1])
+		}
+		dims = append(dims, n)
+	}
+	switch len(dims) {
+	case 1:
+		if rows != 1 || dims[0] != cols {
+			return nil, fmt.Errorf("array has shape (%v,), expected (%v, %v)", dims[0], rows, cols)
+		}
+	case 2:
+		if dims[0] != rows || dims[1] != cols {
+			return nil, fmt.Errorf("array has shape (%v, %v), expected (%v, %v)", dims[0], dims[1], rows, cols)
+		}
+	default:
+		return nil, fmt.Errorf("only 1 and 2 dimensional arrays are supported, got shape (%v)", shape[1])
+	}
+
+	var order binary.ByteOrder
+	var size int
+	switch d := string(descr[1]); d {
+	case "<f8", "<f4":
+		order = binary.LittleEndian
+	case ">f8", ">f4":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("unsupported npy dtype %v, only float32 and float64 are supported", d)
+	}
+	size = int(descr[1][2] - '0')
+
+	data := make([]byte, rows*cols*size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrap(err, "reading npy data")
+	}
+	if n, _ := io.ReadFull(r, make([]byte, 1)); n > 0 {
+		return nil, fmt.Errorf("npy data is longer than the %v bytes of shape (%v)", len(data), shape[1])
+	}
+
+	a := make([][]float64, rows)
+	for ri := range a {
+		a[ri] = make([]float64, cols)
+	}
+	for i := 0; i < rows*cols; i++ {
+		var v float64
+		if size == 8 {
+			v = math.Float64frombits(order.Uint64(data[i*8:]))
+		} else {
+			v = float64(math.Float32frombits(order.Uint32(data[i*4:])))
+		}
+
+		ri, ci := i/cols, i%cols
+		if string(fortran[1]) == "True" {
+			ri, ci = i%rows, i/rows
+		}
+		a[ri][ci] = v
+	}
+
+	return a, nil
+}
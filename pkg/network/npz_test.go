@@ -0,0 +1,256 @@
+package network
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_NPZ_WriteAndReadAreInverses(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetNeuronActivationFunctionsTo(activationfunction.NameRelu)
+
+	var b bytes.Buffer
+	nw.MustWriteNPZ(&b)
+
+	nw2 := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw2.MustReadNPZ(bytes.NewReader(b.Bytes()), int64(b.Len()))
+
+	// NOTE: The biases of the input layer are never used, so they aren't
+	// written.
+	if !reflect.DeepEqual(nw2.ConnectionWeights(), nw.ConnectionWeights()) {
+		t.Fatalf("weights were not read, got %v", nw2.ConnectionWeights())
+	}
+	if !reflect.DeepEqual(nw2.NeuronBiases()[1:], nw.NeuronBiases()[1:]) {
+		t.Fatalf("biases were not read, got %v", nw2.NeuronBiases())
+	}
+	if !reflect.DeepEqual(nw2.NeuronActivationFunctions(), nw.NeuronActivationFunctions()) {
+		t.Fatalf("activation functions were not read, got %v", nw2.NeuronActivationFunctions())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	expected := []string{"layer1_weights.npy", "layer1_biases.npy", "layer2_weights.npy", "layer2_biases.npy", NPZSidecarName}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected archive entries %v, got %v", expected, names)
+	}
+}
+
+func Test_writeNPY(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeNPY(&b, []int{1, 2}, [][]float64{{1.5, -2}}); err != nil {
+		t.Fatal(err)
+	}
+	bs := b.Bytes()
+
+	if !bytes.HasPrefix(bs, []byte("\x93NUMPY\x01\x00")) {
+		t.Fatalf("unexpected magic %q", bs[:8])
+	}
+	hl := int(binary.LittleEndian.Uint16(bs[8:10]))
+	if (10+hl)%64 != 0 {
+		t.Fatalf("data does not start on a 64 byte boundary, header length %v", hl)
+	}
+	header := string(bs[10 : 10+hl])
+	if want := "{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2), }"; header[:len(want)] != want || header[hl-1] != '\n' {
+		t.Fatalf("unexpected header %q", header)
+	}
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(bs[10+hl+8:])); got != -2 {
+		t.Fatalf("unexpected second value %v", got)
+	}
+
+	a, err := readNPY(bytes.NewReader(bs), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, [][]float64{{1.5, -2}}) {
+		t.Fatalf("unexpected array %v", a)
+	}
+}
+
+// rawNPY builds a .npy file by hand, as numpy would for the given dtype and
+// fortran order.
+func rawNPY(header string, data interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString("\x93NUMPY\x01\x00")
+	_ = binary.Write(&b, binary.LittleEndian, uint16(len(header)+1))
+	b.WriteString(header + "\n")
+	_ = binary.Write(&b, binary.LittleEndian, data)
+	return b.Bytes()
+}
+
+func Test_ReadNPZ_FromNumPy(t *testing.T) {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, bs := range map[string][]byte{
+		// A float32 matrix of shape (4, 3) in Fortran order, so stored column
+		// by column.
+		"layer1_weights.npy": rawNPY("{'descr': '<f4', 'fortran_order': True, 'shape': (4, 3), }", []float32{
+			0, 1, 2, 3,
+			10, 11, 12, 13,
+			20, 21, 22, 23,
+		}),
+		"layer1_biases.npy":  rawNPY("{'descr': '<f4', 'fortran_order': False, 'shape': (4,), }", []float32{1, 2, 3, 4}),
+		"layer2_weights.npy": rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 4), }", []float64{0.5, 0.25, 0.125, 0.0625}),
+		"layer2_biases.npy":  rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (1,), }", []float64{-1}),
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write(bs)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetNeuronActivationFunctionsTo(activationfunction.NameRelu)
+	afns := nw.NeuronActivationFunctions()
+	nw.MustReadNPZ(bytes.NewReader(b.Bytes()), int64(b.Len()))
+
+	weights := nw.ConnectionWeights()
+	if want := [][]float64{{0, 10, 20}, {1, 11, 21}, {2, 12, 22}, {3, 13, 23}}; !reflect.DeepEqual(weights[1], want) {
+		t.Fatalf("expected layer 1 weights %v, got %v", want, weights[1])
+	}
+	if want := [][]float64{{0.5, 0.25, 0.125, 0.0625}}; !reflect.DeepEqual(weights[2], want) {
+		t.Fatalf("expected layer 2 weights %v, got %v", want, weights[2])
+	}
+	if got := nw.NeuronBiases(); !reflect.DeepEqual(got[1:], [][]float64{{1, 2, 3, 4}, {-1}}) {
+		t.Fatalf("unexpected biases %v", got)
+	}
+	if !reflect.DeepEqual(nw.NeuronActivationFunctions(), afns) {
+		t.Fatalf("activation functions changed without a sidecar")
+	}
+}
+
+func Test_ReadNPZ_ShapeMismatch(t *testing.T) {
+	var b bytes.Buffer
+	src := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	src.MustWriteNPZ(&b)
+
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 5, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	weights := nw.ConnectionWeights()
+
+	if err := nw.ReadNPZ(bytes.NewReader(b.Bytes()), int64(b.Len())); err == nil {
+		t.Fatalf("expected an error reading into a network of a different shape")
+	}
+	if !reflect.DeepEqual(nw.ConnectionWeights(), weights) {
+		t.Fatalf("network was modified by a failed read")
+	}
+}
+
+func Test_ReadNPZ_SidecarShapeMismatch(t *testing.T) {
+	src := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	var npz bytes.Buffer
+	src.MustWriteNPZ(&npz)
+	zr, err := zip.NewReader(bytes.NewReader(npz.Bytes()), int64(npz.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The sidecar has valid input transforms, which are set before the
+	// activation functions, but is missing the activation functions of the
+	// output layer.
+	sc := NPZSidecar{
+		NeuronMap:               []int{3, 4, 1},
+		ActivationFunctionNames: src.NeuronActivationFunctions()[:2],
+		InputTransforms:         []InputTransform{{Source: 0}, {Source: 0}, {Source: 1}},
+	}
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, f := range zr.File {
+		if f.Name == NPZSidecarName {
+			continue
+		}
+		if err := zw.Copy(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := zw.Create(NPZSidecarName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewEncoder(w).Encode(sc); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 1},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameRelu,
+	})
+	weights, afns := nw.ConnectionWeights(), nw.NeuronActivationFunctions()
+
+	if err := nw.ReadNPZ(bytes.NewReader(b.Bytes()), int64(b.Len())); err == nil {
+		t.Fatalf("expected an error reading activation functions of the wrong shape")
+	}
+	if nw.InputTransforms() != nil || !reflect.DeepEqual(nw.NeuronActivationFunctions(), afns) || !reflect.DeepEqual(nw.ConnectionWeights(), weights) {
+		t.Fatalf("network was modified by a failed read")
+	}
+}
+
+func Test_readNPY_Invalid(t *testing.T) {
+	tcs := map[string][]byte{
+		"negative dimension": rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (-1, 2), }", []float64{}),
+		"huge dimension":     rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (1000000000, 1000000000), }", []float64{}),
+		"wrong shape":        rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 1), }", []float64{1, 2}),
+		"wrong 1d shape":     rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }", []float64{1, 2, 3}),
+		"short data":         rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2), }", []float64{1}),
+		"long data":          rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2), }", []float64{1, 2, 3}),
+	}
+
+	for name, bs := range tcs {
+		if _, err := readNPY(bytes.NewReader(bs), 1, 2); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+	if _, err := readNPY(bytes.NewReader(rawNPY("{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }", []float64{1, 2})), 1, 2); err != nil {
+		t.Fatalf("expected a 1 dimensional array to be read as a single row, got %v", err)
+	}
+}
@@ -0,0 +1,731 @@
+package network
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network/onnxpb"
+)
+
+const (
+	// onnxIRVersion and onnxOpsetVersion are the ONNX IR and default operator
+	// set versions declared by exported models. Every operator used has had
+	// the same semantics since well before these versions.
+	onnxIRVersion    = 7
+	onnxOpsetVersion = 13
+
+	onnxInputName  = "input"
+	onnxOutputName = "output"
+
+	// onnxInputLabelsKey and onnxOutputLabelsKey are the metadata_props keys
+	// holding the JSON encoded input and output labels of an exported Network.
+	onnxInputLabelsKey  = "jnet.inputLabels"
+	onnxOutputLabelsKey = "jnet.outputLabels"
+)
+
+// UnsupportedOperatorError is returned when an ONNX model uses an operator, or
+// a combination of operators, which has no equivalent in a Network.
+type UnsupportedOperatorError struct {
+	// OpType is the type of the offending operator, such as "Conv".
+	OpType string
+	// Node is the name of the offending node, if it has one.
+	Node string
+	// Reason optionally explains why the operator is unsupported.
+	Reason string
+}
+
+func (e *UnsupportedOperatorError) Error() string {
+	msg := fmt.Sprintf("unsupported onnx operator %v", e.OpType)
+	if e.Node != "" {
+		msg += fmt.Sprintf(" in node %q", e.Node)
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+type onnxTranslator struct {
+	opts []TranslatorOption
+}
+
+var _ Translator = new(onnxTranslator)
+
+// NewOnnxTranslator returns a Translator which converts a Network to and from
+// an ONNX model, so that it can be used outside of the Go ecosystem.
+//
+// Every layer after the input layer is exported as a Gemm node, with a weight
+// matrix of shape [neurons in layer, neurons in previous layer] and transB set,
+// followed by the nodes of its activation function. Tensors are doubles, so no
+// precision is lost. Because jnet's sigmoid ranges over (-1, 1), it is exported
+// as 2*Sigmoid(x)-1. Every neuron in a layer must share an activation function,
+// and the noop activation function can't be exported, nor can input
+// transforms. Input and output labels are stored in the model's metadata.
+//
+// Only the subset of ONNX describing a multilayer perceptron can be imported: a
+// single chain of Gemm, or MatMul followed by an optional Add, nodes each
+// followed by an optional Relu, Tanh, 2*Sigmoid(x)-1 or Identity activation.
+// Tensors may be floats or doubles. Any other operator results in an
+// *UnsupportedOperatorError. Because the input layer of a Network has no
+// ONNX equivalent, its neurons are always imported with the linear activation
+// function.
+func NewOnnxTranslator(opts ...TranslatorOption) Translator {
+	return onnxTranslator{
+		opts: opts,
+	}
+}
+
+func (ot onnxTranslator) Serialize(nw Network) ([]byte, error) {
+	if err := checkFullyConnected(nw); err != nil {
+		return nil, err
+	}
+
+	bs, err := marshalOnnx(nw)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err = serialize(bs, ot.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+// MustSerialize calls Serialize but panics if an error is encountered.
+func (ot onnxTranslator) MustSerialize(nw Network) []byte {
+	bs, err := ot.Serialize(nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must serialize"))
+	}
+	return bs
+}
+
+func (ot onnxTranslator) Deserialize(bs []byte) (Network, error) {
+	bs, err := deserialize(bs, ot.opts)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalOnnx(bs)
+}
+
+// MustDeserialize calls Deserialize but panics if an error is encountered.
+func (ot onnxTranslator) MustDeserialize(bs []byte) Network {
+	nw, err := ot.Deserialize(bs)
+	if err != nil {
+		panic(errors.Wrap(err, "must deserialize"))
+	}
+	return nw
+}
+
+// Encode writes the ONNX model of nw to w, applying ot's options as it is
+// written.
+//
+// NOTE: Protocol buffers can only be marshalled as a whole message, so the
+// ONNX model itself is held in memory, but none of ot's options are.
+func (ot onnxTranslator) Encode(w io.Writer, nw Network) error {
+	if err := checkFullyConnected(nw); err != nil {
+		return err
+	}
+
+	return encode(w, ot.opts, func(w io.Writer) error {
+		bs, err := marshalOnnx(nw)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	})
+}
+
+// MustEncode calls Encode but panics if an error is encountered.
+func (ot onnxTranslator) MustEncode(w io.Writer, nw Network) {
+	err := ot.Encode(w, nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must encode"))
+	}
+}
+
+// Decode reads an ONNX model from r, reversing ot's options as it is read.
+//
+// NOTE: Protocol buffers can only be unmarshalled as a whole message, so the
+// ONNX model itself is held in memory, but none of ot's options are.
+func (ot onnxTranslator) Decode(r io.Reader) (Network, error) {
+	return decode(r, ot.opts, func(r io.Reader) (Network, error) {
+		bs, err := io.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading")
+		}
+		return unmarshalOnnx(bs)
+	})
+}
+
+// MustDecode calls Decode but panics if an error is encountered.
+func (ot onnxTranslator) MustDecode(rd io.Reader) Network {
+	nw, err := ot.Decode(rd)
+	if err != nil {
+		panic(errors.Wrap(err, "must decode"))
+	}
+	return nw
+}
+
+func marshalOnnx(nw Network) ([]byte, error) {
+	m, err := toOnnx(nw)
+	if err != nil {
+		return nil, errors.Wrap(err, "to onnx")
+	}
+	bs, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "proto marshalling")
+	}
+	return bs, nil
+}
+
+func unmarshalOnnx(bs []byte) (Network, error) {
+	m := &onnxpb.ModelProto{}
+	if err := proto.Unmarshal(bs, m); err != nil {
+		return nil, errors.Wrap(err, "proto unmarshalling")
+	}
+	nw, err := fromOnnx(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "from onnx")
+	}
+	return nw, nil
+}
+
+func toOnnx(nw Network) (*onnxpb.ModelProto, error) {
+	if len(nw) < 2 {
+		return nil, errors.New("network must have at least 2 layers (for input and output layer)")
+	}
+	if nw.hasInputTransforms() {
+		return nil, errors.New("networks with input transforms can't be exported to onnx")
+	}
+
+	g := &onnxpb.GraphProto{
+		Name: proto.String("jnet"),
+		Input: []*onnxpb.ValueInfoProto{
+			onnxValueInfo(onnxInputName, len(nw.FirstLayer())),
+		},
+		Output: []*onnxpb.ValueInfoProto{
+			onnxValueInfo(onnxOutputName, len(nw.LastLayer())),
+		},
+	}
+
+	addNode := func(opType, name string, inputs []string, attrs ...*onnxpb.AttributeProto) string {
+		g.Node = append(g.Node, &onnxpb.NodeProto{
+			Input:     inputs,
+			Output:    []string{name},
+			Name:      proto.String(name),
+			OpType:    proto.String(opType),
+			Attribute: attrs,
+		})
+		return name
+	}
+
+	hasSigmoidConstants := false
+	cur := onnxInputName
+	for li := 1; li < len(nw); li++ {
+		l, pl := nw[li], nw[li-1]
+
+		afn := l[0].ActivationFunctionName
+		for _, n := range l {
+			if n.ActivationFunctionName != afn {
+				return nil, fmt.Errorf("neurons in layer %v use different activation functions (%v and %v), but onnx applies activation functions to whole layers", li, afn, n.ActivationFunctionName)
+			}
+		}
+
+		w := make([]float64, 0, len(l)*len(pl))
+		b := make([]float64, 0, len(l))
+		for _, n := range l {
+			for _, c := range n.Connections {
+				w = append(w, c.weight)
+			}
+			b = append(b, n.bias)
+		}
+		wName := fmt.Sprintf("layer%v.weight", li)
+		bName := fmt.Sprintf("layer%v.bias", li)
+		g.Initializer = append(g.Initializer,
+			onnxTensor(wName, []int64{int64(len(l)), int64(len(pl))}, w),
+			onnxTensor(bName, []int64{int64(len(l))}, b),
+		)
+
+		cur = addNode("Gemm", fmt.Sprintf("layer%v.gemm", li), []string{cur, wName, bName}, &onnxpb.AttributeProto{
+			Name: proto.String("transB"),
+			Type: onnxpb.AttributeProto_INT.Enum(),
+			I:    proto.Int64(1),
+		})
+
+		switch afn {
+		case activationfunction.NameLinear:
+		case activationfunction.NameRelu:
+			cur = addNode("Relu", fmt.Sprintf("layer%v.relu", li), []string{cur})
+		case activationfunction.NameTanh:
+			cur = addNode("Tanh", fmt.Sprintf("layer%v.tanh", li), []string{cur})
+		case activationfunction.NameSigmoid:
+			if !hasSigmoidConstants {
+				g.Initializer = append(g.Initializer,
+					onnxTensor("jnet.two", nil, []float64{2}),
+					onnxTensor("jnet.one", nil, []float64{1}),
+				)
+				hasSigmoidConstants = true
+			}
+			cur = addNode("Sigmoid", fmt.Sprintf("layer%v.sigmoid", li), []string{cur})
+			cur = addNode("Mul", fmt.Sprintf("layer%v.sigmoid.scale", li), []string{cur, "jnet.two"})
+			cur = addNode("Sub", fmt.Sprintf("layer%v.sigmoid.shift", li), []string{cur, "jnet.one"})
+		default:
+			return nil, fmt.Errorf("activation function %v of layer %v has no onnx equivalent", afn, li)
+		}
+	}
+	addNode("Identity", onnxOutputName, []string{cur})
+
+	m := &onnxpb.ModelProto{
+		IrVersion: proto.Int64(onnxIRVersion),
+		OpsetImport: []*onnxpb.OperatorSetIdProto{
+			{Domain: proto.String(""), Version: proto.Int64(onnxOpsetVersion)},
+		},
+		ProducerName: proto.String("jnet"),
+		Graph:        g,
+	}
+
+	spec := nw.Spec()
+	for _, kv := range []struct {
+		key    string
+		labels []string
+	}{
+		{onnxInputLabelsKey, spec.InputLabels},
+		{onnxOutputLabelsKey, spec.OutputLabels},
+	} {
+		j, err := json.Marshal(kv.labels)
+		if err != nil {
+			return nil, errors.Wrap(err, "json marshalling labels")
+		}
+		m.MetadataProps = append(m.MetadataProps, &onnxpb.StringStringEntryProto{
+			Key:   proto.String(kv.key),
+			Value: proto.String(string(j)),
+		})
+	}
+
+	return m, nil
+}
+
+// onnxValueInfo describes a batch of double vectors of length size.
+func onnxValueInfo(name string, size int) *onnxpb.ValueInfoProto {
+	return &onnxpb.ValueInfoProto{
+		Name: proto.String(name),
+		Type: &onnxpb.TypeProto{
+			TensorType: &onnxpb.TypeProto_Tensor{
+				ElemType: proto.Int32(int32(onnxpb.TensorProto_DOUBLE)),
+				Shape: &onnxpb.TensorShapeProto{
+					Dim: []*onnxpb.TensorShapeProto_Dimension{
+						{DimParam: proto.String("N")},
+						{DimValue: proto.Int64(int64(size))},
+					},
+				},
+			},
+		},
+	}
+}
+
+// onnxTensor returns a double tensor of the given shape holding vs in row
+// major order.
+func onnxTensor(name string, dims []int64, vs []float64) *onnxpb.TensorProto {
+	return &onnxpb.TensorProto{
+		Name:       proto.String(name),
+		Dims:       dims,
+		DataType:   proto.Int32(int32(onnxpb.TensorProto_DOUBLE)),
+		DoubleData: vs,
+	}
+}
+
+// onnxLayer is a dense layer read from an ONNX model. weights[j][i] is the
+// weight between neuron i of the previous layer and neuron j of this layer.
+type onnxLayer struct {
+	weights                [][]float64
+	biases                 []float64
+	activationFunctionName activationfunction.Name
+}
+
+func fromOnnx(m *onnxpb.ModelProto) (Network, error) {
+	g := m.GetGraph()
+	if g == nil {
+		return nil, errors.New("model has no graph")
+	}
+
+	inits := map[string]*onnxpb.TensorProto{}
+	for _, t := range g.GetInitializer() {
+		inits[t.GetName()] = t
+	}
+
+	var inputs []string
+	for _, vi := range g.GetInput() {
+		if _, found := inits[vi.GetName()]; !found {
+			inputs = append(inputs, vi.GetName())
+		}
+	}
+	if len(inputs) != 1 {
+		return nil, fmt.Errorf("graph must have exactly one input, found %v", len(inputs))
+	}
+	if len(g.GetOutput()) != 1 {
+		return nil, fmt.Errorf("graph must have exactly one output, found %v", len(g.GetOutput()))
+	}
+
+	var ls []*onnxLayer
+	// sigmoidStage counts how much of 2*Sigmoid(x)-1 has been seen: 1 after
+	// Sigmoid, 2 after Mul.
+	sigmoidStage := 0
+	// biased tracks whether the current layer came from a MatMul that has not
+	// yet been followed by its Add.
+	biased := true
+	cur := inputs[0]
+	for _, node := range g.GetNode() {
+		op := node.GetOpType()
+		unsupported := func(reason string) error {
+			return &UnsupportedOperatorError{OpType: op, Node: node.GetName(), Reason: reason}
+		}
+
+		if node.GetDomain() != "" {
+			return nil, unsupported(fmt.Sprintf("operators from domain %q are not supported", node.GetDomain()))
+		}
+		if len(node.GetOutput()) != 1 {
+			return nil, unsupported("nodes must have exactly one output")
+		}
+
+		// other is the name of the input of node which is not cur, for
+		// operators taking two inputs.
+		var other string
+		switch {
+		case len(node.GetInput()) > 0 && node.GetInput()[0] == cur:
+			if len(node.GetInput()) > 1 {
+				other = node.GetInput()[1]
+			}
+		case op == "Mul" && len(node.GetInput()) == 2 && node.GetInput()[1] == cur:
+			other = node.GetInput()[0]
+		default:
+			return nil, unsupported(fmt.Sprintf("only a single chain of nodes is supported, but this node does not consume %q", cur))
+		}
+
+		if sigmoidStage > 0 {
+			want := map[int]string{1: "Mul", 2: "Sub"}[sigmoidStage]
+			if op != want {
+				return nil, &UnsupportedOperatorError{OpType: "Sigmoid", Reason: "the logistic sigmoid is only supported as 2*Sigmoid(x)-1, which is jnet's sigmoid"}
+			}
+		}
+
+		var last *onnxLayer
+		if len(ls) > 0 {
+			last = ls[len(ls)-1]
+		}
+		activate := func(afn activationfunction.Name) error {
+			if last == nil || last.activationFunctionName != "" {
+				return unsupported("activation functions must directly follow a Gemm, MatMul or Add")
+			}
+			last.activationFunctionName = afn
+			return nil
+		}
+
+		switch op {
+		case "Gemm":
+			l, err := onnxGemm(node, inits)
+			if err != nil {
+				return nil, errors.Wrapf(err, "node %q", node.GetName())
+			}
+			ls = append(ls, l)
+			biased = true
+
+		case "MatMul":
+			w, err := onnxMatrix(inits, other)
+			if err != nil {
+				return nil, errors.Wrapf(err, "node %q", node.GetName())
+			}
+			w = transpose(w)
+			ls = append(ls, &onnxLayer{weights: w, biases: make([]float64, len(w))})
+			biased = false
+
+		case "Add":
+			if last == nil || biased || last.activationFunctionName != "" {
+				return nil, unsupported("Add is only supported as the bias of a MatMul")
+			}
+			b, err := onnxVector(inits, other, len(last.biases))
+			if err != nil {
+				return nil, errors.Wrapf(err, "node %q", node.GetName())
+			}
+			last.biases = b
+			biased = true
+
+		case "Relu":
+			if err := activate(activationfunction.NameRelu); err != nil {
+				return nil, err
+			}
+
+		case "Tanh":
+			if err := activate(activationfunction.NameTanh); err != nil {
+				return nil, err
+			}
+
+		case "Sigmoid":
+			if err := activate(activationfunction.NameSigmoid); err != nil {
+				return nil, err
+			}
+			sigmoidStage = 1
+
+		case "Mul", "Sub":
+			want := map[string]float64{"Mul": 2, "Sub": 1}[op]
+			if sigmoidStage == 0 {
+				return nil, unsupported("Mul and Sub are only supported as part of 2*Sigmoid(x)-1")
+			}
+			if v, err := onnxScalar(inits, other); err != nil || v != want {
+				return nil, unsupported(fmt.Sprintf("expected %v of %v as part of 2*Sigmoid(x)-1", op, want))
+			}
+			sigmoidStage = (sigmoidStage + 1) % 3
+
+		case "Identity":
+
+		default:
+			return nil, unsupported("")
+		}
+
+		cur = node.GetOutput()[0]
+	}
+	if sigmoidStage > 0 {
+		return nil, &UnsupportedOperatorError{OpType: "Sigmoid", Reason: "the logistic sigmoid is only supported as 2*Sigmoid(x)-1, which is jnet's sigmoid"}
+	}
+	if g.GetOutput()[0].GetName() != cur {
+		return nil, fmt.Errorf("graph output %q is not produced by the last node", g.GetOutput()[0].GetName())
+	}
+	if len(ls) == 0 {
+		return nil, errors.New("graph has no layers")
+	}
+
+	neuronMap := []int{len(ls[0].weights[0])}
+	for li, l := range ls {
+		if len(l.weights[0]) != neuronMap[li] {
+			return nil, fmt.Errorf("layer %v expects %v inputs but the previous layer has %v neurons", li+1, len(l.weights[0]), neuronMap[li])
+		}
+		neuronMap = append(neuronMap, len(l.weights))
+	}
+
+	inputLabels := make([]string, neuronMap[0])
+	outputLabels := make([]string, neuronMap[len(neuronMap)-1])
+	for _, p := range m.GetMetadataProps() {
+		var labels *[]string
+		switch p.GetKey() {
+		case onnxInputLabelsKey:
+			labels = &inputLabels
+		case onnxOutputLabelsKey:
+			labels = &outputLabels
+		default:
+			continue
+		}
+		var ss []string
+		if err := json.Unmarshal([]byte(p.GetValue()), &ss); err != nil {
+			return nil, errors.Wrapf(err, "json unmarshalling %v", p.GetKey())
+		}
+		if len(ss) != len(*labels) {
+			return nil, fmt.Errorf("%v has %v labels, but the layer has %v neurons", p.GetKey(), len(ss), len(*labels))
+		}
+		*labels = ss
+	}
+
+	nw, err := From(Spec{
+		NeuronMap:              neuronMap,
+		InputLabels:            inputLabels,
+		OutputLabels:           outputLabels,
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "building network")
+	}
+
+	for li, l := range ls {
+		afn := l.activationFunctionName
+		if afn == "" {
+			afn = activationfunction.NameLinear
+		}
+		if err := nw[li+1].SetNeuronActivationFunctionsTo(afn); err != nil {
+			return nil, err
+		}
+		for ni, n := range nw[li+1] {
+			n.bias = l.biases[ni]
+			for ci, c := range n.Connections {
+				c.weight = l.weights[ni][ci]
+			}
+		}
+	}
+
+	return nw, nil
+}
+
+// onnxGemm reads the dense layer computed by a Gemm node, folding its alpha and
+// beta attributes into the weights and biases.
+func onnxGemm(node *onnxpb.NodeProto, inits map[string]*onnxpb.TensorProto) (*onnxLayer, error) {
+	alpha, beta := 1.0, 1.0
+	transB := false
+	for _, a := range node.GetAttribute() {
+		switch a.GetName() {
+		case "alpha":
+			alpha = float64(a.GetF())
+		case "beta":
+			beta = float64(a.GetF())
+		case "transA":
+			if a.GetI() != 0 {
+				return nil, &UnsupportedOperatorError{OpType: "Gemm", Node: node.GetName(), Reason: "transA is not supported"}
+			}
+		case "transB":
+			transB = a.GetI() != 0
+		}
+	}
+
+	if len(node.GetInput()) < 2 {
+		return nil, errors.New("gemm requires at least 2 inputs")
+	}
+	w, err := onnxMatrix(inits, node.GetInput()[1])
+	if err != nil {
+		return nil, err
+	}
+	if !transB {
+		w = transpose(w)
+	}
+	for _, row := range w {
+		for i := range row {
+			row[i] *= alpha
+		}
+	}
+
+	b := make([]float64, len(w))
+	if len(node.GetInput()) > 2 && node.GetInput()[2] != "" {
+		b, err = onnxVector(inits, node.GetInput()[2], len(w))
+		if err != nil {
+			return nil, err
+		}
+		for i := range b {
+			b[i] *= beta
+		}
+	}
+
+	return &onnxLayer{weights: w, biases: b}, nil
+}
+
+// onnxMatrix returns the 2 dimensional initializer named name as rows.
+func onnxMatrix(inits map[string]*onnxpb.TensorProto, name string) ([][]float64, error) {
+	t, found := inits[name]
+	if !found {
+		return nil, fmt.Errorf("weights %q must be an initializer", name)
+	}
+	if len(t.GetDims()) != 2 || t.GetDims()[0] < 1 || t.GetDims()[1] < 1 {
+		return nil, fmt.Errorf("weights %q must be a non-empty matrix, got dims %v", name, t.GetDims())
+	}
+	vs, err := onnxTensorValues(t)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, cols := int(t.GetDims()[0]), int(t.GetDims()[1])
+	m := make([][]float64, rows)
+	for r := range m {
+		m[r] = vs[r*cols : (r+1)*cols]
+	}
+	return m, nil
+}
+
+// onnxVector returns the initializer named name as a vector of length size.
+// Initializers holding a single value are broadcast to size.
+func onnxVector(inits map[string]*onnxpb.TensorProto, name string, size int) ([]float64, error) {
+	t, found := inits[name]
+	if !found {
+		return nil, fmt.Errorf("biases %q must be an initializer", name)
+	}
+	vs, err := onnxTensorValues(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(vs) {
+	case size:
+		return vs, nil
+	case 1:
+		b := make([]float64, size)
+		for i := range b {
+			b[i] = vs[0]
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("biases %q have %v values, expected %v", name, len(vs), size)
+	}
+}
+
+// onnxScalar returns the value of the single valued initializer named name.
+func onnxScalar(inits map[string]*onnxpb.TensorProto, name string) (float64, error) {
+	vs, err := onnxVector(inits, name, 1)
+	if err != nil {
+		return 0, err
+	}
+	return vs[0], nil
+}
+
+// onnxTensorValues returns the values of a float or double tensor, whether
+// they are stored in its typed fields or its raw little endian bytes.
+func onnxTensorValues(t *onnxpb.TensorProto) ([]float64, error) {
+	size := 1
+	for _, d := range t.GetDims() {
+		size *= int(d)
+	}
+
+	var vs []float64
+	raw := t.GetRawData()
+	switch onnxpb.TensorProto_DataType(t.GetDataType()) {
+	case onnxpb.TensorProto_DOUBLE:
+		if raw != nil {
+			if len(raw) != size*8 {
+				return nil, fmt.Errorf("tensor %q has %v raw bytes, expected %v", t.GetName(), len(raw), size*8)
+			}
+			for i := 0; i < size; i++ {
+				vs = append(vs, math.Float64frombits(binary.LittleEndian.Uint64(raw[i*8:])))
+			}
+		} else {
+			vs = append(vs, t.GetDoubleData()...)
+		}
+	case onnxpb.TensorProto_FLOAT:
+		if raw != nil {
+			if len(raw) != size*4 {
+				return nil, fmt.Errorf("tensor %q has %v raw bytes, expected %v", t.GetName(), len(raw), size*4)
+			}
+			for i := 0; i < size; i++ {
+				vs = append(vs, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))))
+			}
+		} else {
+			for _, v := range t.GetFloatData() {
+				vs = append(vs, float64(v))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("tensor %q has unsupported data type %v, only FLOAT and DOUBLE are supported", t.GetName(), onnxpb.TensorProto_DataType(t.GetDataType()))
+	}
+
+	if len(vs) != size {
+		return nil, fmt.Errorf("tensor %q has %v values, expected %v", t.GetName(), len(vs), size)
+	}
+	return vs, nil
+}
+
+// transpose returns the transpose of the matrix m.
+func transpose(m [][]float64) [][]float64 {
+	if len(m) == 0 {
+		return nil
+	}
+	t := make([][]float64, len(m[0]))
+	for i := range t {
+		t[i] = make([]float64, len(m))
+		for j := range m {
+			t[i][j] = m[j][i]
+		}
+	}
+	return t
+}
@@ -0,0 +1,293 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network/onnxpb"
+)
+
+// evalOnnx is a minimal reference interpreter for the operators used by dense
+// networks, evaluating m on a single input vector. It exists so that exported
+// models can be checked without an external ONNX runtime.
+func evalOnnx(t *testing.T, m *onnxpb.ModelProto, input []float64) []float64 {
+	t.Helper()
+
+	g := m.GetGraph()
+	type tensor struct {
+		dims []int64
+		vs   []float64
+	}
+	values := map[string]tensor{}
+	for _, it := range g.GetInitializer() {
+		vs, err := onnxTensorValues(it)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values[it.GetName()] = tensor{it.GetDims(), vs}
+	}
+	values[g.GetInput()[0].GetName()] = tensor{[]int64{1, int64(len(input))}, input}
+
+	elementwise := func(a, b tensor, fn func(x, y float64) float64) tensor {
+		out := tensor{a.dims, make([]float64, len(a.vs))}
+		for i := range a.vs {
+			y := b.vs[0]
+			if len(b.vs) == len(a.vs) {
+				y = b.vs[i]
+			}
+			out.vs[i] = fn(a.vs[i], y)
+		}
+		return out
+	}
+	matmul := func(x tensor, w []float64, rows, cols int, transposed bool) tensor {
+		outs := cols
+		if transposed {
+			outs = rows
+		}
+		out := tensor{[]int64{1, int64(outs)}, make([]float64, outs)}
+		for o := 0; o < outs; o++ {
+			for i := range x.vs {
+				if transposed {
+					out.vs[o] += x.vs[i] * w[o*cols+i]
+				} else {
+					out.vs[o] += x.vs[i] * w[i*cols+o]
+				}
+			}
+		}
+		return out
+	}
+
+	for _, n := range g.GetNode() {
+		in := func(i int) tensor {
+			v, found := values[n.GetInput()[i]]
+			if !found {
+				t.Fatalf("node %v: unknown input %v", n.GetName(), n.GetInput()[i])
+			}
+			return v
+		}
+		attrs := map[string]*onnxpb.AttributeProto{}
+		for _, a := range n.GetAttribute() {
+			attrs[a.GetName()] = a
+		}
+
+		var out tensor
+		switch n.GetOpType() {
+		case "Gemm":
+			w := in(1)
+			out = matmul(in(0), w.vs, int(w.dims[0]), int(w.dims[1]), attrs["transB"].GetI() == 1)
+			if len(n.GetInput()) > 2 {
+				out = elementwise(out, in(2), func(x, y float64) float64 { return x + y })
+			}
+		case "MatMul":
+			w := in(1)
+			out = matmul(in(0), w.vs, int(w.dims[0]), int(w.dims[1]), false)
+		case "Add":
+			out = elementwise(in(0), in(1), func(x, y float64) float64 { return x + y })
+		case "Mul":
+			out = elementwise(in(0), in(1), func(x, y float64) float64 { return x * y })
+		case "Sub":
+			out = elementwise(in(0), in(1), func(x, y float64) float64 { return x - y })
+		case "Relu":
+			out = elementwise(in(0), in(0), func(x, _ float64) float64 { return math.Max(x, 0) })
+		case "Tanh":
+			out = elementwise(in(0), in(0), func(x, _ float64) float64 { return math.Tanh(x) })
+		case "Sigmoid":
+			out = elementwise(in(0), in(0), func(x, _ float64) float64 { return 1 / (1 + math.Exp(-x)) })
+		case "Identity":
+			out = in(0)
+		default:
+			t.Fatalf("evalOnnx does not support %v", n.GetOpType())
+		}
+		values[n.GetOutput()[0]] = out
+	}
+
+	return values[g.GetOutput()[0].GetName()].vs
+}
+
+func Test_onnx_ExportMatchesNetwork(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 5, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetNeuronActivationFunctionsTo(activationfunction.NameRelu)
+	nw[2].MustSetNeuronActivationFunctionsTo(activationfunction.NameTanh)
+
+	m := &onnxpb.ModelProto{}
+	if err := proto.Unmarshal(NewOnnxTranslator().MustSerialize(nw), m); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range [][]float64{{0, 0, 0}, {1, -1, 0.5}, {-3, 2, 7}} {
+		got := evalOnnx(t, m, input)
+		want := nw.MustPredictVector(input)
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-12 {
+				t.Fatalf("input %v: onnx output %v does not match network output %v", input, got, want)
+			}
+		}
+	}
+}
+
+func Test_onnx_SerializeAndDeserializeAreInverses(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 5, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetNeuronActivationFunctionsTo(activationfunction.NameRelu)
+	nw[2].MustSetNeuronActivationFunctionsTo(activationfunction.NameTanh)
+
+	ot := NewOnnxTranslator(WithCompression())
+	s := ot.MustSerialize(nw)
+	nw2 := ot.MustDeserialize(s)
+
+	if s2 := ot.MustSerialize(nw2); string(s) != string(s2) {
+		t.Fatalf("original onnx encoding and deserialized network onnx encoding do not equal each other")
+	}
+	if !reflect.DeepEqual(nw2.Spec().InputLabels, nw.Spec().InputLabels) || !reflect.DeepEqual(nw2.Spec().OutputLabels, nw.Spec().OutputLabels) {
+		t.Fatalf("labels were not preserved, got %+v", nw2.Spec())
+	}
+	for _, input := range [][]float64{{0, 0, 0}, {1, -1, 0.5}, {-3, 2, 7}} {
+		if got, want := nw2.MustPredictVector(input), nw.MustPredictVector(input); !reflect.DeepEqual(got, want) {
+			t.Fatalf("input %v: expected predictions %v, got %v", input, want, got)
+		}
+	}
+}
+
+func Test_onnx_ExportErrors(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	nw[1][0].MustSetActivationFunction(activationfunction.NameTanh)
+	if _, err := NewOnnxTranslator().Serialize(nw); err == nil {
+		t.Fatalf("expected an error for mixed activation functions in a layer")
+	}
+	nw[1][0].MustSetActivationFunction(activationfunction.NameSigmoid)
+
+	nw[2].MustSetNeuronActivationFunctionsTo(activationfunction.NameNoop)
+	if _, err := NewOnnxTranslator().Serialize(nw); err == nil {
+		t.Fatalf("expected an error for the noop activation function")
+	}
+}
+
+// float32RawData returns vs as the raw little endian bytes of a FLOAT tensor.
+func float32RawData(vs ...float32) []byte {
+	bs := make([]byte, 4*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint32(bs[i*4:], math.Float32bits(v))
+	}
+	return bs
+}
+
+// newMLPModel builds an ONNX model in the style exported by other frameworks:
+// a float MatMul and Add, followed by Relu, followed by a Gemm with scaled
+// alpha and beta and no transB.
+func newMLPModel(nodes ...*onnxpb.NodeProto) *onnxpb.ModelProto {
+	floatType := &onnxpb.TypeProto{TensorType: &onnxpb.TypeProto_Tensor{ElemType: proto.Int32(int32(onnxpb.TensorProto_FLOAT))}}
+	if nodes == nil {
+		nodes = []*onnxpb.NodeProto{
+			{OpType: proto.String("MatMul"), Name: proto.String("fc1"), Input: []string{"x", "w1"}, Output: []string{"h1"}},
+			{OpType: proto.String("Add"), Name: proto.String("fc1.bias"), Input: []string{"h1", "b1"}, Output: []string{"h2"}},
+			{OpType: proto.String("Relu"), Name: proto.String("relu"), Input: []string{"h2"}, Output: []string{"h3"}},
+			{OpType: proto.String("Gemm"), Name: proto.String("fc2"), Input: []string{"h3", "w2", "b2"}, Output: []string{"y"}, Attribute: []*onnxpb.AttributeProto{
+				{Name: proto.String("alpha"), Type: onnxpb.AttributeProto_FLOAT.Enum(), F: proto.Float32(0.5)},
+				{Name: proto.String("beta"), Type: onnxpb.AttributeProto_FLOAT.Enum(), F: proto.Float32(2)},
+			}},
+		}
+	}
+	return &onnxpb.ModelProto{
+		IrVersion: proto.Int64(8),
+		Graph: &onnxpb.GraphProto{
+			Node: nodes,
+			Initializer: []*onnxpb.TensorProto{
+				// w1 is [in=2, out=3].
+				{Name: proto.String("w1"), Dims: []int64{2, 3}, DataType: proto.Int32(int32(onnxpb.TensorProto_FLOAT)), RawData: float32RawData(1, 2, 3, 4, 5, 6)},
+				{Name: proto.String("b1"), Dims: []int64{3}, DataType: proto.Int32(int32(onnxpb.TensorProto_FLOAT)), FloatData: []float32{0.5, -10, 0}},
+				// w2 is [in=3, out=1].
+				{Name: proto.String("w2"), Dims: []int64{3, 1}, DataType: proto.Int32(int32(onnxpb.TensorProto_DOUBLE)), DoubleData: []float64{1, -1, 2}},
+				{Name: proto.String("b2"), Dims: []int64{1}, DataType: proto.Int32(int32(onnxpb.TensorProto_DOUBLE)), DoubleData: []float64{0.25}},
+			},
+			Input:  []*onnxpb.ValueInfoProto{{Name: proto.String("x"), Type: floatType}},
+			Output: []*onnxpb.ValueInfoProto{{Name: proto.String("y"), Type: floatType}},
+		},
+	}
+}
+
+func Test_onnx_ImportsMLP(t *testing.T) {
+	bs, err := proto.Marshal(newMLPModel())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nw := NewOnnxTranslator().MustDeserialize(bs)
+
+	if got := nw.Spec().NeuronMap; !reflect.DeepEqual(got, []int{2, 3, 1}) {
+		t.Fatalf("expected neuron map [2 3 1], got %v", got)
+	}
+	if got := nw[1][0].ActivationFunctionName; got != activationfunction.NameRelu {
+		t.Fatalf("expected relu hidden layer, got %v", got)
+	}
+	if got := nw[2][0].ActivationFunctionName; got != activationfunction.NameLinear {
+		t.Fatalf("expected linear output layer, got %v", got)
+	}
+
+	for _, input := range [][]float64{{1, 1}, {0.5, -2}, {3, 0}} {
+		h := []float64{
+			math.Max(input[0]*1+input[1]*4+0.5, 0),
+			math.Max(input[0]*2+input[1]*5-10, 0),
+			math.Max(input[0]*3+input[1]*6+0, 0),
+		}
+		want := []float64{0.5*(h[0]*1-h[1]*1+h[2]*2) + 2*0.25}
+
+		if got := nw.MustPredictVector(input); math.Abs(got[0]-want[0]) > 1e-12 {
+			t.Fatalf("input %v: expected %v, got %v", input, want, got)
+		}
+	}
+}
+
+func Test_onnx_UnsupportedOperators(t *testing.T) {
+	node := func(op string, in ...string) *onnxpb.NodeProto {
+		return &onnxpb.NodeProto{OpType: proto.String(op), Name: proto.String(op), Input: in, Output: []string{"y"}}
+	}
+	tcs := map[string][]*onnxpb.NodeProto{
+		"softmax": {
+			{OpType: proto.String("Gemm"), Input: []string{"x", "w1"}, Output: []string{"h"}, Attribute: []*onnxpb.AttributeProto{{Name: proto.String("transB"), I: proto.Int64(0)}}},
+			node("Softmax", "h"),
+		},
+		"plain sigmoid": {
+			{OpType: proto.String("Gemm"), Input: []string{"x", "w1"}, Output: []string{"h"}},
+			node("Sigmoid", "h"),
+		},
+		"transA": {
+			{OpType: proto.String("Gemm"), Name: proto.String("gemm"), Input: []string{"x", "w1"}, Output: []string{"y"}, Attribute: []*onnxpb.AttributeProto{{Name: proto.String("transA"), I: proto.Int64(1)}}},
+		},
+		"custom domain": {
+			{OpType: proto.String("Gemm"), Domain: proto.String("com.example"), Input: []string{"x", "w1"}, Output: []string{"y"}},
+		},
+	}
+
+	for name, nodes := range tcs {
+		bs, err := proto.Marshal(newMLPModel(nodes...))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var uoe *UnsupportedOperatorError
+		if _, err := NewOnnxTranslator().Deserialize(bs); !errors.As(err, &uoe) {
+			t.Errorf("%v: expected an UnsupportedOperatorError, got %v", name, err)
+		}
+	}
+}
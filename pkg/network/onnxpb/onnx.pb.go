@@ -0,0 +1,1613 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: pkg/network/onnxpb/onnx.proto
+
+package onnxpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AttributeProto_AttributeType int32
+
+const (
+	AttributeProto_UNDEFINED      AttributeProto_AttributeType = 0
+	AttributeProto_FLOAT          AttributeProto_AttributeType = 1
+	AttributeProto_INT            AttributeProto_AttributeType = 2
+	AttributeProto_STRING         AttributeProto_AttributeType = 3
+	AttributeProto_TENSOR         AttributeProto_AttributeType = 4
+	AttributeProto_GRAPH          AttributeProto_AttributeType = 5
+	AttributeProto_SPARSE_TENSOR  AttributeProto_AttributeType = 11
+	AttributeProto_TYPE_PROTO     AttributeProto_AttributeType = 13
+	AttributeProto_FLOATS         AttributeProto_AttributeType = 6
+	AttributeProto_INTS           AttributeProto_AttributeType = 7
+	AttributeProto_STRINGS        AttributeProto_AttributeType = 8
+	AttributeProto_TENSORS        AttributeProto_AttributeType = 9
+	AttributeProto_GRAPHS         AttributeProto_AttributeType = 10
+	AttributeProto_SPARSE_TENSORS AttributeProto_AttributeType = 12
+	AttributeProto_TYPE_PROTOS    AttributeProto_AttributeType = 14
+)
+
+// Enum value maps for AttributeProto_AttributeType.
+var (
+	AttributeProto_AttributeType_name = map[int32]string{
+		0:  "UNDEFINED",
+		1:  "FLOAT",
+		2:  "INT",
+		3:  "STRING",
+		4:  "TENSOR",
+		5:  "GRAPH",
+		11: "SPARSE_TENSOR",
+		13: "TYPE_PROTO",
+		6:  "FLOATS",
+		7:  "INTS",
+		8:  "STRINGS",
+		9:  "TENSORS",
+		10: "GRAPHS",
+		12: "SPARSE_TENSORS",
+		14: "TYPE_PROTOS",
+	}
+	AttributeProto_AttributeType_value = map[string]int32{
+		"UNDEFINED":      0,
+		"FLOAT":          1,
+		"INT":            2,
+		"STRING":         3,
+		"TENSOR":         4,
+		"GRAPH":          5,
+		"SPARSE_TENSOR":  11,
+		"TYPE_PROTO":     13,
+		"FLOATS":         6,
+		"INTS":           7,
+		"STRINGS":        8,
+		"TENSORS":        9,
+		"GRAPHS":         10,
+		"SPARSE_TENSORS": 12,
+		"TYPE_PROTOS":    14,
+	}
+)
+
+func (x AttributeProto_AttributeType) Enum() *AttributeProto_AttributeType {
+	p := new(AttributeProto_AttributeType)
+	*p = x
+	return p
+}
+
+func (x AttributeProto_AttributeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttributeProto_AttributeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_network_onnxpb_onnx_proto_enumTypes[0].Descriptor()
+}
+
+func (AttributeProto_AttributeType) Type() protoreflect.EnumType {
+	return &file_pkg_network_onnxpb_onnx_proto_enumTypes[0]
+}
+
+func (x AttributeProto_AttributeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *AttributeProto_AttributeType) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = AttributeProto_AttributeType(num)
+	return nil
+}
+
+// Deprecated: Use AttributeProto_AttributeType.Descriptor instead.
+func (AttributeProto_AttributeType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type TensorProto_DataType int32
+
+const (
+	TensorProto_UNDEFINED  TensorProto_DataType = 0
+	TensorProto_FLOAT      TensorProto_DataType = 1
+	TensorProto_UINT8      TensorProto_DataType = 2
+	TensorProto_INT8       TensorProto_DataType = 3
+	TensorProto_UINT16     TensorProto_DataType = 4
+	TensorProto_INT16      TensorProto_DataType = 5
+	TensorProto_INT32      TensorProto_DataType = 6
+	TensorProto_INT64      TensorProto_DataType = 7
+	TensorProto_STRING     TensorProto_DataType = 8
+	TensorProto_BOOL       TensorProto_DataType = 9
+	TensorProto_FLOAT16    TensorProto_DataType = 10
+	TensorProto_DOUBLE     TensorProto_DataType = 11
+	TensorProto_UINT32     TensorProto_DataType = 12
+	TensorProto_UINT64     TensorProto_DataType = 13
+	TensorProto_COMPLEX64  TensorProto_DataType = 14
+	TensorProto_COMPLEX128 TensorProto_DataType = 15
+	TensorProto_BFLOAT16   TensorProto_DataType = 16
+)
+
+// Enum value maps for TensorProto_DataType.
+var (
+	TensorProto_DataType_name = map[int32]string{
+		0:  "UNDEFINED",
+		1:  "FLOAT",
+		2:  "UINT8",
+		3:  "INT8",
+		4:  "UINT16",
+		5:  "INT16",
+		6:  "INT32",
+		7:  "INT64",
+		8:  "STRING",
+		9:  "BOOL",
+		10: "FLOAT16",
+		11: "DOUBLE",
+		12: "UINT32",
+		13: "UINT64",
+		14: "COMPLEX64",
+		15: "COMPLEX128",
+		16: "BFLOAT16",
+	}
+	TensorProto_DataType_value = map[string]int32{
+		"UNDEFINED":  0,
+		"FLOAT":      1,
+		"UINT8":      2,
+		"INT8":       3,
+		"UINT16":     4,
+		"INT16":      5,
+		"INT32":      6,
+		"INT64":      7,
+		"STRING":     8,
+		"BOOL":       9,
+		"FLOAT16":    10,
+		"DOUBLE":     11,
+		"UINT32":     12,
+		"UINT64":     13,
+		"COMPLEX64":  14,
+		"COMPLEX128": 15,
+		"BFLOAT16":   16,
+	}
+)
+
+func (x TensorProto_DataType) Enum() *TensorProto_DataType {
+	p := new(TensorProto_DataType)
+	*p = x
+	return p
+}
+
+func (x TensorProto_DataType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TensorProto_DataType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_network_onnxpb_onnx_proto_enumTypes[1].Descriptor()
+}
+
+func (TensorProto_DataType) Type() protoreflect.EnumType {
+	return &file_pkg_network_onnxpb_onnx_proto_enumTypes[1]
+}
+
+func (x TensorProto_DataType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *TensorProto_DataType) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = TensorProto_DataType(num)
+	return nil
+}
+
+// Deprecated: Use TensorProto_DataType.Descriptor instead.
+func (TensorProto_DataType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{9, 0}
+}
+
+// ModelProto is the top-level container of an ONNX model.
+type ModelProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IrVersion       *int64                    `protobuf:"varint,1,opt,name=ir_version,json=irVersion" json:"ir_version,omitempty"`
+	OpsetImport     []*OperatorSetIdProto     `protobuf:"bytes,8,rep,name=opset_import,json=opsetImport" json:"opset_import,omitempty"`
+	ProducerName    *string                   `protobuf:"bytes,2,opt,name=producer_name,json=producerName" json:"producer_name,omitempty"`
+	ProducerVersion *string                   `protobuf:"bytes,3,opt,name=producer_version,json=producerVersion" json:"producer_version,omitempty"`
+	Domain          *string                   `protobuf:"bytes,4,opt,name=domain" json:"domain,omitempty"`
+	ModelVersion    *int64                    `protobuf:"varint,5,opt,name=model_version,json=modelVersion" json:"model_version,omitempty"`
+	DocString       *string                   `protobuf:"bytes,6,opt,name=doc_string,json=docString" json:"doc_string,omitempty"`
+	Graph           *GraphProto               `protobuf:"bytes,7,opt,name=graph" json:"graph,omitempty"`
+	MetadataProps   []*StringStringEntryProto `protobuf:"bytes,14,rep,name=metadata_props,json=metadataProps" json:"metadata_props,omitempty"`
+}
+
+func (x *ModelProto) Reset() {
+	*x = ModelProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModelProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelProto) ProtoMessage() {}
+
+func (x *ModelProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelProto.ProtoReflect.Descriptor instead.
+func (*ModelProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ModelProto) GetIrVersion() int64 {
+	if x != nil && x.IrVersion != nil {
+		return *x.IrVersion
+	}
+	return 0
+}
+
+func (x *ModelProto) GetOpsetImport() []*OperatorSetIdProto {
+	if x != nil {
+		return x.OpsetImport
+	}
+	return nil
+}
+
+func (x *ModelProto) GetProducerName() string {
+	if x != nil && x.ProducerName != nil {
+		return *x.ProducerName
+	}
+	return ""
+}
+
+func (x *ModelProto) GetProducerVersion() string {
+	if x != nil && x.ProducerVersion != nil {
+		return *x.ProducerVersion
+	}
+	return ""
+}
+
+func (x *ModelProto) GetDomain() string {
+	if x != nil && x.Domain != nil {
+		return *x.Domain
+	}
+	return ""
+}
+
+func (x *ModelProto) GetModelVersion() int64 {
+	if x != nil && x.ModelVersion != nil {
+		return *x.ModelVersion
+	}
+	return 0
+}
+
+func (x *ModelProto) GetDocString() string {
+	if x != nil && x.DocString != nil {
+		return *x.DocString
+	}
+	return ""
+}
+
+func (x *ModelProto) GetGraph() *GraphProto {
+	if x != nil {
+		return x.Graph
+	}
+	return nil
+}
+
+func (x *ModelProto) GetMetadataProps() []*StringStringEntryProto {
+	if x != nil {
+		return x.MetadataProps
+	}
+	return nil
+}
+
+type OperatorSetIdProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Domain  *string `protobuf:"bytes,1,opt,name=domain" json:"domain,omitempty"`
+	Version *int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (x *OperatorSetIdProto) Reset() {
+	*x = OperatorSetIdProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OperatorSetIdProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperatorSetIdProto) ProtoMessage() {}
+
+func (x *OperatorSetIdProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperatorSetIdProto.ProtoReflect.Descriptor instead.
+func (*OperatorSetIdProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OperatorSetIdProto) GetDomain() string {
+	if x != nil && x.Domain != nil {
+		return *x.Domain
+	}
+	return ""
+}
+
+func (x *OperatorSetIdProto) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type StringStringEntryProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   *string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value *string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (x *StringStringEntryProto) Reset() {
+	*x = StringStringEntryProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StringStringEntryProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringStringEntryProto) ProtoMessage() {}
+
+func (x *StringStringEntryProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringStringEntryProto.ProtoReflect.Descriptor instead.
+func (*StringStringEntryProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StringStringEntryProto) GetKey() string {
+	if x != nil && x.Key != nil {
+		return *x.Key
+	}
+	return ""
+}
+
+func (x *StringStringEntryProto) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+// GraphProto is a topologically sorted list of nodes forming a computation
+// graph.
+type GraphProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node        []*NodeProto      `protobuf:"bytes,1,rep,name=node" json:"node,omitempty"`
+	Name        *string           `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Initializer []*TensorProto    `protobuf:"bytes,5,rep,name=initializer" json:"initializer,omitempty"`
+	DocString   *string           `protobuf:"bytes,10,opt,name=doc_string,json=docString" json:"doc_string,omitempty"`
+	Input       []*ValueInfoProto `protobuf:"bytes,11,rep,name=input" json:"input,omitempty"`
+	Output      []*ValueInfoProto `protobuf:"bytes,12,rep,name=output" json:"output,omitempty"`
+	ValueInfo   []*ValueInfoProto `protobuf:"bytes,13,rep,name=value_info,json=valueInfo" json:"value_info,omitempty"`
+}
+
+func (x *GraphProto) Reset() {
+	*x = GraphProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphProto) ProtoMessage() {}
+
+func (x *GraphProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphProto.ProtoReflect.Descriptor instead.
+func (*GraphProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GraphProto) GetNode() []*NodeProto {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *GraphProto) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *GraphProto) GetInitializer() []*TensorProto {
+	if x != nil {
+		return x.Initializer
+	}
+	return nil
+}
+
+func (x *GraphProto) GetDocString() string {
+	if x != nil && x.DocString != nil {
+		return *x.DocString
+	}
+	return ""
+}
+
+func (x *GraphProto) GetInput() []*ValueInfoProto {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *GraphProto) GetOutput() []*ValueInfoProto {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *GraphProto) GetValueInfo() []*ValueInfoProto {
+	if x != nil {
+		return x.ValueInfo
+	}
+	return nil
+}
+
+// NodeProto is a single call to an operator.
+type NodeProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Input     []string          `protobuf:"bytes,1,rep,name=input" json:"input,omitempty"`
+	Output    []string          `protobuf:"bytes,2,rep,name=output" json:"output,omitempty"`
+	Name      *string           `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	OpType    *string           `protobuf:"bytes,4,opt,name=op_type,json=opType" json:"op_type,omitempty"`
+	Domain    *string           `protobuf:"bytes,7,opt,name=domain" json:"domain,omitempty"`
+	Attribute []*AttributeProto `protobuf:"bytes,5,rep,name=attribute" json:"attribute,omitempty"`
+	DocString *string           `protobuf:"bytes,6,opt,name=doc_string,json=docString" json:"doc_string,omitempty"`
+}
+
+func (x *NodeProto) Reset() {
+	*x = NodeProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeProto) ProtoMessage() {}
+
+func (x *NodeProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeProto.ProtoReflect.Descriptor instead.
+func (*NodeProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NodeProto) GetInput() []string {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *NodeProto) GetOutput() []string {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *NodeProto) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *NodeProto) GetOpType() string {
+	if x != nil && x.OpType != nil {
+		return *x.OpType
+	}
+	return ""
+}
+
+func (x *NodeProto) GetDomain() string {
+	if x != nil && x.Domain != nil {
+		return *x.Domain
+	}
+	return ""
+}
+
+func (x *NodeProto) GetAttribute() []*AttributeProto {
+	if x != nil {
+		return x.Attribute
+	}
+	return nil
+}
+
+func (x *NodeProto) GetDocString() string {
+	if x != nil && x.DocString != nil {
+		return *x.DocString
+	}
+	return ""
+}
+
+// AttributeProto is a named attribute of a node.
+type AttributeProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        *string                       `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	RefAttrName *string                       `protobuf:"bytes,21,opt,name=ref_attr_name,json=refAttrName" json:"ref_attr_name,omitempty"`
+	DocString   *string                       `protobuf:"bytes,13,opt,name=doc_string,json=docString" json:"doc_string,omitempty"`
+	Type        *AttributeProto_AttributeType `protobuf:"varint,20,opt,name=type,enum=jnet.onnx.AttributeProto_AttributeType" json:"type,omitempty"`
+	F           *float32                      `protobuf:"fixed32,2,opt,name=f" json:"f,omitempty"`
+	I           *int64                        `protobuf:"varint,3,opt,name=i" json:"i,omitempty"`
+	S           []byte                        `protobuf:"bytes,4,opt,name=s" json:"s,omitempty"`
+	T           *TensorProto                  `protobuf:"bytes,5,opt,name=t" json:"t,omitempty"`
+	G           *GraphProto                   `protobuf:"bytes,6,opt,name=g" json:"g,omitempty"`
+	Floats      []float32                     `protobuf:"fixed32,7,rep,name=floats" json:"floats,omitempty"`
+	Ints        []int64                       `protobuf:"varint,8,rep,name=ints" json:"ints,omitempty"`
+	Strings     [][]byte                      `protobuf:"bytes,9,rep,name=strings" json:"strings,omitempty"`
+	Tensors     []*TensorProto                `protobuf:"bytes,10,rep,name=tensors" json:"tensors,omitempty"`
+	Graphs      []*GraphProto                 `protobuf:"bytes,11,rep,name=graphs" json:"graphs,omitempty"`
+}
+
+func (x *AttributeProto) Reset() {
+	*x = AttributeProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttributeProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttributeProto) ProtoMessage() {}
+
+func (x *AttributeProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttributeProto.ProtoReflect.Descriptor instead.
+func (*AttributeProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AttributeProto) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AttributeProto) GetRefAttrName() string {
+	if x != nil && x.RefAttrName != nil {
+		return *x.RefAttrName
+	}
+	return ""
+}
+
+func (x *AttributeProto) GetDocString() string {
+	if x != nil && x.DocString != nil {
+		return *x.DocString
+	}
+	return ""
+}
+
+func (x *AttributeProto) GetType() AttributeProto_AttributeType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return AttributeProto_UNDEFINED
+}
+
+func (x *AttributeProto) GetF() float32 {
+	if x != nil && x.F != nil {
+		return *x.F
+	}
+	return 0
+}
+
+func (x *AttributeProto) GetI() int64 {
+	if x != nil && x.I != nil {
+		return *x.I
+	}
+	return 0
+}
+
+func (x *AttributeProto) GetS() []byte {
+	if x != nil {
+		return x.S
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetT() *TensorProto {
+	if x != nil {
+		return x.T
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetG() *GraphProto {
+	if x != nil {
+		return x.G
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetFloats() []float32 {
+	if x != nil {
+		return x.Floats
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetInts() []int64 {
+	if x != nil {
+		return x.Ints
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetStrings() [][]byte {
+	if x != nil {
+		return x.Strings
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetTensors() []*TensorProto {
+	if x != nil {
+		return x.Tensors
+	}
+	return nil
+}
+
+func (x *AttributeProto) GetGraphs() []*GraphProto {
+	if x != nil {
+		return x.Graphs
+	}
+	return nil
+}
+
+// ValueInfoProto describes a named value flowing through a graph.
+type ValueInfoProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      *string    `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type      *TypeProto `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	DocString *string    `protobuf:"bytes,3,opt,name=doc_string,json=docString" json:"doc_string,omitempty"`
+}
+
+func (x *ValueInfoProto) Reset() {
+	*x = ValueInfoProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValueInfoProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueInfoProto) ProtoMessage() {}
+
+func (x *ValueInfoProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueInfoProto.ProtoReflect.Descriptor instead.
+func (*ValueInfoProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ValueInfoProto) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ValueInfoProto) GetType() *TypeProto {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *ValueInfoProto) GetDocString() string {
+	if x != nil && x.DocString != nil {
+		return *x.DocString
+	}
+	return ""
+}
+
+type TypeProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TensorType *TypeProto_Tensor `protobuf:"bytes,1,opt,name=tensor_type,json=tensorType" json:"tensor_type,omitempty"`
+	Denotation *string           `protobuf:"bytes,6,opt,name=denotation" json:"denotation,omitempty"`
+}
+
+func (x *TypeProto) Reset() {
+	*x = TypeProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeProto) ProtoMessage() {}
+
+func (x *TypeProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeProto.ProtoReflect.Descriptor instead.
+func (*TypeProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TypeProto) GetTensorType() *TypeProto_Tensor {
+	if x != nil {
+		return x.TensorType
+	}
+	return nil
+}
+
+func (x *TypeProto) GetDenotation() string {
+	if x != nil && x.Denotation != nil {
+		return *x.Denotation
+	}
+	return ""
+}
+
+type TensorShapeProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dim []*TensorShapeProto_Dimension `protobuf:"bytes,1,rep,name=dim" json:"dim,omitempty"`
+}
+
+func (x *TensorShapeProto) Reset() {
+	*x = TensorShapeProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TensorShapeProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TensorShapeProto) ProtoMessage() {}
+
+func (x *TensorShapeProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TensorShapeProto.ProtoReflect.Descriptor instead.
+func (*TensorShapeProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TensorShapeProto) GetDim() []*TensorShapeProto_Dimension {
+	if x != nil {
+		return x.Dim
+	}
+	return nil
+}
+
+// TensorProto is a serialized tensor value.
+type TensorProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dims       []int64   `protobuf:"varint,1,rep,name=dims" json:"dims,omitempty"`
+	DataType   *int32    `protobuf:"varint,2,opt,name=data_type,json=dataType" json:"data_type,omitempty"`
+	FloatData  []float32 `protobuf:"fixed32,4,rep,packed,name=float_data,json=floatData" json:"float_data,omitempty"`
+	Int32Data  []int32   `protobuf:"varint,5,rep,packed,name=int32_data,json=int32Data" json:"int32_data,omitempty"`
+	StringData [][]byte  `protobuf:"bytes,6,rep,name=string_data,json=stringData" json:"string_data,omitempty"`
+	Int64Data  []int64   `protobuf:"varint,7,rep,packed,name=int64_data,json=int64Data" json:"int64_data,omitempty"`
+	Name       *string   `protobuf:"bytes,8,opt,name=name" json:"name,omitempty"`
+	DocString  *string   `protobuf:"bytes,12,opt,name=doc_string,json=docString" json:"doc_string,omitempty"`
+	RawData    []byte    `protobuf:"bytes,9,opt,name=raw_data,json=rawData" json:"raw_data,omitempty"`
+	DoubleData []float64 `protobuf:"fixed64,10,rep,packed,name=double_data,json=doubleData" json:"double_data,omitempty"`
+	Uint64Data []uint64  `protobuf:"varint,11,rep,packed,name=uint64_data,json=uint64Data" json:"uint64_data,omitempty"`
+}
+
+func (x *TensorProto) Reset() {
+	*x = TensorProto{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TensorProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TensorProto) ProtoMessage() {}
+
+func (x *TensorProto) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TensorProto.ProtoReflect.Descriptor instead.
+func (*TensorProto) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TensorProto) GetDims() []int64 {
+	if x != nil {
+		return x.Dims
+	}
+	return nil
+}
+
+func (x *TensorProto) GetDataType() int32 {
+	if x != nil && x.DataType != nil {
+		return *x.DataType
+	}
+	return 0
+}
+
+func (x *TensorProto) GetFloatData() []float32 {
+	if x != nil {
+		return x.FloatData
+	}
+	return nil
+}
+
+func (x *TensorProto) GetInt32Data() []int32 {
+	if x != nil {
+		return x.Int32Data
+	}
+	return nil
+}
+
+func (x *TensorProto) GetStringData() [][]byte {
+	if x != nil {
+		return x.StringData
+	}
+	return nil
+}
+
+func (x *TensorProto) GetInt64Data() []int64 {
+	if x != nil {
+		return x.Int64Data
+	}
+	return nil
+}
+
+func (x *TensorProto) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *TensorProto) GetDocString() string {
+	if x != nil && x.DocString != nil {
+		return *x.DocString
+	}
+	return ""
+}
+
+func (x *TensorProto) GetRawData() []byte {
+	if x != nil {
+		return x.RawData
+	}
+	return nil
+}
+
+func (x *TensorProto) GetDoubleData() []float64 {
+	if x != nil {
+		return x.DoubleData
+	}
+	return nil
+}
+
+func (x *TensorProto) GetUint64Data() []uint64 {
+	if x != nil {
+		return x.Uint64Data
+	}
+	return nil
+}
+
+type TypeProto_Tensor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// elem_type is a TensorProto.DataType.
+	ElemType *int32            `protobuf:"varint,1,opt,name=elem_type,json=elemType" json:"elem_type,omitempty"`
+	Shape    *TensorShapeProto `protobuf:"bytes,2,opt,name=shape" json:"shape,omitempty"`
+}
+
+func (x *TypeProto_Tensor) Reset() {
+	*x = TypeProto_Tensor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TypeProto_Tensor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeProto_Tensor) ProtoMessage() {}
+
+func (x *TypeProto_Tensor) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeProto_Tensor.ProtoReflect.Descriptor instead.
+func (*TypeProto_Tensor) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *TypeProto_Tensor) GetElemType() int32 {
+	if x != nil && x.ElemType != nil {
+		return *x.ElemType
+	}
+	return 0
+}
+
+func (x *TypeProto_Tensor) GetShape() *TensorShapeProto {
+	if x != nil {
+		return x.Shape
+	}
+	return nil
+}
+
+type TensorShapeProto_Dimension struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DimValue   *int64  `protobuf:"varint,1,opt,name=dim_value,json=dimValue" json:"dim_value,omitempty"`
+	DimParam   *string `protobuf:"bytes,2,opt,name=dim_param,json=dimParam" json:"dim_param,omitempty"`
+	Denotation *string `protobuf:"bytes,3,opt,name=denotation" json:"denotation,omitempty"`
+}
+
+func (x *TensorShapeProto_Dimension) Reset() {
+	*x = TensorShapeProto_Dimension{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TensorShapeProto_Dimension) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TensorShapeProto_Dimension) ProtoMessage() {}
+
+func (x *TensorShapeProto_Dimension) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_network_onnxpb_onnx_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TensorShapeProto_Dimension.ProtoReflect.Descriptor instead.
+func (*TensorShapeProto_Dimension) Descriptor() ([]byte, []int) {
+	return file_pkg_network_onnxpb_onnx_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *TensorShapeProto_Dimension) GetDimValue() int64 {
+	if x != nil && x.DimValue != nil {
+		return *x.DimValue
+	}
+	return 0
+}
+
+func (x *TensorShapeProto_Dimension) GetDimParam() string {
+	if x != nil && x.DimParam != nil {
+		return *x.DimParam
+	}
+	return ""
+}
+
+func (x *TensorShapeProto_Dimension) GetDenotation() string {
+	if x != nil && x.Denotation != nil {
+		return *x.Denotation
+	}
+	return ""
+}
+
+var File_pkg_network_onnxpb_onnx_proto protoreflect.FileDescriptor
+
+var file_pkg_network_onnxpb_onnx_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x70, 0x6b, 0x67, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6f, 0x6e,
+	0x6e, 0x78, 0x70, 0x62, 0x2f, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x09, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x22, 0x90, 0x03, 0x0a, 0x0a, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x72, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x69,
+	0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x0c, 0x6f, 0x70, 0x73, 0x65,
+	0x74, 0x5f, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x49, 0x64, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x0b, 0x6f,
+	0x70, 0x73, 0x65, 0x74, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x5f, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x6f, 0x63,
+	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x2b, 0x0a, 0x05, 0x67, 0x72, 0x61, 0x70, 0x68, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e,
+	0x78, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x05, 0x67, 0x72,
+	0x61, 0x70, 0x68, 0x12, 0x48, 0x0a, 0x0e, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f,
+	0x70, 0x72, 0x6f, 0x70, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6a, 0x6e,
+	0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x0d,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x50, 0x72, 0x6f, 0x70, 0x73, 0x22, 0x46, 0x0a,
+	0x12, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x49, 0x64, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x40, 0x0a, 0x16, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x53,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc1, 0x02, 0x0a, 0x0a, 0x47, 0x72, 0x61, 0x70,
+	0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x28, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x72, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6a, 0x6e, 0x65, 0x74,
+	0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x54, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x12, 0x1d,
+	0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x2f, 0x0a,
+	0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6a,
+	0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x31,
+	0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x49, 0x6e, 0x66, 0x6f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x12, 0x38, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e,
+	0x78, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xd6, 0x01, 0x0a, 0x09,
+	0x4e, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x70,
+	0x75, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6f,
+	0x70, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x70,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x37, 0x0a, 0x09,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x41, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x09, 0x61, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x5f, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x53, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x22, 0x9c, 0x05, 0x0a, 0x0e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x72,
+	0x65, 0x66, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x15, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x66, 0x41, 0x74, 0x74, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x3b,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x6a,
+	0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x66,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x01, 0x66, 0x12, 0x0c, 0x0a, 0x01, 0x69, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x01, 0x69, 0x12, 0x0c, 0x0a, 0x01, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x01, 0x73, 0x12, 0x24, 0x0a, 0x01, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x54, 0x65, 0x6e,
+	0x73, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x01, 0x74, 0x12, 0x23, 0x0a, 0x01, 0x67,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e,
+	0x6e, 0x78, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x01, 0x67,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x02,
+	0x52, 0x06, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x6e, 0x74, 0x73,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x03, 0x52, 0x04, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x74, 0x65, 0x6e, 0x73, 0x6f, 0x72,
+	0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f,
+	0x6e, 0x6e, 0x78, 0x2e, 0x54, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52,
+	0x07, 0x74, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x67, 0x72, 0x61, 0x70,
+	0x68, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e,
+	0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52,
+	0x06, 0x67, 0x72, 0x61, 0x70, 0x68, 0x73, 0x22, 0xd9, 0x01, 0x0a, 0x0d, 0x41, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x4e, 0x44,
+	0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x4c, 0x4f, 0x41,
+	0x54, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06,
+	0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x45, 0x4e, 0x53,
+	0x4f, 0x52, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x47, 0x52, 0x41, 0x50, 0x48, 0x10, 0x05, 0x12,
+	0x11, 0x0a, 0x0d, 0x53, 0x50, 0x41, 0x52, 0x53, 0x45, 0x5f, 0x54, 0x45, 0x4e, 0x53, 0x4f, 0x52,
+	0x10, 0x0b, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f,
+	0x10, 0x0d, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x53, 0x10, 0x06, 0x12, 0x08,
+	0x0a, 0x04, 0x49, 0x4e, 0x54, 0x53, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x54, 0x52, 0x49,
+	0x4e, 0x47, 0x53, 0x10, 0x08, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x45, 0x4e, 0x53, 0x4f, 0x52, 0x53,
+	0x10, 0x09, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x52, 0x41, 0x50, 0x48, 0x53, 0x10, 0x0a, 0x12, 0x12,
+	0x0a, 0x0e, 0x53, 0x50, 0x41, 0x52, 0x53, 0x45, 0x5f, 0x54, 0x45, 0x4e, 0x53, 0x4f, 0x52, 0x53,
+	0x10, 0x0c, 0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f,
+	0x53, 0x10, 0x0e, 0x22, 0x6d, 0x0a, 0x0e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x49, 0x6e, 0x66, 0x6f,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f,
+	0x6e, 0x6e, 0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x53, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x22, 0xc3, 0x01, 0x0a, 0x09, 0x54, 0x79, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x3c, 0x0a, 0x0b, 0x74, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e,
+	0x78, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x6e, 0x73,
+	0x6f, 0x72, 0x52, 0x0a, 0x74, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x64, 0x65, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x58,
+	0x0a, 0x06, 0x54, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x6c, 0x65, 0x6d,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x6c, 0x65,
+	0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6a, 0x6e, 0x65, 0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78,
+	0x2e, 0x54, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x53, 0x68, 0x61, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x52, 0x05, 0x73, 0x68, 0x61, 0x70, 0x65, 0x22, 0xb2, 0x01, 0x0a, 0x10, 0x54, 0x65, 0x6e,
+	0x73, 0x6f, 0x72, 0x53, 0x68, 0x61, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x37, 0x0a,
+	0x03, 0x64, 0x69, 0x6d, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6a, 0x6e, 0x65,
+	0x74, 0x2e, 0x6f, 0x6e, 0x6e, 0x78, 0x2e, 0x54, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x53, 0x68, 0x61,
+	0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x03, 0x64, 0x69, 0x6d, 0x1a, 0x65, 0x0a, 0x09, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x69, 0x6d, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x69, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x64, 0x69, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x69, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x65, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x65, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xbd, 0x04,
+	0x0a, 0x0b, 0x54, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x69, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x04, 0x64, 0x69, 0x6d,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x21,
+	0x0a, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x02, 0x42, 0x02, 0x10, 0x01, 0x52, 0x09, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x21, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x05, 0x42, 0x02, 0x10, 0x01, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x33, 0x32,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x44, 0x61, 0x74, 0x61, 0x12, 0x21, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x03, 0x28, 0x03, 0x42, 0x02, 0x10, 0x01, 0x52, 0x09, 0x69,
+	0x6e, 0x74, 0x36, 0x34, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x64, 0x6f, 0x63, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x64, 0x6f, 0x63, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x72,
+	0x61, 0x77, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x72,
+	0x61, 0x77, 0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65,
+	0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x01, 0x42, 0x02, 0x10, 0x01, 0x52,
+	0x0a, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0b, 0x75,
+	0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x04,
+	0x42, 0x02, 0x10, 0x01, 0x52, 0x0a, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x44, 0x61, 0x74, 0x61,
+	0x22, 0xda, 0x01, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a,
+	0x09, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05,
+	0x46, 0x4c, 0x4f, 0x41, 0x54, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x49, 0x4e, 0x54, 0x38,
+	0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x54, 0x38, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06,
+	0x55, 0x49, 0x4e, 0x54, 0x31, 0x36, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4e, 0x54, 0x31,
+	0x36, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4e, 0x54, 0x33, 0x32, 0x10, 0x06, 0x12, 0x09,
+	0x0a, 0x05, 0x49, 0x4e, 0x54, 0x36, 0x34, 0x10, 0x07, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52,
+	0x49, 0x4e, 0x47, 0x10, 0x08, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4f, 0x4c, 0x10, 0x09, 0x12,
+	0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x31, 0x36, 0x10, 0x0a, 0x12, 0x0a, 0x0a, 0x06,
+	0x44, 0x4f, 0x55, 0x42, 0x4c, 0x45, 0x10, 0x0b, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x49, 0x4e, 0x54,
+	0x33, 0x32, 0x10, 0x0c, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x49, 0x4e, 0x54, 0x36, 0x34, 0x10, 0x0d,
+	0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x58, 0x36, 0x34, 0x10, 0x0e, 0x12,
+	0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x58, 0x31, 0x32, 0x38, 0x10, 0x0f, 0x12,
+	0x0c, 0x0a, 0x08, 0x42, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x31, 0x36, 0x10, 0x10, 0x42, 0x35, 0x5a,
+	0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x49, 0x6e, 0x73, 0x75,
+	0x6c, 0x69, 0x6e, 0x63, 0x65, 0x2f, 0x6a, 0x6e, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6f, 0x6e, 0x6e, 0x78, 0x70, 0x62, 0x3b, 0x6f, 0x6e,
+	0x6e, 0x78, 0x70, 0x62,
+}
+
+var (
+	file_pkg_network_onnxpb_onnx_proto_rawDescOnce sync.Once
+	file_pkg_network_onnxpb_onnx_proto_rawDescData = file_pkg_network_onnxpb_onnx_proto_rawDesc
+)
+
+func file_pkg_network_onnxpb_onnx_proto_rawDescGZIP() []byte {
+	file_pkg_network_onnxpb_onnx_proto_rawDescOnce.Do(func() {
+		file_pkg_network_onnxpb_onnx_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_network_onnxpb_onnx_proto_rawDescData)
+	})
+	return file_pkg_network_onnxpb_onnx_proto_rawDescData
+}
+
+var file_pkg_network_onnxpb_onnx_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pkg_network_onnxpb_onnx_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pkg_network_onnxpb_onnx_proto_goTypes = []interface{}{
+	(AttributeProto_AttributeType)(0),  // 0: jnet.onnx.AttributeProto.AttributeType
+	(TensorProto_DataType)(0),          // 1: jnet.onnx.TensorProto.DataType
+	(*ModelProto)(nil),                 // 2: jnet.onnx.ModelProto
+	(*OperatorSetIdProto)(nil),         // 3: jnet.onnx.OperatorSetIdProto
+	(*StringStringEntryProto)(nil),     // 4: jnet.onnx.StringStringEntryProto
+	(*GraphProto)(nil),                 // 5: jnet.onnx.GraphProto
+	(*NodeProto)(nil),                  // 6: jnet.onnx.NodeProto
+	(*AttributeProto)(nil),             // 7: jnet.onnx.AttributeProto
+	(*ValueInfoProto)(nil),             // 8: jnet.onnx.ValueInfoProto
+	(*TypeProto)(nil),                  // 9: jnet.onnx.TypeProto
+	(*TensorShapeProto)(nil),           // 10: jnet.onnx.TensorShapeProto
+	(*TensorProto)(nil),                // 11: jnet.onnx.TensorProto
+	(*TypeProto_Tensor)(nil),           // 12: jnet.onnx.TypeProto.Tensor
+	(*TensorShapeProto_Dimension)(nil), // 13: jnet.onnx.TensorShapeProto.Dimension
+}
+var file_pkg_network_onnxpb_onnx_proto_depIdxs = []int32{
+	3,  // 0: jnet.onnx.ModelProto.opset_import:type_name -> jnet.onnx.OperatorSetIdProto
+	5,  // 1: jnet.onnx.ModelProto.graph:type_name -> jnet.onnx.GraphProto
+	4,  // 2: jnet.onnx.ModelProto.metadata_props:type_name -> jnet.onnx.StringStringEntryProto
+	6,  // 3: jnet.onnx.GraphProto.node:type_name -> jnet.onnx.NodeProto
+	11, // 4: jnet.onnx.GraphProto.initializer:type_name -> jnet.onnx.TensorProto
+	8,  // 5: jnet.onnx.GraphProto.input:type_name -> jnet.onnx.ValueInfoProto
+	8,  // 6: jnet.onnx.GraphProto.output:type_name -> jnet.onnx.ValueInfoProto
+	8,  // 7: jnet.onnx.GraphProto.value_info:type_name -> jnet.onnx.ValueInfoProto
+	7,  // 8: jnet.onnx.NodeProto.attribute:type_name -> jnet.onnx.AttributeProto
+	0,  // 9: jnet.onnx.AttributeProto.type:type_name -> jnet.onnx.AttributeProto.AttributeType
+	11, // 10: jnet.onnx.AttributeProto.t:type_name -> jnet.onnx.TensorProto
+	5,  // 11: jnet.onnx.AttributeProto.g:type_name -> jnet.onnx.GraphProto
+	11, // 12: jnet.onnx.AttributeProto.tensors:type_name -> jnet.onnx.TensorProto
+	5,  // 13: jnet.onnx.AttributeProto.graphs:type_name -> jnet.onnx.GraphProto
+	9,  // 14: jnet.onnx.ValueInfoProto.type:type_name -> jnet.onnx.TypeProto
+	12, // 15: jnet.onnx.TypeProto.tensor_type:type_name -> jnet.onnx.TypeProto.Tensor
+	13, // 16: jnet.onnx.TensorShapeProto.dim:type_name -> jnet.onnx.TensorShapeProto.Dimension
+	10, // 17: jnet.onnx.TypeProto.Tensor.shape:type_name -> jnet.onnx.TensorShapeProto
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_pkg_network_onnxpb_onnx_proto_init() }
+func file_pkg_network_onnxpb_onnx_proto_init() {
+	if File_pkg_network_onnxpb_onnx_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModelProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OperatorSetIdProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StringStringEntryProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttributeProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValueInfoProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TensorShapeProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TensorProto); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TypeProto_Tensor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_network_onnxpb_onnx_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TensorShapeProto_Dimension); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_network_onnxpb_onnx_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_network_onnxpb_onnx_proto_goTypes,
+		DependencyIndexes: file_pkg_network_onnxpb_onnx_proto_depIdxs,
+		EnumInfos:         file_pkg_network_onnxpb_onnx_proto_enumTypes,
+		MessageInfos:      file_pkg_network_onnxpb_onnx_proto_msgTypes,
+	}.Build()
+	File_pkg_network_onnxpb_onnx_proto = out.File
+	file_pkg_network_onnxpb_onnx_proto_rawDesc = nil
+	file_pkg_network_onnxpb_onnx_proto_goTypes = nil
+	file_pkg_network_onnxpb_onnx_proto_depIdxs = nil
+}
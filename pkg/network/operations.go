@@ -2,6 +2,7 @@ package network
 
 import (
 	"fmt"
+	"reflect"
 
 	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
 )
@@ -347,6 +348,19 @@ func (nw Network) SetNeuronBiasesTo(val float64) {
 	}
 }
 
+// NeuronBiases returns the bias of every neuron in nw, laid out identically to
+// the argument of SetNeuronBiases.
+func (nw Network) NeuronBiases() [][]float64 {
+	biases := make([][]float64, len(nw))
+	for li, l := range nw {
+		biases[li] = make([]float64, len(l))
+		for ni, n := range l {
+			biases[li][ni] = n.bias
+		}
+	}
+	return biases
+}
+
 func (nw Network) SetNeuronActivationFunctions(activationFunctionNames [][]activationfunction.Name) error {
 	if len(activationFunctionNames) != len(nw) {
 		return fmt.Errorf("invalid number of sets of activation functions provided (%v), does not match number of layers in network (%v)", len(activationFunctionNames), len(nw))
@@ -396,6 +410,20 @@ func (nw Network) MustSetNeuronActivationFunctionsTo(activationFunctionName acti
 	}
 }
 
+// NeuronActivationFunctions returns the name of the activation function of
+// every neuron in nw, laid out identically to the argument of
+// SetNeuronActivationFunctions.
+func (nw Network) NeuronActivationFunctions() [][]activationfunction.Name {
+	names := make([][]activationfunction.Name, len(nw))
+	for li, l := range nw {
+		names[li] = make([]activationfunction.Name, len(l))
+		for ni, n := range l {
+			names[li][ni] = n.ActivationFunctionName
+		}
+	}
+	return names
+}
+
 func (nw Network) SetConnectionWeights(weights [][][]float64) error {
 	if len(weights) != len(nw) {
 		return fmt.Errorf("invalid number of sets of sets of weights provided (%v), does not match number of layers in network (%v)", len(weights), len(nw))
@@ -438,6 +466,22 @@ func (nw Network) SetConnectionWeightsTo(weight float64) {
 	}
 }
 
+// ConnectionWeights returns the weight of every connection in nw, laid out
+// identically to the argument of SetConnectionWeights.
+func (nw Network) ConnectionWeights() [][][]float64 {
+	weights := make([][][]float64, len(nw))
+	for li, l := range nw {
+		weights[li] = make([][]float64, len(l))
+		for ni, n := range l {
+			weights[li][ni] = make([]float64, len(n.Connections))
+			for ci, c := range n.Connections {
+				weights[li][ni][ci] = c.weight
+			}
+		}
+	}
+	return weights
+}
+
 func (l Layer) Equals(l2 Layer) error {
 	if len(l) != len(l2) {
 		return fmt.Errorf("layers are different lengths, %v != %v", len(l), len(l2))
@@ -793,6 +837,9 @@ func (n *Neuron) Equals(n2 *Neuron) error {
 	if n.label != n2.label {
 		return fmt.Errorf("neurons' labels do not match, %v != %v", n.label, n2.label)
 	}
+	if !reflect.DeepEqual(n.transform, n2.transform) {
+		return fmt.Errorf("neurons' input transforms do not match, %v != %v", n.transform, n2.transform)
+	}
 	if n.value != n2.value {
 		return fmt.Errorf("neurons' values do not match, %v != %v", n.value, n2.value)
 	}
@@ -815,13 +862,11 @@ func (n *Neuron) Equals(n2 *Neuron) error {
 		return fmt.Errorf("neurons' dNetDBias do not match, %v != %v", n.dNetDBias, n2.dNetDBias)
 	}
 
-	if len(n.biasNudges) != len(n2.biasNudges) {
-		return fmt.Errorf("neurons do not have same number of bias nudges, %v != %v", len(n.biasNudges), n2.biasNudges)
+	if n.biasNudgeSum != n2.biasNudgeSum {
+		return fmt.Errorf("neurons' biasNudgeSums do not match, %v != %v", n.biasNudgeSum, n2.biasNudgeSum)
 	}
-	for bi := range n.biasNudges {
-		if n.biasNudges[bi] != n2.biasNudges[bi] {
-			return fmt.Errorf("neurons' biasNudges do not match, %v != %v", n.biasNudges[bi], n2.biasNudges[bi])
-		}
+	if n.nudges != n2.nudges {
+		return fmt.Errorf("neurons do not have same number of bias nudges, %v != %v", n.nudges, n2.nudges)
 	}
 
 	return nil
@@ -1076,13 +1121,11 @@ func (c *Connection) Equals(c2 *Connection) error {
 		return fmt.Errorf("connections' dNetDPrevValues do not match, %v != %v", c.dNetDPrevValue, c2.dNetDPrevValue)
 	}
 
-	if len(c.weightNudges) != len(c2.weightNudges) {
-		return fmt.Errorf("connections do not have same number of weight nudges, %v != %v", len(c.weightNudges), len(c.weightNudges))
+	if c.weightNudgeSum != c2.weightNudgeSum {
+		return fmt.Errorf("connections' weightNudgeSums do not match, %v != %v", c.weightNudgeSum, c2.weightNudgeSum)
 	}
-	for wi := range c.weightNudges {
-		if c.weightNudges[wi] != c2.weightNudges[wi] {
-			return fmt.Errorf("connections' biasNudges do not match, %v != %v", c.weightNudges[wi], c2.weightNudges[wi])
-		}
+	if c.nudges != c2.nudges {
+		return fmt.Errorf("connections do not have same number of weight nudges, %v != %v", c.nudges, c2.nudges)
 	}
 
 	return nil
@@ -0,0 +1,117 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// NumParameters returns the number of biases and weights in nw, excluding the
+// biases of the input layer, which are never used.
+func (nw Network) NumParameters() int {
+	size := 0
+	for li, l := range nw {
+		for _, n := range l {
+			size += len(n.Connections)
+			if li > 0 {
+				size++
+			}
+		}
+	}
+	return size
+}
+
+// Parameters returns every bias and weight of nw flattened into a single
+// vector, laid out as Gradients.Flatten lays out gradients: the bias of every
+// neuron, layer by layer, followed by the weight of every connection, layer by
+// layer and neuron by neuron. The biases of the input layer are left out, as
+// ForwardPass never reads them.
+func (nw Network) Parameters() []float64 {
+	ps := make([]float64, 0, nw.NumParameters())
+	for li := 1; li < len(nw); li++ {
+		for _, n := range nw[li] {
+			ps = append(ps, n.bias)
+		}
+	}
+	for _, l := range nw {
+		for _, n := range l {
+			for _, c := range n.Connections {
+				ps = append(ps, c.weight)
+			}
+		}
+	}
+	return ps
+}
+
+// SetParameters sets every bias and weight of nw from ps, which is laid out as
+// returned by Parameters. The biases of the input layer are left as they are.
+//
+// If len(ps) != nw.NumParameters() an error is returned and nw is left
+// unchanged.
+func (nw Network) SetParameters(ps []float64) error {
+	if len(ps) != nw.NumParameters() {
+		return fmt.Errorf("invalid number of parameters provided (%v), does not match number of parameters in network (%v)", len(ps), nw.NumParameters())
+	}
+
+	i := 0
+	for li := 1; li < len(nw); li++ {
+		for _, n := range nw[li] {
+			n.bias = ps[i]
+			i++
+		}
+	}
+	for _, l := range nw {
+		for _, n := range l {
+			for _, c := range n.Connections {
+				c.weight = ps[i]
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+// MustSetParameters calls SetParameters but panics if an error is encountered.
+func (nw Network) MustSetParameters(ps []float64) {
+	err := nw.SetParameters(ps)
+	if err != nil {
+		panic(errors.Wrap(err, "must set parameters"))
+	}
+}
+
+// Clone returns a deep copy of nw, with the same shape, labels, activation
+// functions, input transforms, biases and weights, but none of nw's pass or
+// batch state. Connections of the copy lead to the copies of the neurons the
+// connections of nw lead to.
+func (nw Network) Clone() Network {
+	copies := make(map[*Neuron]*Neuron)
+	nw2 := make(Network, len(nw))
+	for li, l := range nw {
+		nw2[li] = make(Layer, len(l))
+		for ni, n := range l {
+			n2 := &Neuron{
+				ActivationFunctionName: n.ActivationFunctionName,
+				activationFunction:     n.activationFunction,
+				label:                  n.label,
+				bias:                   n.bias,
+			}
+			if n.transform != nil {
+				t := *n.transform
+				t.Steps = append([]TransformStep(nil), n.transform.Steps...)
+				n2.transform = &t
+			}
+			nw2[li][ni] = n2
+			copies[n] = n2
+		}
+	}
+	for li, l := range nw {
+		for ni, n := range l {
+			cs := make([]*Connection, len(n.Connections))
+			for ci, c := range n.Connections {
+				cs[ci] = &Connection{To: copies[c.To], weight: c.weight}
+			}
+			nw2[li][ni].Connections = cs
+		}
+	}
+	return nw2
+}
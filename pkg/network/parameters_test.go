@@ -0,0 +1,81 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_Parameters(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 3, 1},
+		OutputLabels:           []string{"a"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	// The biases of the input layer are never used, so they aren't parameters.
+	if n := nw.NumParameters(); n != 3+1+6+3 {
+		t.Fatalf("expected 13 parameters, got %v", n)
+	}
+
+	// Parameters are laid out the same as Gradients.
+	g := NewGradients(nw)
+	g.Biases = nw.NeuronBiases()
+	g.Weights = nw.ConnectionWeights()
+	if ps, expected := nw.Parameters(), g.Flatten(); !reflect.DeepEqual(ps, expected) {
+		t.Fatalf("expected %v, got %v", expected, ps)
+	}
+
+	ps := make([]float64, nw.NumParameters())
+	for i := range ps {
+		ps[i] = float64(i)
+	}
+	inputBiases := nw.NeuronBiases()[0]
+	nw.MustSetParameters(ps)
+	if !reflect.DeepEqual(nw.Parameters(), ps) {
+		t.Fatalf("expected %v, got %v", ps, nw.Parameters())
+	}
+	if b := nw[1][0].bias; b != 0 {
+		t.Fatalf("expected the first bias of layer 1 to be 0, got %v", b)
+	}
+	if w := nw[2][0].Connections[2].weight; w != 12 {
+		t.Fatalf("expected the last weight to be 12, got %v", w)
+	}
+	if !reflect.DeepEqual(nw.NeuronBiases()[0], inputBiases) {
+		t.Fatalf("expected the biases of the input layer to be unchanged, got %v", nw.NeuronBiases()[0])
+	}
+
+	if err := nw.SetParameters(ps[1:]); err == nil {
+		t.Fatal("expected an error for too few parameters")
+	}
+}
+
+func Test_Clone(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 3, 2},
+		InputLabels:            []string{"x", "y"},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.MustSetNeuronActivationFunctions([][]activationfunction.Name{
+		{activationfunction.NameSigmoid, activationfunction.NameSigmoid},
+		{activationfunction.NameRelu, activationfunction.NameTanh, activationfunction.NameLinear},
+		{activationfunction.NameSigmoid, activationfunction.NameSigmoid},
+	})
+	nw.MustSetInputTransforms([]InputTransform{{Source: 0}, {Source: 1, Steps: []TransformStep{{Kind: TransformLog, Offset: 1}}}})
+
+	nw2 := nw.Clone()
+	if err := nw.Equals(nw2); err != nil {
+		t.Fatalf("expected clone to equal the original: %v", err)
+	}
+	if nw2[1][0].Connections[1].To != nw2[0][1] {
+		t.Fatal("expected connections of the clone to lead to neurons of the clone")
+	}
+
+	input := []float64{0.5, 2}
+	expected := nw.MustPredictVector(input)
+	nw2.SetNeuronBiasesTo(0)
+	if actual := nw.MustPredictVector(input); !reflect.DeepEqual(actual, expected) {
+		t.Fatal("expected changes to the clone to leave the original unchanged")
+	}
+}
@@ -0,0 +1,209 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Prediction is the label and value of a single output neuron after a
+// prediction has been made.
+type Prediction struct {
+	Label string
+	Value float64
+}
+
+// PredictVector will execute a ForwardPass on nw using input as its input, then
+// returns the values of every output neuron index-wise. This is most useful for
+// regression, where the raw outputs are the prediction.
+//
+// If len(input) != nw.RawInputs() then an error will be returned.
+func (nw Network) PredictVector(input []float64) ([]float64, error) {
+	err := nw.predict(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ll := nw.LastLayer()
+	vs := make([]float64, len(ll))
+	for ni := range ll {
+		vs[ni] = ll[ni].value
+	}
+	return vs, nil
+}
+
+// MustPredictVector calls PredictVector but panics if an error is encountered.
+func (nw Network) MustPredictVector(input []float64) []float64 {
+	vs, err := nw.PredictVector(input)
+	if err != nil {
+		panic(err)
+	}
+	return vs
+}
+
+// OutputVector will execute a ForwardPass on nw using input as the values of
+// its input layer, then returns the values of every output neuron index-wise.
+// Unlike PredictVector, the input transforms of nw are not applied, so it is
+// suited to data which has already been transformed for training, such as
+// trainer.Data.
+//
+// If len(input) != len(nw.FirstLayer()) then an error will be returned.
+func (nw Network) OutputVector(input []float64) ([]float64, error) {
+	if len(nw) == 0 {
+		return nil, errors.New("cannot compute outputs: network has no layers")
+	}
+	if len(input) != len(nw.FirstLayer()) {
+		return nil, fmt.Errorf("invalid number of values provided (%v), does no match number of neurons in Layer (%v)", len(input), len(nw.FirstLayer()))
+	}
+
+	nw.ResetFromBatch()
+	if err := nw.ForwardPass(input); err != nil {
+		return nil, err
+	}
+
+	ll := nw.LastLayer()
+	vs := make([]float64, len(ll))
+	for ni := range ll {
+		vs[ni] = ll[ni].value
+	}
+	return vs, nil
+}
+
+// MustOutputVector calls OutputVector but panics if an error is encountered.
+func (nw Network) MustOutputVector(input []float64) []float64 {
+	vs, err := nw.OutputVector(input)
+	if err != nil {
+		panic(err)
+	}
+	return vs
+}
+
+// PredictAll will execute a ForwardPass on nw using input as its input, then
+// returns the value of every output neuron keyed by its label.
+//
+// If len(input) != nw.RawInputs() or the output labels of nw are not
+// unique then an error will be returned.
+func (nw Network) PredictAll(input []float64) (map[string]float64, error) {
+	err := nw.predict(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ll := nw.LastLayer()
+	m := make(map[string]float64, len(ll))
+	for ni := range ll {
+		if _, found := m[ll[ni].label]; found {
+			return nil, fmt.Errorf("cannot map predictions to labels: output label %q is not unique", ll[ni].label)
+		}
+		m[ll[ni].label] = ll[ni].value
+	}
+	return m, nil
+}
+
+// MustPredictAll calls PredictAll but panics if an error is encountered.
+func (nw Network) MustPredictAll(input []float64) map[string]float64 {
+	m, err := nw.PredictAll(input)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// TopK will execute a ForwardPass on nw using input as its input, then returns
+// the k output neurons with the highest values, ordered from highest to lowest.
+// If k is larger than the number of output neurons, all of them are returned.
+//
+// If len(input) != nw.RawInputs() or k < 1 then an error will be
+// returned.
+func (nw Network) TopK(input []float64, k int) ([]Prediction, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("cannot get top k predictions: k must be at least 1 (requested %v)", k)
+	}
+
+	err := nw.predict(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := nw.sortedPredictions()
+	if k > len(ps) {
+		k = len(ps)
+	}
+	return ps[:k], nil
+}
+
+// MustTopK calls TopK but panics if an error is encountered.
+func (nw Network) MustTopK(input []float64, k int) []Prediction {
+	ps, err := nw.TopK(input, k)
+	if err != nil {
+		panic(err)
+	}
+	return ps
+}
+
+// PredictMultiLabel will execute a ForwardPass on nw using input as its input,
+// then returns every output neuron whose value is greater than or equal to
+// threshold, ordered from highest to lowest. Unlike Predict, any number of
+// labels (including none) may apply to a single input.
+//
+// If len(input) != nw.RawInputs() then an error will be returned.
+func (nw Network) PredictMultiLabel(input []float64, threshold float64) ([]Prediction, error) {
+	err := nw.predict(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var ps []Prediction
+	for _, p := range nw.sortedPredictions() {
+		if p.Value < threshold {
+			break
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// MustPredictMultiLabel calls PredictMultiLabel but panics if an error is
+// encountered.
+func (nw Network) MustPredictMultiLabel(input []float64, threshold float64) []Prediction {
+	ps, err := nw.PredictMultiLabel(input, threshold)
+	if err != nil {
+		panic(err)
+	}
+	return ps
+}
+
+// predict resets nw and executes a ForwardPass using input, after applying
+// any input transforms of nw, as its input so that the output layer may be
+// inspected.
+func (nw Network) predict(input []float64) error {
+	if len(nw) == 0 {
+		return errors.New("cannot make prediction: network has no layers")
+	}
+	if !nw.hasInputTransforms() && len(input) != len(nw.FirstLayer()) {
+		return fmt.Errorf("invalid number of values provided (%v), does no match number of neurons in Layer (%v)", len(input), len(nw.FirstLayer()))
+	}
+	input, err := nw.TransformInput(input)
+	if err != nil {
+		return err
+	}
+
+	nw.ResetFromBatch()
+
+	return nw.ForwardPass(input)
+}
+
+// sortedPredictions returns a Prediction for every neuron in nw's last layer,
+// ordered from highest value to lowest. Neurons of equal value retain their
+// order in the layer.
+func (nw Network) sortedPredictions() []Prediction {
+	ll := nw.LastLayer()
+	ps := make([]Prediction, len(ll))
+	for ni := range ll {
+		ps[ni] = Prediction{Label: ll[ni].label, Value: ll[ni].value}
+	}
+	sort.SliceStable(ps, func(i, j int) bool {
+		return ps[i].Value > ps[j].Value
+	})
+	return ps
+}
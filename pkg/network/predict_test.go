@@ -0,0 +1,68 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_Predictions(t *testing.T) {
+	// The network is linear with identity weights, so its outputs equal its
+	// inputs.
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{4, 4},
+		OutputLabels:           []string{"a", "b", "c", "d"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.MustSetConnectionWeights([][][]float64{
+		{{}, {}, {}, {}},
+		{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}},
+	})
+	input := []float64{0.2, 0.9, -0.5, 0.6}
+
+	if vs := nw.MustPredictVector(input); !reflect.DeepEqual(vs, input) {
+		t.Fatalf("expected vector %v, got %v", input, vs)
+	}
+
+	all := nw.MustPredictAll(input)
+	expected := map[string]float64{"a": 0.2, "b": 0.9, "c": -0.5, "d": 0.6}
+	if !reflect.DeepEqual(all, expected) {
+		t.Fatalf("expected map %v, got %v", expected, all)
+	}
+
+	top := nw.MustTopK(input, 2)
+	if !reflect.DeepEqual(top, []Prediction{{"b", 0.9}, {"d", 0.6}}) {
+		t.Fatalf("unexpected top 2 predictions: %v", top)
+	}
+	if top := nw.MustTopK(input, 10); len(top) != 4 || top[3].Label != "c" {
+		t.Fatalf("expected all predictions ordered by value, got %v", top)
+	}
+
+	multi := nw.MustPredictMultiLabel(input, 0.5)
+	if !reflect.DeepEqual(multi, []Prediction{{"b", 0.9}, {"d", 0.6}}) {
+		t.Fatalf("unexpected multi-label predictions: %v", multi)
+	}
+	if multi := nw.MustPredictMultiLabel(input, 1); len(multi) != 0 {
+		t.Fatalf("expected no labels above threshold, got %v", multi)
+	}
+}
+
+func Test_Predictions_Errors(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 2},
+		OutputLabels:           []string{"a", "a"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+
+	if _, err := nw.PredictAll([]float64{1, 2}); err == nil {
+		t.Fatalf("expected an error for duplicate output labels")
+	}
+	if _, err := nw.TopK([]float64{1, 2}, 0); err == nil {
+		t.Fatalf("expected an error for k < 1")
+	}
+	if _, err := nw.PredictVector([]float64{1}); err == nil {
+		t.Fatalf("expected an error for input of the wrong length")
+	}
+}
@@ -1,16 +1,20 @@
 package network
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 
 	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
-	"github.com/Insulince/jnet/pkg/network/networkspb"
+	"github.com/Insulince/jnet/pkg/network/networkspb/v2"
 )
 
 type (
 	protoTranslator struct {
-		opts []TranslatorOption
+		metadata map[string]string
+		opts     []TranslatorOption
 	}
 )
 
@@ -22,18 +26,31 @@ func NewProtoTranslator(opts ...TranslatorOption) Translator {
 	}
 }
 
+// NewProtoTranslatorWithMetadata returns a proto Translator which additionally
+// stores metadata in the free-form metadata of every network it writes. The
+// "lossFunctionName" key is always written by the translator itself. Metadata
+// can be read back via ReadProtoMetadata.
+func NewProtoTranslatorWithMetadata(metadata map[string]string, opts ...TranslatorOption) Translator {
+	return protoTranslator{
+		metadata: metadata,
+		opts:     opts,
+	}
+}
+
 func (pt protoTranslator) Serialize(nw Network) ([]byte, error) {
-	pnw := toProto(nw)
-	bs, err := proto.Marshal(pnw)
+	if err := checkFullyConnected(nw); err != nil {
+		return nil, err
+	}
+
+	pnw := toProto(nw, pt.metadata)
+	bs, err := protoMarshal(pnw)
 	if err != nil {
 		return nil, errors.Wrap(err, "proto marshalling")
 	}
 
-	for i, opt := range pt.opts {
-		bs, err = opt.Serialize(bs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "translator serialize option %v", i)
-		}
+	bs, err = serialize(bs, pt.opts)
+	if err != nil {
+		return nil, err
 	}
 
 	return bs, nil
@@ -49,12 +66,9 @@ func (pt protoTranslator) MustSerialize(nw Network) []byte {
 }
 
 func (pt protoTranslator) Deserialize(bs []byte) (Network, error) {
-	var err error
-	for i, opt := range pt.opts {
-		bs, err = opt.Deserialize(bs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "translator deserialize option %v", i)
-		}
+	bs, err := deserialize(bs, pt.opts)
+	if err != nil {
+		return nil, err
 	}
 
 	pnw := &networkspb.Network{}
@@ -77,30 +91,111 @@ func (pt protoTranslator) MustDeserialize(bs []byte) Network {
 	return nw
 }
 
-func toProto(nw Network) *networkspb.Network {
+// ReadProtoMetadata returns the free-form metadata stored in the proto encoded
+// network in bs, reversing opts first, without building the network.
+func ReadProtoMetadata(bs []byte, opts ...TranslatorOption) (map[string]string, error) {
+	bs, err := deserialize(bs, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	pnw := &networkspb.Network{}
+	if err := proto.Unmarshal(bs, pnw); err != nil {
+		return nil, errors.Wrap(err, "proto unmarshalling")
+	}
+	if pnw.SchemaVersion > protoSchemaVersion {
+		return nil, fmt.Errorf("proto schema version %v is newer than the latest supported version %v", pnw.SchemaVersion, protoSchemaVersion)
+	}
+	return pnw.Metadata, nil
+}
+
+// MustReadProtoMetadata calls ReadProtoMetadata but panics if an error is
+// encountered.
+func MustReadProtoMetadata(bs []byte, opts ...TranslatorOption) map[string]string {
+	md, err := ReadProtoMetadata(bs, opts...)
+	if err != nil {
+		panic(errors.Wrap(err, "must read proto metadata"))
+	}
+	return md
+}
+
+// protoMarshal deterministically marshals pnw, so that serializing the same
+// Network always produces the same bytes despite its metadata being a map.
+func protoMarshal(pnw *networkspb.Network) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(pnw)
+}
+
+// protoSchemaVersion is the schema version written by the proto translator.
+const protoSchemaVersion = 2
 
-	// NOTE: Implicitly assumes the entire network uses the same activation
-	// function AND that there exists a first layer AND there exists a first
-	// neuron.
-	pnw.ActivationFunctionName = string(nw[0][0].ActivationFunctionName)
+func toProto(nw Network, metadata map[string]string) *networkspb.Network {
+	pnw := &networkspb.Network{
+		SchemaVersion: protoSchemaVersion,
+		Metadata:      map[string]string{},
+	}
+	for k, v := range metadata {
+		pnw.Metadata[k] = v
+	}
+	pnw.Metadata["lossFunctionName"] = LossFunctionName
+
+	// NOTE: Populated only so that readers of the original schema, which
+	// assumes the entire network uses the same activation function, can still
+	// read the network.
+	if len(nw) > 0 && len(nw[0]) > 0 {
+		pnw.ActivationFunctionName = string(nw[0][0].ActivationFunctionName)
+	}
 
 	var pls []*networkspb.Layer
-	for _, l := range nw {
+	for li, l := range nw {
 		pl := &networkspb.Layer{}
 
+		pl.Kind = protoLayerKind(li, len(nw))
+		if len(l) > 0 {
+			pl.ActivationFunctionName = string(l[0].ActivationFunctionName)
+		}
+
 		var pns []*networkspb.Neuron
 		for _, n := range l {
 			pn := &networkspb.Neuron{}
 
 			pn.Label = n.label
 			pn.Bias = n.bias
+			if afn := string(n.ActivationFunctionName); afn != pl.ActivationFunctionName {
+				pn.ActivationFunctionName = afn
+			}
+			if n.nudges > 0 {
+				pn.TrainingState = &networkspb.NeuronTrainingState{
+					BiasNudgeSum: n.biasNudgeSum,
+					Nudges:       uint64(n.nudges),
+				}
+			}
+			if t := n.transform; t != nil {
+				pt := &networkspb.InputTransform{Source: uint32(t.Source)}
+				for _, s := range t.Steps {
+					pt.Steps = append(pt.Steps, &networkspb.TransformStep{
+						Kind:     string(s.Kind),
+						Mean:     s.Mean,
+						Std:      s.Std,
+						Min:      s.Min,
+						Max:      s.Max,
+						Category: s.Category,
+						Offset:   s.Offset,
+					})
+				}
+				pn.InputTransform = pt
+			}
 
 			var pcs []*networkspb.Connection
 			for _, c := range n.Connections {
 				pc := &networkspb.Connection{}
 
 				pc.Weight = c.weight
+				if c.nudges > 0 {
+					pc.TrainingState = &networkspb.ConnectionTrainingState{
+						WeightNudgeSum: c.weightNudgeSum,
+						Nudges:         uint64(c.nudges),
+					}
+				}
 
 				pcs = append(pcs, pc)
 			}
@@ -117,10 +212,39 @@ func toProto(nw Network) *networkspb.Network {
 	return pnw
 }
 
+// protoLayerKind returns the kind of the layer at index li of a network of n
+// layers.
+func protoLayerKind(li, n int) networkspb.LayerKind {
+	switch li {
+	case 0:
+		return networkspb.LayerKind_LAYER_KIND_INPUT
+	case n - 1:
+		return networkspb.LayerKind_LAYER_KIND_OUTPUT
+	default:
+		return networkspb.LayerKind_LAYER_KIND_HIDDEN
+	}
+}
+
+// fromProto builds a Network from pnw, which may have been written with either
+// the original schema or schema version 2, as the two are wire compatible.
+// Layer kinds, which the original schema lacks, are checked against the
+// position of their layer when specified.
+//
+// The activation function of a neuron is taken from the neuron itself, falling
+// back to its layer and then to the network. Networks written with the
+// original schema only ever specify it on the network.
 func fromProto(pnw *networkspb.Network) (Network, error) {
+	if pnw.SchemaVersion > protoSchemaVersion {
+		return nil, fmt.Errorf("proto schema version %v is newer than the latest supported version %v", pnw.SchemaVersion, protoSchemaVersion)
+	}
+
 	nw := Network{}
 
-	for _, pl := range pnw.Layers {
+	for li, pl := range pnw.Layers {
+		if kind, expected := pl.Kind, protoLayerKind(li, len(pnw.Layers)); kind != networkspb.LayerKind_LAYER_KIND_UNSPECIFIED && kind != expected {
+			return nil, fmt.Errorf("layer %v is of kind %v but must be of kind %v", li, kind, expected)
+		}
+
 		var l Layer
 
 		for _, pn := range pl.Neurons {
@@ -128,13 +252,45 @@ func fromProto(pnw *networkspb.Network) (Network, error) {
 
 			n.label = pn.Label
 			n.bias = pn.Bias
-			n.ActivationFunctionName = activationfunction.Name(pnw.ActivationFunctionName)
-			n.activationFunction = activationfunction.MustGetFunction(activationfunction.Name(pnw.ActivationFunctionName))
+			if ts := pn.TrainingState; ts != nil {
+				n.biasNudgeSum = ts.BiasNudgeSum
+				n.nudges = int(ts.Nudges)
+			}
+			if pt := pn.InputTransform; pt != nil {
+				t := &InputTransform{Source: int(pt.Source)}
+				for _, ps := range pt.Steps {
+					t.Steps = append(t.Steps, TransformStep{
+						Kind:     TransformKind(ps.Kind),
+						Mean:     ps.Mean,
+						Std:      ps.Std,
+						Min:      ps.Min,
+						Max:      ps.Max,
+						Category: ps.Category,
+						Offset:   ps.Offset,
+					})
+				}
+				n.transform = t
+			}
+
+			afn := pn.ActivationFunctionName
+			if afn == "" {
+				afn = pl.ActivationFunctionName
+			}
+			if afn == "" {
+				afn = pnw.ActivationFunctionName
+			}
+			if err := n.SetActivationFunction(activationfunction.Name(afn)); err != nil {
+				return nil, errors.Wrap(err, "setting activation function")
+			}
 
 			for _, pc := range pn.Connections {
 				c := &Connection{}
 
 				c.weight = pc.Weight
+				if ts := pc.TrainingState; ts != nil {
+					c.weightNudgeSum = ts.WeightNudgeSum
+					c.nudges = int(ts.Nudges)
+				}
 
 				n.Connections = append(n.Connections, c)
 			}
@@ -150,6 +306,69 @@ func fromProto(pnw *networkspb.Network) (Network, error) {
 	if err := nw.ReconnectNeurons(); err != nil {
 		return nil, errors.Wrap(err, "reconnecting neurons")
 	}
+	if err := nw.checkDecodedInputTransforms(); err != nil {
+		return nil, errors.Wrap(err, "checking input transforms")
+	}
 
 	return nw, nil
 }
+
+// Encode writes the proto encoding of nw to w, applying pt's options as it is
+// written.
+//
+// NOTE: Protocol buffers can only be marshalled as a whole message, so the
+// proto encoding itself is held in memory, but none of pt's options are.
+func (pt protoTranslator) Encode(w io.Writer, nw Network) error {
+	if err := checkFullyConnected(nw); err != nil {
+		return err
+	}
+
+	return encode(w, pt.opts, func(w io.Writer) error {
+		bs, err := protoMarshal(toProto(nw, pt.metadata))
+		if err != nil {
+			return errors.Wrap(err, "proto marshalling")
+		}
+		_, err = w.Write(bs)
+		return err
+	})
+}
+
+// MustEncode calls Encode but panics if an error is encountered.
+func (pt protoTranslator) MustEncode(w io.Writer, nw Network) {
+	err := pt.Encode(w, nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must encode"))
+	}
+}
+
+// Decode reads a proto encoded Network from r, reversing pt's options as it is
+// read.
+//
+// NOTE: Protocol buffers can only be unmarshalled as a whole message, so the
+// proto encoding itself is held in memory, but none of pt's options are.
+func (pt protoTranslator) Decode(r io.Reader) (Network, error) {
+	return decode(r, pt.opts, func(r io.Reader) (Network, error) {
+		bs, err := io.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading")
+		}
+		pnw := &networkspb.Network{}
+		if err := proto.Unmarshal(bs, pnw); err != nil {
+			return nil, errors.Wrap(err, "proto unmarshalling")
+		}
+		nw, err := fromProto(pnw)
+		if err != nil {
+			return nil, errors.Wrap(err, "from proto")
+		}
+		return nw, nil
+	})
+}
+
+// MustDecode calls Decode but panics if an error is encountered.
+func (pt protoTranslator) MustDecode(rd io.Reader) Network {
+	nw, err := pt.Decode(rd)
+	if err != nil {
+		panic(errors.Wrap(err, "must decode"))
+	}
+	return nw
+}
@@ -1,9 +1,15 @@
 package network
 
 import (
+	"math"
+	"reflect"
 	"testing"
 
+	"google.golang.org/protobuf/proto"
+
 	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	networkspbv1 "github.com/Insulince/jnet/pkg/network/networkspb"
+	"github.com/Insulince/jnet/pkg/network/networkspb/v2"
 )
 
 func Test_proto_SerializeAndDeserializeAreInverses(t *testing.T) {
@@ -16,7 +22,8 @@ func Test_proto_SerializeAndDeserializeAreInverses(t *testing.T) {
 	nw := MustFrom(spec)
 
 	// NOTE: This is not included like it is for the JSON translator because
-	// proto translation does not encode things like weight and bias nudges.
+	// proto translation does not encode the values of an individual pass, such
+	// as neuron values and derivatives.
 	// nw.MustForwardPass([]float64{1, 0, 0, 0, 0})
 	// nw.MustBackwardPass([]float64{1, 0, 0})
 	// nw.RecordNudges()
@@ -34,3 +41,134 @@ func Test_proto_SerializeAndDeserializeAreInverses(t *testing.T) {
 		t.Fatalf("original proto encoding and deserialized network proto encoding do not equal each other")
 	}
 }
+
+func Test_proto_PreservesActivationFunctionsAndTrainingState(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 3, 2},
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw[1].MustSetActivationFunctions([]activationfunction.Name{
+		activationfunction.NameTanh,
+		activationfunction.NameRelu,
+		activationfunction.NameTanh,
+	})
+	nw.MustForwardPass([]float64{0.3, 0.7})
+	nw.MustBackwardPass([]float64{1, 0})
+	nw.RecordNudges()
+
+	pt := NewProtoTranslator()
+	nw2 := pt.MustDeserialize(pt.MustSerialize(nw))
+
+	for li := range nw {
+		for ni := range nw[li] {
+			if nw2[li][ni].ActivationFunctionName != nw[li][ni].ActivationFunctionName {
+				t.Fatalf("expected layer %v neuron %v to have activation function %v, got %v", li, ni, nw[li][ni].ActivationFunctionName, nw2[li][ni].ActivationFunctionName)
+			}
+		}
+	}
+	if !reflect.DeepEqual(nw2.Gradients(), nw.Gradients()) {
+		t.Fatalf("expected gradients %v, got %v", nw.Gradients(), nw2.Gradients())
+	}
+}
+
+func Test_proto_DeserializesOriginalSchema(t *testing.T) {
+	v1 := &networkspbv1.Network{
+		ActivationFunctionName: string(activationfunction.NameTanh),
+		Layers: []*networkspbv1.Layer{
+			{Neurons: []*networkspbv1.Neuron{{Label: "in"}}},
+			{Neurons: []*networkspbv1.Neuron{
+				{Label: "out", Bias: 0.5, Connections: []*networkspbv1.Connection{{Weight: 2}}},
+			}},
+		},
+	}
+	bs, err := proto.Marshal(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nw := NewProtoTranslator().MustDeserialize(bs)
+
+	expected := Spec{
+		NeuronMap:              []int{1, 1},
+		InputLabels:            []string{"in"},
+		OutputLabels:           []string{"out"},
+		ActivationFunctionName: activationfunction.NameTanh,
+	}
+	if spec := nw.Spec(); !reflect.DeepEqual(spec, expected) {
+		t.Fatalf("expected spec %+v, got %+v", expected, spec)
+	}
+	if prediction := nw.MustPredictVector([]float64{1}); math.Abs(prediction[0]-math.Tanh(2*1+0.5)) > 1e-12 {
+		t.Fatalf("expected prediction %v, got %v", math.Tanh(2*1+0.5), prediction[0])
+	}
+}
+
+func Test_proto_SerializesReadableByOriginalSchema(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 1},
+		InputLabels:            []string{"a", "b"},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameRelu,
+	})
+
+	v1 := &networkspbv1.Network{}
+	if err := proto.Unmarshal(NewProtoTranslator().MustSerialize(nw), v1); err != nil {
+		t.Fatal(err)
+	}
+
+	if v1.ActivationFunctionName != string(activationfunction.NameRelu) {
+		t.Fatalf("expected activation function %q, got %q", activationfunction.NameRelu, v1.ActivationFunctionName)
+	}
+	if len(v1.Layers) != 2 || len(v1.Layers[1].Neurons) != 1 || v1.Layers[1].Neurons[0].Label != "x" {
+		t.Fatalf("expected an input layer and an output layer labelled x, got %v", v1.Layers)
+	}
+}
+
+func Test_proto_RejectsNewerSchema(t *testing.T) {
+	bs, err := proto.Marshal(&networkspb.Network{SchemaVersion: protoSchemaVersion + 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewProtoTranslator().Deserialize(bs); err == nil {
+		t.Fatal("expected an error deserializing a newer schema version")
+	}
+}
+
+func Test_proto_Metadata(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 1},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	pt := NewProtoTranslatorWithMetadata(map[string]string{"author": "jnet"}, WithCompression())
+	bs := pt.MustSerialize(nw)
+
+	md := MustReadProtoMetadata(bs, WithCompression())
+	expected := map[string]string{"author": "jnet", "lossFunctionName": LossFunctionName}
+	if !reflect.DeepEqual(md, expected) {
+		t.Fatalf("expected metadata %v, got %v", expected, md)
+	}
+	if _, err := pt.Deserialize(bs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_proto_RejectsMismatchedLayerKind(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{2, 3, 1},
+		OutputLabels:           []string{"x"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	pnw := toProto(nw, nil)
+	pnw.Layers[1].Kind = networkspb.LayerKind_LAYER_KIND_OUTPUT
+	bs, err := proto.Marshal(pnw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewProtoTranslator().Deserialize(bs); err == nil {
+		t.Fatal("expected an error deserializing a hidden layer of output kind")
+	}
+}
@@ -0,0 +1,36 @@
+package network
+
+// AddConnection connects n to pn with weight, in addition to any connections n
+// already has, and returns the new Connection. Unlike ConnectTo, pn may be any
+// neuron of any earlier layer rather than every neuron of the previous layer,
+// so networks built this way may be sparse and may skip layers.
+//
+// ForwardPass, and the predictions built on it, support such networks so long
+// as they are feed forward, see IsFeedForward. BackwardPass, and therefore
+// training via the trainer package, the translators, WriteNPZ and the codegen
+// package all require networks to be fully connected, see IsFullyConnected.
+func (n *Neuron) AddConnection(pn *Neuron, weight float64) *Connection {
+	c := &Connection{To: pn, weight: weight}
+	n.Connections = append(n.Connections, c)
+	return c
+}
+
+// IsFeedForward reports whether every connection of every neuron in nw leads to
+// a neuron of an earlier layer, so that ForwardPass computes the value of every
+// neuron before it is used. Fully connected networks are always feed forward.
+func (nw Network) IsFeedForward() bool {
+	earlier := make(map[*Neuron]bool)
+	for _, l := range nw {
+		for _, n := range l {
+			for _, c := range n.Connections {
+				if !earlier[c.To] {
+					return false
+				}
+			}
+		}
+		for _, n := range l {
+			earlier[n] = true
+		}
+	}
+	return true
+}
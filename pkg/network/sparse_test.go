@@ -0,0 +1,86 @@
+package network
+
+import (
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_AddConnection(t *testing.T) {
+	x := MustNewNeuron(nil, activationfunction.NameLinear)
+	y := MustNewNeuron(nil, activationfunction.NameLinear)
+	h := MustNewNeuron(nil, activationfunction.NameLinear)
+	o := MustNewNeuron(nil, activationfunction.NameLinear)
+	for _, n := range []*Neuron{x, y, h, o} {
+		n.SetBias(0)
+	}
+	o.SetLabel("o")
+
+	// h only reads x, and o reads h and skips a layer to read y.
+	h.AddConnection(x, 2)
+	o.AddConnection(h, 3)
+	o.AddConnection(y, -1)
+	nw := Network{{x, y}, {h}, {o}}
+
+	if !nw.IsFeedForward() {
+		t.Fatal("expected network to be feed forward")
+	}
+	if nw.IsFullyConnected() {
+		t.Fatal("expected network to not be fully connected")
+	}
+
+	vs, err := nw.PredictVector([]float64{1, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vs[0] != 2 {
+		t.Fatalf("expected 2*3 - 4 = 2, got %v", vs[0])
+	}
+
+	if err := nw.BackwardPass([]float64{0}); err == nil {
+		t.Fatal("expected an error back propagating through a sparse network")
+	}
+
+	h.AddConnection(o, 1)
+	if nw.IsFeedForward() {
+		t.Fatal("expected a connection to a later layer to not be feed forward")
+	}
+}
+
+func Test_IsFullyConnected(t *testing.T) {
+	a := MustNewNeuron(nil, activationfunction.NameLinear)
+	b := MustNewNeuron(nil, activationfunction.NameLinear)
+	h1 := MustNewNeuron(nil, activationfunction.NameLinear)
+	h2 := MustNewNeuron(nil, activationfunction.NameLinear)
+	o := MustNewNeuron(nil, activationfunction.NameLinear)
+	o.SetLabel("o")
+
+	// Every neuron has as many connections as its previous layer has neurons,
+	// but h2 reads them out of order and o reads a rather than h2.
+	h1.AddConnection(a, 1)
+	h1.AddConnection(b, 1)
+	h2.AddConnection(b, 1)
+	h2.AddConnection(a, 1)
+	o.AddConnection(a, 1)
+	o.AddConnection(h1, 1)
+	nw := Network{{a, b}, {h1, h2}, {o}}
+
+	if nw.IsFullyConnected() {
+		t.Fatal("expected network to not be fully connected")
+	}
+
+	nw.MustForwardPass([]float64{1, 2})
+	dLossDValue := a.dLossDValue
+	if err := nw.BackwardPass([]float64{0}); err == nil {
+		t.Fatal("expected an error back propagating through a network which is not fully connected")
+	}
+	if o.dLossDValue != 0 || a.dLossDValue != dLossDValue {
+		t.Fatal("expected a failed backward pass to leave the network unchanged")
+	}
+
+	h2.Connections[0].To, h2.Connections[1].To = a, b
+	o.Connections[0].To, o.Connections[1].To = h1, h2
+	if !nw.IsFullyConnected() {
+		t.Fatal("expected network to be fully connected")
+	}
+}
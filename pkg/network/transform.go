@@ -0,0 +1,274 @@
+package network
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// TransformKind identifies the operation performed by a TransformStep.
+type TransformKind string
+
+const (
+	// TransformStandardize computes (x - Mean) / Std.
+	TransformStandardize TransformKind = "standardize"
+	// TransformMinMax computes (x - Min) / (Max - Min), which is 0 if Min and
+	// Max are equal.
+	TransformMinMax TransformKind = "minMax"
+	// TransformOneHot computes 1 if x equals Category and 0 otherwise.
+	TransformOneHot TransformKind = "oneHot"
+	// TransformLog computes the natural logarithm of x + Offset.
+	TransformLog TransformKind = "log"
+	// TransformClip clamps x to [Min, Max].
+	TransformClip TransformKind = "clip"
+)
+
+// TransformStep is a single operation of an InputTransform. Only the fields
+// used by its Kind are meaningful.
+type TransformStep struct {
+	Kind     TransformKind `json:"kind"`
+	Mean     float64       `json:"mean,omitempty"`
+	Std      float64       `json:"std,omitempty"`
+	Min      float64       `json:"min,omitempty"`
+	Max      float64       `json:"max,omitempty"`
+	Category float64       `json:"category,omitempty"`
+	Offset   float64       `json:"offset,omitempty"`
+}
+
+// Apply returns the result of s on x.
+func (s TransformStep) Apply(x float64) (float64, error) {
+	switch s.Kind {
+	case TransformStandardize:
+		return (x - s.Mean) / s.Std, nil
+	case TransformMinMax:
+		if s.Max == s.Min {
+			return 0, nil
+		}
+		return (x - s.Min) / (s.Max - s.Min), nil
+	case TransformOneHot:
+		if x == s.Category {
+			return 1, nil
+		}
+		return 0, nil
+	case TransformLog:
+		if x+s.Offset <= 0 {
+			return 0, fmt.Errorf("can't take the log of non-positive value %v", x+s.Offset)
+		}
+		return math.Log(x + s.Offset), nil
+	case TransformClip:
+		return math.Max(s.Min, math.Min(s.Max, x)), nil
+	default:
+		return 0, fmt.Errorf("unknown transform kind %q", s.Kind)
+	}
+}
+
+// validate checks that s is of a known kind with finite parameters that make
+// sense for it.
+func (s TransformStep) validate() error {
+	for _, p := range []struct {
+		name  string
+		value float64
+	}{{"mean", s.Mean}, {"std", s.Std}, {"min", s.Min}, {"max", s.Max}, {"category", s.Category}, {"offset", s.Offset}} {
+		if math.IsNaN(p.value) || math.IsInf(p.value, 0) {
+			return fmt.Errorf("%v (%v) of %v must be finite", p.name, p.value, s.Kind)
+		}
+	}
+
+	switch s.Kind {
+	case TransformStandardize:
+		if !(s.Std > 0) {
+			return fmt.Errorf("standard deviation of %v must be positive", s.Kind)
+		}
+	case TransformMinMax, TransformClip:
+		if s.Min > s.Max {
+			return fmt.Errorf("min (%v) of %v must not exceed max (%v)", s.Min, s.Kind, s.Max)
+		}
+	case TransformOneHot, TransformLog:
+	default:
+		return fmt.Errorf("unknown transform kind %q", s.Kind)
+	}
+	return nil
+}
+
+// InputTransform computes the value of an input neuron from the raw input
+// given to a prediction, by applying Steps in order to the raw value at index
+// Source. An InputTransform without Steps passes the raw value through.
+type InputTransform struct {
+	Source int             `json:"source"`
+	Steps  []TransformStep `json:"steps,omitempty"`
+}
+
+// Apply returns the value of an input neuron with transform t, given the raw
+// input to a prediction.
+func (t InputTransform) Apply(raw []float64) (float64, error) {
+	if t.Source < 0 || t.Source >= len(raw) {
+		return 0, fmt.Errorf("source (%v) is out of range for %v raw inputs", t.Source, len(raw))
+	}
+	x := raw[t.Source]
+	for si, s := range t.Steps {
+		var err error
+		if x, err = s.Apply(x); err != nil {
+			return 0, fmt.Errorf("step %v: %w", si, err)
+		}
+	}
+	return x, nil
+}
+
+// SetInputTransforms sets the InputTransform of every neuron in nw's input
+// layer, index-wise, so that every prediction made by nw preprocesses its
+// input the same way its training data was. Training itself, via ForwardPass,
+// is unaffected, so training data must be transformed before being trained on.
+//
+// Several input neurons may share a Source, as is the case when a raw input is
+// one-hot encoded, so the raw input given to a prediction may be smaller than
+// the input layer. Passing nil removes every InputTransform.
+func (nw Network) SetInputTransforms(ts []InputTransform) error {
+	if len(nw) == 0 {
+		return errors.New("cannot set input transforms: network has no layers")
+	}
+	fl := nw.FirstLayer()
+	if ts == nil {
+		for _, n := range fl {
+			n.transform = nil
+		}
+		return nil
+	}
+	if len(ts) != len(fl) {
+		return fmt.Errorf("invalid number of input transforms provided (%v), does not match number of neurons in input layer (%v)", len(ts), len(fl))
+	}
+
+	for i, t := range ts {
+		if t.Source < 0 {
+			return fmt.Errorf("source (%v) of input transform %v must not be negative", t.Source, i)
+		}
+		for si, s := range t.Steps {
+			if err := s.validate(); err != nil {
+				return fmt.Errorf("step %v of input transform %v: %w", si, i, err)
+			}
+		}
+	}
+
+	for i := range ts {
+		t := ts[i]
+		t.Steps = append([]TransformStep(nil), t.Steps...)
+		fl[i].transform = &t
+	}
+	return nil
+}
+
+// checkDecodedInputTransforms validates the input transforms a translator
+// decoded directly onto the neurons of nw, as SetInputTransforms validates
+// those set by hand. Only neurons of the input layer may have one.
+func (nw Network) checkDecodedInputTransforms() error {
+	for li := 1; li < len(nw); li++ {
+		for ni, n := range nw[li] {
+			if n.transform != nil {
+				return fmt.Errorf("neuron %v of layer %v has an input transform but is not an input neuron", ni, li)
+			}
+		}
+	}
+	if len(nw) == 0 {
+		return nil
+	}
+	return nw.SetInputTransforms(nw.InputTransforms())
+}
+
+// MustSetInputTransforms calls SetInputTransforms but panics if an error is
+// encountered.
+func (nw Network) MustSetInputTransforms(ts []InputTransform) {
+	err := nw.SetInputTransforms(ts)
+	if err != nil {
+		panic(errors.Wrap(err, "must set input transforms"))
+	}
+}
+
+// InputTransforms returns the InputTransform of every neuron in nw's input
+// layer, or nil if nw has none.
+func (nw Network) InputTransforms() []InputTransform {
+	if !nw.hasInputTransforms() {
+		return nil
+	}
+	fl := nw.FirstLayer()
+	ts := make([]InputTransform, len(fl))
+	for i, n := range fl {
+		if n.transform == nil {
+			ts[i] = InputTransform{Source: i}
+			continue
+		}
+		ts[i] = *n.transform
+		ts[i].Steps = append([]TransformStep(nil), n.transform.Steps...)
+	}
+	return ts
+}
+
+// RawInputs returns the number of values a prediction made by nw expects. It is
+// the size of the input layer unless nw has input transforms.
+func (nw Network) RawInputs() int {
+	if len(nw) == 0 {
+		return 0
+	}
+	if !nw.hasInputTransforms() {
+		return len(nw.FirstLayer())
+	}
+	size := 0
+	for i, n := range nw.FirstLayer() {
+		source := i
+		if n.transform != nil {
+			source = n.transform.Source
+		}
+		if source+1 > size {
+			size = source + 1
+		}
+	}
+	return size
+}
+
+// TransformInput applies the input transforms of nw to raw, returning the
+// values to feed into nw's input layer. If nw has no input transforms, raw is
+// returned as is.
+func (nw Network) TransformInput(raw []float64) ([]float64, error) {
+	if len(raw) != nw.RawInputs() {
+		return nil, fmt.Errorf("invalid number of values provided (%v), does not match number of raw inputs (%v)", len(raw), nw.RawInputs())
+	}
+	if !nw.hasInputTransforms() {
+		return raw, nil
+	}
+
+	fl := nw.FirstLayer()
+	input := make([]float64, len(fl))
+	for i, n := range fl {
+		if n.transform == nil {
+			input[i] = raw[i]
+			continue
+		}
+		x, err := n.transform.Apply(raw)
+		if err != nil {
+			return nil, fmt.Errorf("input transform %v: %w", i, err)
+		}
+		input[i] = x
+	}
+	return input, nil
+}
+
+// MustTransformInput calls TransformInput but panics if an error is
+// encountered.
+func (nw Network) MustTransformInput(raw []float64) []float64 {
+	input, err := nw.TransformInput(raw)
+	if err != nil {
+		panic(errors.Wrap(err, "must transform input"))
+	}
+	return input
+}
+
+func (nw Network) hasInputTransforms() bool {
+	if len(nw) == 0 {
+		return false
+	}
+	for _, n := range nw.FirstLayer() {
+		if n.transform != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package network
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+func Test_TransformInput(t *testing.T) {
+	// The 4 input neurons are computed from 2 raw inputs: the first
+	// standardized, and the second one-hot encoded into 3 categories.
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{4, 3, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.MustSetInputTransforms([]InputTransform{
+		{Source: 0, Steps: []TransformStep{{Kind: TransformClip, Min: -10, Max: 10}, {Kind: TransformStandardize, Mean: 2, Std: 4}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 0}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 1}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 2}}},
+	})
+
+	if nw.RawInputs() != 2 {
+		t.Fatalf("expected 2 raw inputs, got %v", nw.RawInputs())
+	}
+
+	input := nw.MustTransformInput([]float64{50, 1})
+	if expected := []float64{2, 0, 1, 0}; !reflect.DeepEqual(input, expected) {
+		t.Fatalf("expected %v, got %v", expected, input)
+	}
+
+	if _, err := nw.TransformInput([]float64{1, 2, 3, 4}); err == nil {
+		t.Fatalf("expected error for input of the size of the input layer")
+	}
+
+	// Predictions apply the transforms to their raw input.
+	vs := nw.MustPredictVector([]float64{-2, 2})
+	nw.MustSetInputTransforms(nil)
+	expected := nw.MustPredictVector([]float64{-1, 0, 0, 1})
+	if !reflect.DeepEqual(vs, expected) {
+		t.Fatalf("expected %v, got %v", expected, vs)
+	}
+}
+
+func Test_TransformStep_Apply(t *testing.T) {
+	tests := []struct {
+		step     TransformStep
+		x        float64
+		expected float64
+	}{
+		{step: TransformStep{Kind: TransformStandardize, Mean: 1, Std: 2}, x: 5, expected: 2},
+		{step: TransformStep{Kind: TransformMinMax, Min: 2, Max: 6}, x: 3, expected: 0.25},
+		{step: TransformStep{Kind: TransformMinMax, Min: 2, Max: 2}, x: 3, expected: 0},
+		{step: TransformStep{Kind: TransformOneHot, Category: 3}, x: 3, expected: 1},
+		{step: TransformStep{Kind: TransformOneHot, Category: 3}, x: 4, expected: 0},
+		{step: TransformStep{Kind: TransformLog, Offset: 1}, x: math.E - 1, expected: 1},
+		{step: TransformStep{Kind: TransformClip, Min: -1, Max: 1}, x: -3, expected: -1},
+	}
+	for _, test := range tests {
+		v, err := test.step.Apply(test.x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != test.expected {
+			t.Fatalf("%v of %v: expected %v, got %v", test.step.Kind, test.x, test.expected, v)
+		}
+	}
+
+	if _, err := (TransformStep{Kind: TransformLog}).Apply(0); err == nil {
+		t.Fatalf("expected error taking the log of 0")
+	}
+}
+
+func Test_SetInputTransforms_Invalid(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{4, 3, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.MustSetInputTransforms([]InputTransform{
+		{Source: 0, Steps: []TransformStep{{Kind: TransformClip, Min: -10, Max: 10}, {Kind: TransformStandardize, Mean: 2, Std: 4}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 0}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 1}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 2}}},
+	})
+	before := nw.InputTransforms()
+
+	invalid := [][]InputTransform{
+		{{}, {}, {}},
+		{{Source: -1}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: "unknown"}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformStandardize}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformClip, Min: 1, Max: 0}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformStandardize, Mean: math.NaN(), Std: 1}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformMinMax, Min: math.Inf(-1), Max: 1}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformOneHot, Category: math.NaN()}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformLog, Offset: math.Inf(1)}}}, {}, {}, {}},
+		{{Steps: []TransformStep{{Kind: TransformLog, Std: math.NaN()}}}, {}, {}, {}},
+	}
+	for _, ts := range invalid {
+		if err := nw.SetInputTransforms(ts); err == nil {
+			t.Fatalf("expected error for %v", ts)
+		}
+	}
+
+	if !reflect.DeepEqual(nw.InputTransforms(), before) {
+		t.Fatalf("input transforms changed despite errors")
+	}
+}
+
+func Test_InputTransforms_AreTranslated(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{4, 3, 2},
+		OutputLabels:           []string{"a", "b"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	nw.MustSetInputTransforms([]InputTransform{
+		{Source: 0, Steps: []TransformStep{{Kind: TransformClip, Min: -10, Max: 10}, {Kind: TransformStandardize, Mean: 2, Std: 4}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 0}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 1}}},
+		{Source: 1, Steps: []TransformStep{{Kind: TransformOneHot, Category: 2}}},
+	})
+
+	translators := map[string]Translator{
+		"json":         NewJsonTranslator(),
+		"compact json": NewCompactJsonTranslator(),
+		"proto":        NewProtoTranslator(),
+		"container":    NewContainerTranslator(NewProtoTranslator()),
+	}
+	for name, tr := range translators {
+		t.Run(name, func(t *testing.T) {
+			nw2 := tr.MustDeserialize(tr.MustSerialize(nw))
+
+			if !reflect.DeepEqual(nw2.InputTransforms(), nw.InputTransforms()) {
+				t.Fatalf("expected %v, got %v", nw.InputTransforms(), nw2.InputTransforms())
+			}
+			if !reflect.DeepEqual(nw2.MustPredictVector([]float64{3, 0}), nw.MustPredictVector([]float64{3, 0})) {
+				t.Fatalf("predictions differ after translation")
+			}
+		})
+	}
+
+	t.Run("npz", func(t *testing.T) {
+		var b bytes.Buffer
+		nw.MustWriteNPZ(&b)
+
+		nw2 := MustFrom(Spec{NeuronMap: []int{4, 3, 2}, OutputLabels: []string{"a", "b"}, ActivationFunctionName: activationfunction.NameSigmoid})
+		nw2.MustReadNPZ(bytes.NewReader(b.Bytes()), int64(b.Len()))
+
+		if !reflect.DeepEqual(nw2.InputTransforms(), nw.InputTransforms()) {
+			t.Fatalf("expected %v, got %v", nw.InputTransforms(), nw2.InputTransforms())
+		}
+	})
+
+	if _, err := NewOnnxTranslator().Serialize(nw); err == nil {
+		t.Fatalf("expected onnx export of input transforms to fail")
+	}
+}
+
+func Test_InputTransforms_AreValidatedWhenTranslated(t *testing.T) {
+	translators := map[string]Translator{
+		"json":  NewJsonTranslator(),
+		"proto": NewProtoTranslator(),
+	}
+	for name, tr := range translators {
+		t.Run(name, func(t *testing.T) {
+			nw := MustFrom(Spec{
+				NeuronMap:              []int{2, 2},
+				OutputLabels:           []string{"a", "b"},
+				ActivationFunctionName: activationfunction.NameSigmoid,
+			})
+			nw[0][0].transform = &InputTransform{Source: 0, Steps: []TransformStep{{Kind: TransformStandardize}}}
+			if _, err := tr.Deserialize(tr.MustSerialize(nw)); err == nil {
+				t.Fatalf("expected an error for an invalid input transform")
+			}
+
+			nw[0][0].transform = nil
+			nw[1][0].transform = &InputTransform{Source: 0}
+			if _, err := tr.Deserialize(tr.MustSerialize(nw)); err == nil {
+				t.Fatalf("expected an error for an input transform on an output neuron")
+			}
+		})
+	}
+}
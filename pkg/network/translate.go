@@ -2,7 +2,6 @@ package network
 
 import (
 	"bytes"
-	"compress/gzip"
 	"encoding/base64"
 	"github.com/pkg/errors"
 	"io"
@@ -19,50 +18,58 @@ type (
 		MustDeserialize([]byte) Network
 	}
 
+	// Encoder is the streaming counterpart to Serializer. It writes a Network
+	// to an io.Writer so that it does not have to be held in memory in its
+	// entirety, once per TranslatorOption, as it is serialized.
+	Encoder interface {
+		Encode(io.Writer, Network) error
+		MustEncode(io.Writer, Network)
+	}
+
+	// Decoder is the streaming counterpart to Deserializer. It reads a Network
+	// from an io.Reader.
+	Decoder interface {
+		Decode(io.Reader) (Network, error)
+		MustDecode(io.Reader) Network
+	}
+
 	Translator interface {
 		Serializer
 		Deserializer
+		Encoder
+		Decoder
 	}
 
+	// TranslatorOption transforms the bytes produced by a Translator when
+	// serializing, and reverses that transformation when deserializing.
+	// Options are applied in the order they are provided to a Translator when
+	// serializing, and in reverse order when deserializing.
+	//
+	// Serialize and Deserialize operate on whole byte slices. NewWriter and
+	// NewReader are their streaming equivalents, and are used by Encode and
+	// Decode. If either is nil, a buffered adapter around Serialize or
+	// Deserialize is used in its place.
 	TranslatorOption struct {
+		// Name identifies the transformation, such as "base64" or "gzip". It
+		// is recorded in the header written by the container translator.
+		Name string
+
 		Serialize   func(bs []byte) ([]byte, error)
 		Deserialize func(bs []byte) ([]byte, error)
-	}
-)
 
-func WithCompression() TranslatorOption {
-	return TranslatorOption{
-		Serialize: func(bs []byte) ([]byte, error) {
-			var b bytes.Buffer
-			gz := gzip.NewWriter(&b)
-			if _, err := gz.Write(bs); err != nil {
-				return nil, errors.Wrap(err, "gzip write")
-			}
-			if err := gz.Close(); err != nil {
-				return nil, errors.Wrap(err, "gzip close")
-			}
-			bs = b.Bytes()
-			return bs, nil
-		},
-		Deserialize: func(bs []byte) ([]byte, error) {
-			gz, err := gzip.NewReader(bytes.NewBuffer(bs))
-			if err != nil {
-				return nil, errors.Wrap(err, "gzip new reader")
-			}
-			bs, err = io.ReadAll(gz)
-			if err != nil {
-				return nil, errors.Wrap(err, "gzip read")
-			}
-			if err := gz.Close(); err != nil {
-				return nil, errors.Wrap(err, "gzip close")
-			}
-			return bs, nil
-		},
+		// NewWriter returns a writer which transforms everything written to it
+		// before writing it to w. Closing the returned writer must flush any
+		// buffered data to w, but must not close w.
+		NewWriter func(w io.Writer) (io.WriteCloser, error)
+		// NewReader returns a reader which reverses the transformation of
+		// everything read from r.
+		NewReader func(r io.Reader) (io.ReadCloser, error)
 	}
-}
+)
 
 func WithBase64() TranslatorOption {
 	return TranslatorOption{
+		Name: "base64",
 		Serialize: func(bs []byte) ([]byte, error) {
 			bs = []byte(base64.StdEncoding.EncodeToString(bs))
 			return bs, nil
@@ -75,5 +82,140 @@ func WithBase64() TranslatorOption {
 			}
 			return bs, nil
 		},
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return base64.NewEncoder(base64.StdEncoding, w), nil
+		},
+		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+			return io.NopCloser(base64.NewDecoder(base64.StdEncoding, r)), nil
+		},
+	}
+}
+
+// checkFullyConnected returns an error unless nw is fully connected, as
+// translators and exports only store the weights of fully connected layers.
+func checkFullyConnected(nw Network) error {
+	if !nw.IsFullyConnected() {
+		return errors.New("only fully connected networks are supported, see IsFullyConnected")
+	}
+	return nil
+}
+
+// serialize applies every option in opts to bs in order.
+func serialize(bs []byte, opts []TranslatorOption) ([]byte, error) {
+	var err error
+	for i, opt := range opts {
+		bs, err = opt.Serialize(bs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "translator serialize option %v", i)
+		}
+	}
+	return bs, nil
+}
+
+// deserialize reverses every option in opts on bs in reverse order.
+func deserialize(bs []byte, opts []TranslatorOption) ([]byte, error) {
+	var err error
+	for i := len(opts) - 1; i >= 0; i-- {
+		bs, err = opts[i].Deserialize(bs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "translator deserialize option %v", i)
+		}
+	}
+	return bs, nil
+}
+
+// encode calls enc with a writer that applies every option in opts, in order,
+// to everything written to it before it reaches w. Every writer in the chain
+// is closed, innermost first, once enc returns.
+func encode(w io.Writer, opts []TranslatorOption, enc func(io.Writer) error) error {
+	wcs := make([]io.WriteCloser, len(opts))
+	for i := len(opts) - 1; i >= 0; i-- {
+		wc, err := opts[i].writer(w)
+		if err != nil {
+			return errors.Wrapf(err, "translator encode option %v", i)
+		}
+		wcs[i] = wc
+		w = wc
+	}
+
+	if err := enc(w); err != nil {
+		return err
+	}
+
+	for i, wc := range wcs {
+		if err := wc.Close(); err != nil {
+			return errors.Wrapf(err, "translator encode option %v close", i)
+		}
+	}
+	return nil
+}
+
+// decode calls dec with a reader that reverses every option in opts, in reverse
+// order, on everything read from r.
+func decode(r io.Reader, opts []TranslatorOption, dec func(io.Reader) (Network, error)) (Network, error) {
+	var rcs []io.ReadCloser
+	defer func() {
+		for _, rc := range rcs {
+			_ = rc.Close()
+		}
+	}()
+
+	for i := len(opts) - 1; i >= 0; i-- {
+		rc, err := opts[i].reader(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "translator decode option %v", i)
+		}
+		rcs = append(rcs, rc)
+		r = rc
+	}
+
+	return dec(r)
+}
+
+// writer returns opt.NewWriter(w), or if NewWriter is nil, a writer which
+// buffers everything written to it and passes it through opt.Serialize when it
+// is closed.
+func (opt TranslatorOption) writer(w io.Writer) (io.WriteCloser, error) {
+	if opt.NewWriter != nil {
+		return opt.NewWriter(w)
+	}
+	return &bufferedWriter{w: w, serialize: opt.Serialize}, nil
+}
+
+// reader returns opt.NewReader(r), or if NewReader is nil, a reader over the
+// result of passing everything in r through opt.Deserialize.
+func (opt TranslatorOption) reader(r io.Reader) (io.ReadCloser, error) {
+	if opt.NewReader != nil {
+		return opt.NewReader(r)
+	}
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading")
+	}
+	bs, err = opt.Deserialize(bs)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(bs)), nil
+}
+
+// bufferedWriter adapts a TranslatorOption's Serialize function, which operates
+// on whole byte slices, to an io.WriteCloser.
+type bufferedWriter struct {
+	w         io.Writer
+	serialize func([]byte) ([]byte, error)
+	b         bytes.Buffer
+}
+
+func (bw *bufferedWriter) Write(p []byte) (int, error) {
+	return bw.b.Write(p)
+}
+
+func (bw *bufferedWriter) Close() error {
+	bs, err := bw.serialize(bw.b.Bytes())
+	if err != nil {
+		return err
 	}
+	_, err = bw.w.Write(bs)
+	return err
 }
@@ -0,0 +1,112 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+)
+
+// withReversal is a TranslatorOption without streaming support, used to
+// exercise the buffered adapters used by Encode and Decode.
+func withReversal() TranslatorOption {
+	reverse := func(bs []byte) ([]byte, error) {
+		r := make([]byte, len(bs))
+		for i := range bs {
+			r[len(bs)-1-i] = bs[i]
+		}
+		return r, nil
+	}
+	return TranslatorOption{Serialize: reverse, Deserialize: reverse}
+}
+
+func Test_EncodeAndDecodeMatchSerializeAndDeserialize(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	newTranslators := map[string]func(...TranslatorOption) Translator{
+		"json":  NewJsonTranslator,
+		"proto": NewProtoTranslator,
+	}
+	optss := map[string][]TranslatorOption{
+		"none":                {},
+		"compression":         {WithCompression()},
+		"compression, base64": {WithCompression(), WithBase64()},
+		"base64, compression": {WithBase64(), WithCompression()},
+		"reversal, base64":    {withReversal(), WithBase64()},
+	}
+
+	for tn, newTranslator := range newTranslators {
+		for on, opts := range optss {
+			tr := newTranslator(opts...)
+			t.Run(tn+" with "+on, func(t *testing.T) {
+				s := tr.MustSerialize(nw)
+
+				var b bytes.Buffer
+				tr.MustEncode(&b, nw)
+				if !bytes.Equal(s, b.Bytes()) {
+					t.Fatalf("encoding does not match serialization")
+				}
+
+				if err := nw.Equals(tr.MustDeserialize(s)); err != nil {
+					t.Fatalf("deserialized network does not equal original: %v", err)
+				}
+
+				// NOTE: A pipe ensures the decoder only ever sees the stream,
+				// never the whole encoding at once.
+				pr, pw := io.Pipe()
+				go func() {
+					_ = pw.CloseWithError(tr.Encode(pw, nw))
+				}()
+				if err := nw.Equals(tr.MustDecode(pr)); err != nil {
+					t.Fatalf("decoded network does not equal original: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func Test_NotFullyConnected_IsRejected(t *testing.T) {
+	nw := MustFrom(Spec{
+		NeuronMap:              []int{3, 4, 2},
+		InputLabels:            []string{"a", "b", "c"},
+		OutputLabels:           []string{"x", "y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	// Neuron 0 of layer 2 reads neuron 1 of layer 1 twice, so it has as many
+	// connections as there are neurons in layer 1 but isn't fully connected.
+	nw[2][0].Connections[0].To = nw[1][1]
+
+	translators := map[string]Translator{
+		"json":         NewJsonTranslator(),
+		"compact json": NewCompactJsonTranslator(),
+		"proto":        NewProtoTranslator(),
+		"gob":          NewGobTranslator(),
+		"onnx":         NewOnnxTranslator(),
+		"container":    NewContainerTranslator(NewProtoTranslator()),
+	}
+	for name, tr := range translators {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tr.Serialize(nw); err == nil {
+				t.Fatalf("expected an error serializing a network which is not fully connected")
+			}
+			var b bytes.Buffer
+			if err := tr.Encode(&b, nw); err == nil {
+				t.Fatalf("expected an error encoding a network which is not fully connected")
+			}
+			if b.Len() != 0 {
+				t.Fatalf("expected nothing to be written, got %v bytes", b.Len())
+			}
+		})
+	}
+
+	var b bytes.Buffer
+	if err := nw.WriteNPZ(&b); err == nil {
+		t.Fatalf("expected an error writing a network which is not fully connected as npz")
+	}
+}
@@ -0,0 +1,128 @@
+package optimize
+
+import (
+	"math"
+)
+
+const (
+	// sufficientDecrease is the constant of the sufficient decrease, or
+	// Armijo, condition every accepted step must satisfy.
+	sufficientDecrease = 1e-4
+	// maxLineSearchEvaluations is the number of points a line search may
+	// evaluate before giving up.
+	maxLineSearchEvaluations = 30
+)
+
+// objective returns the loss at x and its gradient.
+type objective func(x []float64) (float64, []float64, error)
+
+// point is a point along the line of a line search.
+type point struct {
+	step float64
+	x, g []float64
+	f    float64
+	// slope is the derivative of the loss along the line at the point.
+	slope float64
+}
+
+// lineSearch searches along the direction d from x, whose loss is f and
+// gradient is g, for a step satisfying the strong Wolfe conditions with
+// curvature constant c2, starting with a step of step. It follows algorithms 3.5
+// and 3.6 of Nocedal and Wright's Numerical Optimization: the step grows until
+// it brackets an acceptable step, which is then narrowed down on via quadratic
+// interpolation.
+//
+// If no step satisfies the conditions within maxLineSearchEvaluations
+// evaluations, the best step which decreased the loss sufficiently is returned
+// instead, and false is returned only if there is no such step.
+func lineSearch(fn objective, x []float64, f float64, g, d []float64, step, c2 float64) (point, bool, error) {
+	origin := point{x: x, g: g, f: f, slope: dot(g, d)}
+	at := func(step float64) (point, error) {
+		p := point{step: step, x: make([]float64, len(x))}
+		for i := range x {
+			p.x[i] = x[i] + step*d[i]
+		}
+		var err error
+		p.f, p.g, err = fn(p.x)
+		if err != nil {
+			return point{}, err
+		}
+		p.slope = dot(p.g, d)
+		return p, nil
+	}
+	armijo := func(p point) bool {
+		return p.f <= origin.f+sufficientDecrease*p.step*origin.slope
+	}
+	wolfe := func(p point) bool {
+		return math.Abs(p.slope) <= -c2*origin.slope
+	}
+
+	best := origin
+	consider := func(p point) {
+		if armijo(p) && p.f < best.f {
+			best = p
+		}
+	}
+
+	// zoom narrows down on an acceptable step between lo, whose loss is the
+	// lowest seen satisfying sufficient decrease, and hi.
+	zoom := func(lo, hi point, evaluations int) (point, bool, error) {
+		for ; evaluations < maxLineSearchEvaluations; evaluations++ {
+			step := (lo.step + hi.step) / 2
+			if !math.IsInf(hi.f, 1) {
+				// NOTE: The minimum of the quadratic through the loss and slope
+				// at lo and the loss at hi, kept away from either end so the
+				// bracket always shrinks.
+				width := hi.step - lo.step
+				denominator := 2 * (hi.f - lo.f - lo.slope*width)
+				if denominator != 0 {
+					step = lo.step - lo.slope*width*width/denominator
+				}
+				a, b := math.Min(lo.step, hi.step), math.Max(lo.step, hi.step)
+				margin := 0.1 * (b - a)
+				if math.IsNaN(step) || step < a+margin || step > b-margin {
+					step = (lo.step + hi.step) / 2
+				}
+			}
+
+			p, err := at(step)
+			if err != nil {
+				return point{}, false, err
+			}
+			consider(p)
+			if !armijo(p) || p.f >= lo.f {
+				hi = p
+				continue
+			}
+			if wolfe(p) {
+				return p, true, nil
+			}
+			if p.slope*(hi.step-lo.step) >= 0 {
+				hi = lo
+			}
+			lo = p
+		}
+		return best, best.step > 0, nil
+	}
+
+	prev := origin
+	for evaluations := 0; evaluations < maxLineSearchEvaluations; evaluations++ {
+		p, err := at(step)
+		if err != nil {
+			return point{}, false, err
+		}
+		consider(p)
+		if !armijo(p) || (evaluations > 0 && p.f >= prev.f) {
+			return zoom(prev, p, evaluations+1)
+		}
+		if wolfe(p) {
+			return p, true, nil
+		}
+		if p.slope >= 0 {
+			return zoom(p, prev, evaluations+1)
+		}
+		prev = p
+		step *= 2
+	}
+	return best, best.step > 0, nil
+}
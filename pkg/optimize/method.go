@@ -0,0 +1,138 @@
+package optimize
+
+// method chooses the direction of every step of an optimization.
+type method interface {
+	// direction returns the direction to search along from a point whose
+	// gradient is g.
+	direction(g []float64) []float64
+	// update records that a step of s was taken, which changed the gradient by
+	// y.
+	update(s, y []float64)
+	// reset forgets every step taken, so that the next direction is that of
+	// steepest descent.
+	reset()
+	// curvature is the constant of the curvature condition the line searches
+	// of the method must satisfy.
+	curvature() float64
+}
+
+// lbfgs chooses directions via limited memory BFGS.
+type lbfgs struct {
+	memory int
+	// s and y hold the most recent steps and changes in gradient, oldest
+	// first, and rho holds 1/(y·s) for each.
+	s, y [][]float64
+	rho  []float64
+}
+
+// direction returns -Hg, where H approximates the inverse Hessian of the loss,
+// via the L-BFGS two loop recursion.
+func (l *lbfgs) direction(g []float64) []float64 {
+	q := make([]float64, len(g))
+	for i := range g {
+		q[i] = -g[i]
+	}
+
+	k := len(l.s)
+	alpha := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		alpha[i] = l.rho[i] * dot(l.s[i], q)
+		axpy(-alpha[i], l.y[i], q)
+	}
+
+	// NOTE: The initial inverse Hessian is the identity scaled by the
+	// curvature along the most recent step, which gives directions a scale
+	// where a step of 1 is usually accepted.
+	if k > 0 {
+		scale := dot(l.s[k-1], l.y[k-1]) / dot(l.y[k-1], l.y[k-1])
+		for i := range q {
+			q[i] *= scale
+		}
+	}
+
+	for i := 0; i < k; i++ {
+		beta := l.rho[i] * dot(l.y[i], q)
+		axpy(alpha[i]-beta, l.s[i], q)
+	}
+	return q
+}
+
+// update remembers s and y, forgetting the oldest step once memory steps are
+// remembered. Steps along which the loss wasn't convex are skipped, since they
+// would make H indefinite.
+func (l *lbfgs) update(s, y []float64) {
+	sy := dot(s, y)
+	if sy <= 1e-10*norm(s)*norm(y) {
+		return
+	}
+	if len(l.s) == l.memory {
+		l.s, l.y, l.rho = l.s[1:], l.y[1:], l.rho[1:]
+	}
+	l.s = append(l.s, s)
+	l.y = append(l.y, y)
+	l.rho = append(l.rho, 1/sy)
+}
+
+func (l *lbfgs) reset() {
+	l.s, l.y, l.rho = nil, nil, nil
+}
+
+func (l *lbfgs) curvature() float64 {
+	return 0.9
+}
+
+// conjugateGradient chooses directions via nonlinear conjugate gradient with
+// the Polak-Ribière+ update.
+type conjugateGradient struct {
+	// restart is the number of steps after which the direction is reset to
+	// that of steepest descent, as conjugacy is lost.
+	restart int
+	steps   int
+	// g and d are the gradient and direction of the last step.
+	g, d []float64
+}
+
+func (cg *conjugateGradient) direction(g []float64) []float64 {
+	d := make([]float64, len(g))
+	for i := range g {
+		d[i] = -g[i]
+	}
+
+	if cg.d != nil && cg.steps < cg.restart {
+		// NOTE: Clamping beta to zero restarts whenever the new gradient has
+		// little in common with the last, which is what guarantees descent.
+		beta := 0.0
+		for i := range g {
+			beta += g[i] * (g[i] - cg.g[i])
+		}
+		beta /= dot(cg.g, cg.g)
+		if beta > 0 {
+			axpy(beta, cg.d, d)
+		}
+		cg.steps++
+	} else {
+		cg.steps = 0
+	}
+
+	cg.g, cg.d = g, d
+	return d
+}
+
+func (cg *conjugateGradient) update(_, _ []float64) {}
+
+func (cg *conjugateGradient) reset() {
+	cg.g, cg.d = nil, nil
+}
+
+// curvature is small, since conjugate gradient relies on line searches being
+// fairly exact for successive directions to be conjugate.
+func (cg *conjugateGradient) curvature() float64 {
+	return 0.1
+}
+
+// axpy adds a*x to y.
+func axpy(a float64, x, y []float64) {
+	for i := range x {
+		y[i] += a * x[i]
+	}
+}
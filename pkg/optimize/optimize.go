@@ -0,0 +1,366 @@
+// Package optimize trains networks via full batch, quasi second order
+// optimizers rather than the stochastic gradient descent of the trainer
+// package. Every weight and bias of a network is flattened into a single
+// parameter vector, and each step evaluates the loss and gradient of the network
+// across all of its training data, via ForwardPass and BackwardPass, then moves
+// the parameters along a search direction by a step found via line search.
+//
+// No learning rate or mini batch size needs tuning, and on small problems of a
+// few thousand data these optimizers typically converge in far fewer passes
+// over the data than stochastic gradient descent. Since every step evaluates
+// all the data, they scale poorly to large data sets.
+package optimize
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Method is an algorithm choosing the direction of every step.
+type Method int
+
+const (
+	// MethodLBFGS is limited memory BFGS, which approximates the inverse
+	// Hessian of the loss from the last Memory steps taken.
+	MethodLBFGS Method = iota
+	// MethodConjugateGradient is nonlinear conjugate gradient with the
+	// Polak-Ribière+ update, which needs less memory than L-BFGS.
+	MethodConjugateGradient
+)
+
+func (m Method) String() string {
+	switch m {
+	case MethodLBFGS:
+		return "L-BFGS"
+	case MethodConjugateGradient:
+		return "conjugate gradient"
+	default:
+		return fmt.Sprintf("Method(%d)", int(m))
+	}
+}
+
+// Configuration defines how an Optimizer trains a network.
+type Configuration struct {
+	Method Method
+	// MaxIterations is the maximum number of steps taken. Must be at least 1.
+	MaxIterations int
+	// GradientTolerance ends optimization once the L2 norm of the gradient
+	// of the loss is at most GradientTolerance.
+	GradientTolerance float64
+	// Tolerance ends optimization once a step decreases the loss by at most
+	// Tolerance relative to the loss, or absolutely when the loss is smaller
+	// than 1.
+	Tolerance float64
+	// Memory is the number of steps L-BFGS remembers. Values less than 1 are
+	// treated as 10.
+	Memory int
+	// Timeout ends optimization after this much time has passed. Setting to 0
+	// means there is no timeout.
+	Timeout time.Duration
+}
+
+func (c Configuration) validate() error {
+	if c.Method != MethodLBFGS && c.Method != MethodConjugateGradient {
+		return fmt.Errorf("unknown method %v", c.Method)
+	}
+	if c.MaxIterations < 1 {
+		return fmt.Errorf("max iterations (%v) must be at least 1", c.MaxIterations)
+	}
+	if c.GradientTolerance < 0 {
+		return fmt.Errorf("gradient tolerance (%v) must not be negative", c.GradientTolerance)
+	}
+	if c.Tolerance < 0 {
+		return fmt.Errorf("tolerance (%v) must not be negative", c.Tolerance)
+	}
+	return nil
+}
+
+// Stop is the reason optimization ended.
+type Stop int
+
+const (
+	// StopGradientTolerance means the gradient norm reached GradientTolerance.
+	StopGradientTolerance Stop = iota
+	// StopTolerance means a step decreased the loss by no more than Tolerance.
+	StopTolerance
+	// StopMaxIterations means MaxIterations steps were taken.
+	StopMaxIterations
+	// StopNoProgress means no step along even the steepest descent direction
+	// decreased the loss, which usually means the loss is minimized as
+	// precisely as floating point allows.
+	StopNoProgress
+	// StopTimedOut means Timeout was reached.
+	StopTimedOut
+)
+
+func (s Stop) String() string {
+	switch s {
+	case StopGradientTolerance:
+		return "reached gradient tolerance"
+	case StopTolerance:
+		return "reached tolerance"
+	case StopMaxIterations:
+		return "reached maximum iterations"
+	case StopNoProgress:
+		return "no progress possible"
+	case StopTimedOut:
+		return "timed out"
+	default:
+		return fmt.Sprintf("Stop(%d)", int(s))
+	}
+}
+
+// Result summarizes an optimization.
+type Result struct {
+	// Iterations is the number of steps taken.
+	Iterations int
+	// Evaluations is the number of times the loss and gradient were evaluated
+	// across all the data, including during line searches.
+	Evaluations int
+	// Loss is the final average loss across the data.
+	Loss float64
+	// GradientNorm is the L2 norm of the final gradient of Loss.
+	GradientNorm float64
+	Stop         Stop
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%v after %v iterations and %v evaluations: loss %v, gradient norm %v", r.Stop, r.Iterations, r.Evaluations, r.Loss, r.GradientNorm)
+}
+
+// Optimizer trains networks on Data via the Method of its Configuration. Its
+// loss is the same as that of the trainer package, so the loss is the average
+// of network.Network.CalculateLoss across Data.
+type Optimizer struct {
+	Configuration
+	Data trainer.Data
+	Log  io.Writer
+}
+
+// New creates an Optimizer which trains networks on d. Providing nil for log
+// results in logs being written to stdout.
+func New(c Configuration, d trainer.Data, log io.Writer) Optimizer {
+	if log == nil {
+		log = os.Stdout
+	}
+	return Optimizer{
+		Configuration: c,
+		Data:          d,
+		Log:           log,
+	}
+}
+
+// ErrTimedOut is returned by Optimize when the Timeout of its Configuration is
+// reached.
+var ErrTimedOut = errors.New("optimization process timed out")
+
+// Optimize minimizes the loss of nw across Data, starting from its current
+// weights and biases, until GradientTolerance, Tolerance or MaxIterations is
+// reached, or no further progress is possible. nw is left with the parameters
+// of the last step taken, and the returned Result describes them. If Timeout is
+// reached, ErrTimedOut is returned along with the Result so far.
+//
+// Like training via the trainer package, nw must be fully connected.
+func (o *Optimizer) Optimize(nw network.Network) (Result, error) {
+	if err := o.Configuration.validate(); err != nil {
+		return Result{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if len(o.Data) == 0 {
+		return Result{}, errors.New("must provide data to optimize against")
+	}
+	if !nw.IsFullyConnected() {
+		return Result{}, errors.New("only fully connected networks can be optimized")
+	}
+	if o.Log == nil {
+		o.Log = os.Stdout
+	}
+
+	var deadline time.Time
+	if o.Configuration.Timeout > 0 {
+		deadline = time.Now().Add(o.Configuration.Timeout)
+	}
+
+	var r Result
+	evaluate := func(ps []float64) (float64, []float64, error) {
+		r.Evaluations++
+		return o.evaluate(nw, ps)
+	}
+
+	x := nw.Parameters()
+	f, g, err := evaluate(x)
+	if err != nil {
+		return r, err
+	}
+	if math.IsInf(f, 1) {
+		return r, errors.New("loss of network is not finite")
+	}
+
+	var m method
+	switch o.Configuration.Method {
+	case MethodLBFGS:
+		memory := o.Configuration.Memory
+		if memory < 1 {
+			memory = 10
+		}
+		m = &lbfgs{memory: memory}
+	case MethodConjugateGradient:
+		m = &conjugateGradient{restart: len(x)}
+	}
+	c2 := m.curvature()
+
+	_, _ = fmt.Fprintf(o.Log, "Starting %v optimization process...\n", o.Configuration.Method)
+
+	// NOTE: nw holds the parameters of whichever point was last evaluated,
+	// which during a line search needn't be the point accepted, so it is
+	// always left at x.
+	defer func() {
+		nw.MustSetParameters(x)
+	}()
+
+	fresh := true
+	prevStep, prevSlope := 0.0, 0.0
+	for {
+		r.Loss, r.GradientNorm = f, norm(g)
+		_, _ = fmt.Fprintf(o.Log, "Iteration %v: loss %.8f gradient norm %.8f\n", r.Iterations, r.Loss, r.GradientNorm)
+
+		if r.GradientNorm <= o.Configuration.GradientTolerance {
+			r.Stop = StopGradientTolerance
+			break
+		}
+		if r.Iterations >= o.Configuration.MaxIterations {
+			r.Stop = StopMaxIterations
+			break
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			r.Stop = StopTimedOut
+			return r, ErrTimedOut
+		}
+
+		d := m.direction(g)
+		slope := dot(g, d)
+		if slope >= 0 || math.IsNaN(slope) {
+			m.reset()
+			fresh = true
+			d = m.direction(g)
+			slope = dot(g, d)
+		}
+
+		// NOTE: Without history there is no sense of scale, so the first step is
+		// limited to moving the parameters a distance of 1. Conjugate gradient
+		// directions aren't scaled either, so each step starts at the length
+		// which would change the loss, to first order, as much as the last.
+		step := 1.0
+		switch {
+		case fresh:
+			step = math.Min(1, 1/r.GradientNorm)
+		case o.Configuration.Method == MethodConjugateGradient:
+			step = prevStep * prevSlope / slope
+		}
+
+		ls, ok, err := lineSearch(evaluate, x, f, g, d, step, c2)
+		if err != nil {
+			return r, err
+		}
+		if !ok && !fresh {
+			// The remembered curvature led nowhere, so forget it and try the
+			// steepest descent direction instead.
+			m.reset()
+			fresh = true
+			d = m.direction(g)
+			slope = dot(g, d)
+			ls, ok, err = lineSearch(evaluate, x, f, g, d, math.Min(1, 1/r.GradientNorm), c2)
+			if err != nil {
+				return r, err
+			}
+		}
+		if !ok {
+			r.Stop = StopNoProgress
+			break
+		}
+
+		s, y := make([]float64, len(x)), make([]float64, len(x))
+		for i := range x {
+			s[i] = ls.x[i] - x[i]
+			y[i] = ls.g[i] - g[i]
+		}
+		m.update(s, y)
+
+		decrease := f - ls.f
+		x, f, g = ls.x, ls.f, ls.g
+		fresh = false
+		prevStep, prevSlope = ls.step, slope
+		r.Iterations++
+
+		if decrease <= o.Configuration.Tolerance*math.Max(1, math.Abs(f)) {
+			r.Loss, r.GradientNorm = f, norm(g)
+			r.Stop = StopTolerance
+			break
+		}
+	}
+
+	_, _ = fmt.Fprintf(o.Log, "Optimization process ended: %v\n", r)
+	return r, nil
+}
+
+// MustOptimize calls Optimize but panics if an error is encountered.
+func (o *Optimizer) MustOptimize(nw network.Network) Result {
+	r, err := o.Optimize(nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must optimize"))
+	}
+	return r
+}
+
+// evaluate sets the parameters of nw to ps, then returns the average loss of nw
+// across Data and its gradient, laid out as ps. A loss or gradient which isn't
+// finite results in a loss of +Inf, so that line searches back away from it.
+func (o *Optimizer) evaluate(nw network.Network, ps []float64) (float64, []float64, error) {
+	if err := nw.SetParameters(ps); err != nil {
+		return 0, nil, err
+	}
+
+	nw.ResetFromBatch()
+	total := 0.0
+	for _, td := range o.Data {
+		nw.ResetFromPass()
+		if err := nw.ForwardPass(td.Data); err != nil {
+			return 0, nil, err
+		}
+		loss, err := nw.CalculateLoss(td.Truth)
+		if err != nil {
+			return 0, nil, err
+		}
+		total += loss
+		if err := nw.BackwardPass(td.Truth); err != nil {
+			return 0, nil, err
+		}
+		nw.RecordNudges()
+	}
+	g := nw.Gradients()
+
+	f := total / float64(len(o.Data))
+	if math.IsNaN(f) || math.IsInf(f, 0) || g.CheckFinite() != nil {
+		f = math.Inf(1)
+	}
+	return f, g.Flatten(), nil
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func norm(a []float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
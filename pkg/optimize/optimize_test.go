@@ -0,0 +1,151 @@
+package optimize
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// classes is the data of Test_NetworkConverges.
+var classes = trainer.Data{
+	{Data: []float64{1, 0, 0, 0}, Truth: []float64{1, 0, 0, 0}},
+	{Data: []float64{0, 1, 0, 0}, Truth: []float64{1, 0, 0, 0}},
+	{Data: []float64{0, 0, 1, 0}, Truth: []float64{0, 1, 0, 0}},
+	{Data: []float64{0, 0, 0, 1}, Truth: []float64{0, 1, 0, 0}},
+}
+
+func Test_Optimize(t *testing.T) {
+	for _, m := range []Method{MethodLBFGS, MethodConjugateGradient} {
+		nw := network.MustFrom(network.Spec{
+			NeuronMap:              []int{4, 4, 4, 4},
+			OutputLabels:           []string{"1", "2", "3", "4"},
+			ActivationFunctionName: activationfunction.NameSigmoid,
+		})
+
+		o := New(Configuration{
+			Method:            m,
+			MaxIterations:     1000,
+			GradientTolerance: 1e-5,
+		}, classes, io.Discard)
+		r, err := o.Optimize(nw)
+		if err != nil {
+			t.Fatalf("%v: %v", m, err)
+		}
+		if r.Loss > 0.01 {
+			t.Fatalf("%v: expected loss below 0.01, got %v", m, r)
+		}
+
+		// The network is left with the parameters the result describes.
+		loss, _, err := o.evaluate(nw, nw.Parameters())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loss != r.Loss {
+			t.Fatalf("%v: expected network to have loss %v, got %v", m, r.Loss, loss)
+		}
+	}
+}
+
+func Test_Optimize_Linear(t *testing.T) {
+	// y = 2a - b + 0.5 is fit exactly by a linear network, so its loss can be
+	// minimized to zero.
+	var d trainer.Data
+	for _, in := range [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {-1, 2}, {3, -2}} {
+		d = append(d, trainer.Datum{Data: in, Truth: []float64{2*in[0] - in[1] + 0.5}})
+	}
+
+	for _, m := range []Method{MethodLBFGS, MethodConjugateGradient} {
+		nw := network.MustFrom(network.Spec{
+			NeuronMap:              []int{2, 1},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		})
+
+		o := New(Configuration{
+			Method:            m,
+			MaxIterations:     100,
+			GradientTolerance: 1e-8,
+		}, d, io.Discard)
+		r := o.MustOptimize(nw)
+		if r.Stop != StopGradientTolerance && r.Stop != StopNoProgress {
+			t.Fatalf("%v: expected to converge, got %v", m, r)
+		}
+
+		ws := nw.ConnectionWeights()[1][0]
+		if b := nw.NeuronBiases()[1][0]; math.Abs(ws[0]-2) > 1e-4 || math.Abs(ws[1]+1) > 1e-4 || math.Abs(b-0.5) > 1e-4 {
+			t.Fatalf("%v: expected weights 2 and -1 and bias 0.5, got %v and %v", m, ws, b)
+		}
+	}
+}
+
+func Test_Optimize_Tolerance(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{4, 3, 4},
+		OutputLabels:           []string{"1", "2", "3", "4"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	o := New(Configuration{MaxIterations: 1000, Tolerance: 1e-3}, classes, io.Discard)
+	r := o.MustOptimize(nw)
+	if r.Stop != StopTolerance || r.Iterations >= 1000 {
+		t.Fatalf("expected to reach tolerance, got %v", r)
+	}
+
+	o = New(Configuration{MaxIterations: 3}, classes, io.Discard)
+	if r := o.MustOptimize(nw); r.Stop != StopMaxIterations || r.Iterations != 3 {
+		t.Fatalf("expected to reach maximum iterations, got %v", r)
+	}
+}
+
+func Test_Optimize_Invalid(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{4, 4},
+		OutputLabels:           []string{"1", "2", "3", "4"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+
+	for i, c := range []Configuration{
+		{},
+		{MaxIterations: 1, Method: Method(7)},
+		{MaxIterations: 1, Tolerance: -1},
+	} {
+		o := New(c, classes, io.Discard)
+		if _, err := o.Optimize(nw); err == nil {
+			t.Errorf("expected an error for configuration %v", i)
+		}
+	}
+
+	o := New(Configuration{MaxIterations: 1}, nil, io.Discard)
+	if _, err := o.Optimize(nw); err == nil {
+		t.Error("expected an error without data")
+	}
+}
+
+func Test_lineSearch(t *testing.T) {
+	// f(x) = (x0 - 3)^2 + 10 (x1 + 1)^2, minimized at (3, -1).
+	fn := func(x []float64) (float64, []float64, error) {
+		return (x[0]-3)*(x[0]-3) + 10*(x[1]+1)*(x[1]+1), []float64{2 * (x[0] - 3), 20 * (x[1] + 1)}, nil
+	}
+	x := []float64{0, 0}
+	f, g, _ := fn(x)
+	d := []float64{-g[0], -g[1]}
+
+	for _, step := range []float64{1e-4, 1, 100} {
+		p, ok, err := lineSearch(fn, x, f, g, d, step, 0.1)
+		if err != nil || !ok {
+			t.Fatalf("expected a step from %v, got %v (%v)", step, ok, err)
+		}
+		if p.f > f+sufficientDecrease*p.step*dot(g, d) || math.Abs(p.slope) > -0.1*dot(g, d) {
+			t.Fatalf("step %v from %v doesn't satisfy the strong Wolfe conditions", p.step, step)
+		}
+	}
+
+	// There is no descent along the gradient.
+	if _, ok, _ := lineSearch(fn, x, f, g, g, 1, 0.9); ok {
+		t.Fatal("expected no step along an ascent direction")
+	}
+}
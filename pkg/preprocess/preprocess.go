@@ -0,0 +1,264 @@
+// Package preprocess fits feature preprocessing, such as scaling and one-hot
+// encoding, on training data. A fitted Pipeline transforms the training data,
+// and is then stored on the Network being trained as its input transforms, so
+// that the translators persist it and every prediction applies it
+// automatically.
+package preprocess
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Step is a preprocessing operation applied to some columns of the raw data.
+// Steps are created via Standard, MinMax, OneHot, Log and Clip.
+type Step struct {
+	kind     network.TransformKind
+	columns  []int
+	min, max float64
+	offset   float64
+}
+
+// Standard scales each of columns to a mean of 0 and a standard deviation of 1.
+// A column whose values are all equal is only centered.
+func Standard(columns ...int) Step {
+	return Step{kind: network.TransformStandardize, columns: columns}
+}
+
+// MinMax scales each of columns so that its smallest value becomes 0 and its
+// largest becomes 1.
+func MinMax(columns ...int) Step {
+	return Step{kind: network.TransformMinMax, columns: columns}
+}
+
+// OneHot replaces each of columns, which should hold categories encoded as
+// numbers, with one input per distinct value, in ascending order. Values that
+// weren't seen when fitting set none of the inputs. OneHot must be the last
+// step applied to a column.
+func OneHot(columns ...int) Step {
+	return Step{kind: network.TransformOneHot, columns: columns}
+}
+
+// Log replaces each of columns with the natural logarithm of its value plus
+// offset, which is commonly 1 for counts that may be 0.
+func Log(offset float64, columns ...int) Step {
+	return Step{kind: network.TransformLog, columns: columns, offset: offset}
+}
+
+// Clip clamps each of columns to [min, max].
+func Clip(min, max float64, columns ...int) Step {
+	return Step{kind: network.TransformClip, columns: columns, min: min, max: max}
+}
+
+// Pipeline applies Steps to the columns of raw data, in the order the Steps
+// were given. Columns without any Steps are passed through unchanged.
+type Pipeline struct {
+	steps      []Step
+	transforms []network.InputTransform
+}
+
+// New returns an unfitted Pipeline of steps.
+func New(steps ...Step) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// Fit learns the parameters of every step of p, such as means and categories,
+// from the Data of d. Each step is fit on the values produced by the steps
+// before it.
+func (p *Pipeline) Fit(d trainer.Data) error {
+	if len(d) == 0 {
+		return errors.New("can't fit on empty data")
+	}
+	width := len(d[0].Data)
+	for i, td := range d {
+		if len(td.Data) != width {
+			return fmt.Errorf("datum %v has %v values, but datum 0 has %v", i, len(td.Data), width)
+		}
+	}
+	for si, s := range p.steps {
+		for _, c := range s.columns {
+			if c < 0 || c >= width {
+				return fmt.Errorf("column %v of step %v (%v) is out of range for %v columns", c, si, s.kind, width)
+			}
+		}
+		if s.kind == network.TransformClip && s.min > s.max {
+			return fmt.Errorf("min (%v) of step %v (%v) exceeds max (%v)", s.min, si, s.kind, s.max)
+		}
+	}
+
+	var transforms []network.InputTransform
+	for c := 0; c < width; c++ {
+		xs := make([]float64, len(d))
+		for i, td := range d {
+			xs[i] = td.Data[c]
+		}
+
+		t := network.InputTransform{Source: c}
+		var categories []float64
+		for si, s := range p.steps {
+			if !contains(s.columns, c) {
+				continue
+			}
+			if categories != nil {
+				return fmt.Errorf("step %v (%v) of column %v follows a one-hot step, which must be last", si, s.kind, c)
+			}
+
+			ts := network.TransformStep{Kind: s.kind}
+			switch s.kind {
+			case network.TransformStandardize:
+				ts.Mean, ts.Std = meanStd(xs)
+				if ts.Std == 0 {
+					ts.Std = 1
+				}
+			case network.TransformMinMax:
+				ts.Min, ts.Max = xs[0], xs[0]
+				for _, x := range xs {
+					ts.Min, ts.Max = math.Min(ts.Min, x), math.Max(ts.Max, x)
+				}
+			case network.TransformOneHot:
+				categories = unique(xs)
+				continue
+			case network.TransformLog:
+				ts.Offset = s.offset
+			case network.TransformClip:
+				ts.Min, ts.Max = s.min, s.max
+			}
+
+			t.Steps = append(t.Steps, ts)
+			for i := range xs {
+				x, err := ts.Apply(xs[i])
+				if err != nil {
+					return fmt.Errorf("step %v (%v) of column %v on datum %v: %w", si, s.kind, c, i, err)
+				}
+				xs[i] = x
+			}
+		}
+
+		if categories == nil {
+			transforms = append(transforms, t)
+			continue
+		}
+		for _, category := range categories {
+			ct := network.InputTransform{Source: c, Steps: append([]network.TransformStep(nil), t.Steps...)}
+			ct.Steps = append(ct.Steps, network.TransformStep{Kind: network.TransformOneHot, Category: category})
+			transforms = append(transforms, ct)
+		}
+	}
+
+	p.transforms = transforms
+	return nil
+}
+
+// MustFit calls Fit but panics if an error is encountered.
+func (p *Pipeline) MustFit(d trainer.Data) {
+	err := p.Fit(d)
+	if err != nil {
+		panic(errors.Wrap(err, "must fit"))
+	}
+}
+
+// Transforms returns the input transforms of the fitted p, one per input of the
+// network it preprocesses for, or nil if p hasn't been fit.
+func (p *Pipeline) Transforms() []network.InputTransform {
+	return p.transforms
+}
+
+// Size returns the number of inputs the fitted p produces from each datum,
+// which is the size the input layer of a network trained on them must be.
+func (p *Pipeline) Size() int {
+	return len(p.transforms)
+}
+
+// Transform returns a copy of d with its Data preprocessed by the fitted p, to
+// be trained on. Truths are shared with d rather than copied.
+func (p *Pipeline) Transform(d trainer.Data) (trainer.Data, error) {
+	if p.transforms == nil {
+		return nil, errors.New("pipeline must be fit before transforming")
+	}
+
+	td := make(trainer.Data, len(d))
+	for i := range d {
+		td[i].Truth = d[i].Truth
+		td[i].Data = make([]float64, len(p.transforms))
+		for ti, t := range p.transforms {
+			x, err := t.Apply(d[i].Data)
+			if err != nil {
+				return nil, fmt.Errorf("input %v of datum %v: %w", ti, i, err)
+			}
+			td[i].Data[ti] = x
+		}
+	}
+	return td, nil
+}
+
+// MustTransform calls Transform but panics if an error is encountered.
+func (p *Pipeline) MustTransform(d trainer.Data) trainer.Data {
+	td, err := p.Transform(d)
+	if err != nil {
+		panic(errors.Wrap(err, "must transform"))
+	}
+	return td
+}
+
+// Apply stores the fitted p on nw as its input transforms, so that predictions
+// made by nw preprocess their input, and translators persist p along with nw.
+func (p *Pipeline) Apply(nw network.Network) error {
+	if p.transforms == nil {
+		return errors.New("pipeline must be fit before applying")
+	}
+	return nw.SetInputTransforms(p.transforms)
+}
+
+// MustApply calls Apply but panics if an error is encountered.
+func (p *Pipeline) MustApply(nw network.Network) {
+	err := p.Apply(nw)
+	if err != nil {
+		panic(errors.Wrap(err, "must apply"))
+	}
+}
+
+func contains(is []int, i int) bool {
+	for _, v := range is {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+// meanStd returns the mean and population standard deviation of xs.
+func meanStd(xs []float64) (float64, float64) {
+	mean := 0.0
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+
+	variance := 0.0
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	variance /= float64(len(xs))
+
+	return mean, math.Sqrt(variance)
+}
+
+// unique returns the distinct values of xs in ascending order.
+func unique(xs []float64) []float64 {
+	seen := make(map[float64]bool, len(xs))
+	var us []float64
+	for _, x := range xs {
+		if !seen[x] {
+			seen[x] = true
+			us = append(us, x)
+		}
+	}
+	sort.Float64s(us)
+	return us
+}
@@ -0,0 +1,114 @@
+package preprocess
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+var testData = trainer.Data{
+	{Data: []float64{1, 10, 0, 2}, Truth: []float64{0}},
+	{Data: []float64{2, 20, 1, 0}, Truth: []float64{1}},
+	{Data: []float64{3, 60, 2, 6}, Truth: []float64{0}},
+	{Data: []float64{6, 30, 1, 4}, Truth: []float64{1}},
+}
+
+func Test_Pipeline(t *testing.T) {
+	p := New(
+		Standard(0),
+		Clip(0, 40, 1),
+		MinMax(1),
+		OneHot(2),
+	)
+	p.MustFit(testData)
+
+	if p.Size() != 6 {
+		t.Fatalf("expected 6 inputs, got %v", p.Size())
+	}
+
+	td := p.MustTransform(testData)
+
+	// Column 0 has a mean of 3 and a standard deviation of sqrt(3.5).
+	std := math.Sqrt(3.5)
+	expected := [][]float64{
+		{-2 / std, 0, 1, 0, 0, 2},
+		{-1 / std, 1.0 / 3, 0, 1, 0, 0},
+		{0, 1, 0, 0, 1, 6},
+		{3 / std, 2.0 / 3, 0, 1, 0, 4},
+	}
+	for i := range td {
+		for j := range expected[i] {
+			if math.Abs(td[i].Data[j]-expected[i][j]) > 1e-12 {
+				t.Fatalf("datum %v: expected %v, got %v", i, expected[i], td[i].Data)
+			}
+		}
+		if !reflect.DeepEqual(td[i].Truth, testData[i].Truth) {
+			t.Fatalf("datum %v: truth was changed", i)
+		}
+	}
+}
+
+func Test_Pipeline_Apply(t *testing.T) {
+	p := New(Log(1, 3), Standard(3), OneHot(2))
+	p.MustFit(testData)
+	td := p.MustTransform(testData)
+
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{p.Size(), 3, 1},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameSigmoid,
+	})
+	p.MustApply(nw)
+
+	translated := network.NewProtoTranslator().MustDeserialize(network.NewProtoTranslator().MustSerialize(nw))
+
+	for i := range testData {
+		raw := translated.MustPredictVector(testData[i].Data)
+
+		if err := nw.SetInputTransforms(nil); err != nil {
+			t.Fatal(err)
+		}
+		expected := nw.MustPredictVector(td[i].Data)
+		p.MustApply(nw)
+
+		if !reflect.DeepEqual(raw, expected) {
+			t.Fatalf("datum %v: expected prediction from raw input %v, got %v", i, expected, raw)
+		}
+	}
+}
+
+func Test_Pipeline_UnseenCategory(t *testing.T) {
+	p := New(OneHot(2))
+	p.MustFit(testData)
+
+	td := p.MustTransform(trainer.Data{{Data: []float64{0, 0, 7, 0}}})
+
+	if expected := []float64{0, 0, 0, 0, 0, 0}; !reflect.DeepEqual(td[0].Data, expected) {
+		t.Fatalf("expected %v, got %v", expected, td[0].Data)
+	}
+}
+
+func Test_Pipeline_Errors(t *testing.T) {
+	tests := map[string]*Pipeline{
+		"column out of range": New(Standard(4)),
+		"step after one-hot":  New(OneHot(0), Standard(0)),
+		"log of non-positive": New(Log(0, 2)),
+		"clip min above max":  New(Clip(1, 0, 0)),
+	}
+	for name, p := range tests {
+		if err := p.Fit(testData); err == nil {
+			t.Fatalf("%v: expected error", name)
+		}
+	}
+
+	if err := New().Fit(trainer.Data{}); err == nil {
+		t.Fatalf("expected error fitting on empty data")
+	}
+	if _, err := New().Transform(testData); err == nil {
+		t.Fatalf("expected error transforming before fitting")
+	}
+}
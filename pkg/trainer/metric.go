@@ -0,0 +1,44 @@
+package trainer
+
+import (
+	"fmt"
+
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// Metric evaluates a Network on Data, such as its accuracy. Metrics are tracked
+// on a Trainer's Validation data while training. Implementations of common
+// metrics can be found in the metrics package.
+type Metric interface {
+	// Name identifies the metric in logs and MetricRecords.
+	Name() string
+	// Evaluate returns the value of the metric for nw on d.
+	Evaluate(nw network.Network, d Data) (float64, error)
+}
+
+// MetricRecord holds the value of every Metric of a Trainer after a training
+// iteration.
+type MetricRecord struct {
+	Iteration int
+	Values    map[string]float64
+}
+
+// validate evaluates every Metric of t on its Validation data, appending the
+// results to its History and logging them.
+func (t *Trainer) validate(nw network.Network, ti int) error {
+	r := MetricRecord{Iteration: ti, Values: make(map[string]float64, len(t.Metrics))}
+
+	_, _ = fmt.Fprintf(t.Log, "\nValidation at iteration %v:", ti)
+	for _, m := range t.Metrics {
+		v, err := m.Evaluate(nw, t.Validation)
+		if err != nil {
+			return fmt.Errorf("evaluating metric %v at iteration %v: %w", m.Name(), ti, err)
+		}
+		r.Values[m.Name()] = v
+		_, _ = fmt.Fprintf(t.Log, " %v=%.6f", m.Name(), v)
+	}
+	_, _ = fmt.Fprintln(t.Log)
+
+	t.History = append(t.History, r)
+	return nil
+}
@@ -0,0 +1,54 @@
+package trainer
+
+import (
+	"fmt"
+
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+// NamedDatum is a Datum whose Data is keyed by the input labels of the network
+// it is for, rather than ordered like its input layer.
+type NamedDatum struct {
+	Data  map[string]float64
+	Truth []float64
+}
+
+type NamedData []NamedDatum
+
+// ToData converts nd into Data which can be trained on nw. The features of
+// each datum are ordered via nw.NamedInput, and then transformed by any input
+// transforms of nw, so that they can be fed directly into nw's input layer.
+//
+// If any datum is missing a feature, or has a feature nw doesn't, an error
+// wrapping a *network.FeatureError is returned.
+func (nd NamedData) ToData(nw network.Network) (Data, error) {
+	d := make(Data, len(nd))
+	for i, ndm := range nd {
+		raw, err := nw.NamedInput(ndm.Data)
+		if err != nil {
+			return nil, fmt.Errorf("datum %v: %w", i, err)
+		}
+		input, err := nw.TransformInput(raw)
+		if err != nil {
+			return nil, fmt.Errorf("datum %v: %w", i, err)
+		}
+		d[i] = Datum{Data: input, Truth: ndm.Truth}
+	}
+	return d, nil
+}
+
+// TrainNamed trains nw on nd, which is first converted via NamedData.ToData,
+// in place of t's Data. t's Data is restored once training ends.
+func (t *Trainer) TrainNamed(nw network.Network, nd NamedData) error {
+	d, err := nd.ToData(nw)
+	if err != nil {
+		return err
+	}
+
+	data := t.Data
+	t.Data = d
+	defer func() {
+		t.Data = data
+	}()
+	return t.Train(nw)
+}
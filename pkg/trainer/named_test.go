@@ -0,0 +1,72 @@
+package trainer
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+func Test_NamedData_ToData(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{2, 1},
+		InputLabels:            []string{"x", "y"},
+		OutputLabels:           []string{"z"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.MustSetInputTransforms([]network.InputTransform{
+		{Source: 0, Steps: []network.TransformStep{{Kind: network.TransformStandardize, Mean: 1, Std: 2}}},
+		{Source: 1},
+	})
+	nd := NamedData{
+		{Data: map[string]float64{"y": 2, "x": 5}, Truth: []float64{1}},
+	}
+
+	d, err := nd.ToData(nw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Data{{Data: []float64{2, 2}, Truth: []float64{1}}}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got %v", expected, d)
+	}
+
+	_, err = NamedData{{Data: map[string]float64{"x": 1}}}.ToData(nw)
+	var fe *network.FeatureError
+	if !errors.As(err, &fe) || !reflect.DeepEqual(fe.Missing, []string{"y"}) {
+		t.Fatalf("expected missing feature y, got %v", err)
+	}
+}
+
+func Test_TrainNamed(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{2, 1},
+		InputLabels:            []string{"x", "y"},
+		OutputLabels:           []string{"z"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.SetConnectionWeightsTo(0)
+
+	nd := NamedData{
+		{Data: map[string]float64{"y": 0, "x": 1}, Truth: []float64{1}},
+		{Data: map[string]float64{"x": 0, "y": 1}, Truth: []float64{-1}},
+	}
+	tr := New(Configuration{LearningRate: 0.1, MiniBatchSize: 2, MaxIterations: 500}, nil, io.Discard)
+
+	if err := tr.TrainNamed(nw, nd); err != nil {
+		t.Fatal(err)
+	}
+
+	_, v := nw.MustPredictNamed(map[string]float64{"x": 1, "y": 0})
+	if v < 0.9 {
+		t.Fatalf("expected trained network to predict close to 1 for x, got %v", v)
+	}
+	if tr.Data != nil {
+		t.Fatalf("expected the trainer's data to be left unchanged")
+	}
+}
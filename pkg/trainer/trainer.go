@@ -19,6 +19,22 @@ type Configuration struct {
 	MinLossCutoff     float64
 	MaxIterations     int
 	Timeout           time.Duration
+	// ClipValue, when greater than zero, clamps every accumulated mini batch
+	// gradient to the range [-ClipValue, ClipValue] before it is applied.
+	ClipValue float64
+	// ClipNorm, when greater than zero, rescales the accumulated mini batch
+	// gradients so that their global L2 norm does not exceed ClipNorm before
+	// they are applied.
+	ClipNorm float64
+	// AccumulationSteps is the number of mini batches whose gradients are
+	// accumulated before the network's weights are adjusted, allowing an
+	// effective batch size larger than MiniBatchSize. Values less than 1 are
+	// treated as 1.
+	AccumulationSteps int
+	// ValidationInterval, when greater than zero, evaluates every one of a
+	// Trainer's Metrics on its Validation data after every ValidationInterval
+	// training iterations.
+	ValidationInterval int
 }
 
 type Datum struct {
@@ -32,6 +48,14 @@ type Trainer struct {
 	Configuration
 	Data
 	Log io.Writer
+
+	// Validation is the data Metrics are evaluated on. It should be held out
+	// from Data so that the Metrics reflect how well training generalizes.
+	Validation Data
+	// Metrics are evaluated on Validation every ValidationInterval iterations.
+	Metrics []Metric
+	// History holds a MetricRecord for every time Metrics were evaluated.
+	History []MetricRecord
 }
 
 // TODO(justin): Validate configuration?
@@ -73,54 +97,78 @@ var ErrTimedOut = errors.New("training process timed out")
 func (t *Trainer) Train(nw network.Network) error {
 	// TODO(justin): Make use of the timeout in the training configuration.
 
-	exit := make(chan error)
-
+	// NOTE: timeout stays nil, and so never fires, without a timeout in the
+	// training configuration.
+	var timeout <-chan time.Time
 	if t.Configuration.Timeout > 0 {
-		go func() {
-			time.Sleep(t.Configuration.Timeout)
-			exit <- ErrTimedOut
-		}()
+		timer := time.NewTimer(t.Configuration.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
 	}
 
 	_, _ = fmt.Fprintln(t.Log, "Starting training process...")
 
 	totalLoss, averageLoss, minMiniBatchLoss, maxMiniBatchLoss := 0.0, 0.0, float64(math.MaxInt32), float64(-math.MaxInt32)
 
+	accumulationSteps := t.Configuration.AccumulationSteps
+	if accumulationSteps < 1 {
+		accumulationSteps = 1
+	}
+
 	ti := 0
 	for { // For every desired training iteration...
-		miniBatch, err := t.Data.MiniBatch(t.Configuration.MiniBatchSize)
-		if err != nil {
-			return err
-		}
-
 		totalMiniBatchLoss := 0.0
 
+		// Nudges are accumulated across every accumulation step and only
+		// cleared once they have been applied, so that the effective batch size
+		// is MiniBatchSize * AccumulationSteps.
 		nw.ResetFromBatch()
-		for _, td := range miniBatch {
-			nw.ResetFromPass()
-
-			err := nw.ForwardPass(td.Data)
+		for ai := 0; ai < accumulationSteps; ai++ {
+			miniBatch, err := t.Data.MiniBatch(t.Configuration.MiniBatchSize)
 			if err != nil {
 				return err
 			}
 
-			loss, err := nw.CalculateLoss(td.Truth)
-			if err != nil {
-				return err
-			}
-
-			totalMiniBatchLoss += loss
-
-			err = nw.BackwardPass(td.Truth)
-			if err != nil {
-				return err
+			for _, td := range miniBatch {
+				nw.ResetFromPass()
+
+				err := nw.ForwardPass(td.Data)
+				if err != nil {
+					return err
+				}
+
+				loss, err := nw.CalculateLoss(td.Truth)
+				if err != nil {
+					return err
+				}
+
+				// NOTE: Only non-finite outputs are certain to reach the loss, as
+				// activation functions can map non-finite values elsewhere to
+				// finite ones (relu(-Inf) is 0, and sigmoid and tanh saturate).
+				// Those are left to the check of the gradients below, and the
+				// whole network is only scanned to locate the cause once the
+				// loss isn't finite.
+				if math.IsNaN(loss) || math.IsInf(loss, 0) {
+					err := nw.CheckFinite()
+					if err == nil {
+						err = fmt.Errorf("non-finite loss (%v)", loss)
+					}
+					return fmt.Errorf("training diverged during forward pass of iteration %v: %w", ti, err)
+				}
+
+				totalMiniBatchLoss += loss
+
+				err = nw.BackwardPass(td.Truth)
+				if err != nil {
+					return err
+				}
+
+				nw.RecordNudges()
 			}
-
-			nw.RecordNudges()
 		}
 
 		// Get the average loss across the whole mini batch.
-		miniBatchLoss := totalMiniBatchLoss / float64(t.Configuration.MiniBatchSize)
+		miniBatchLoss := totalMiniBatchLoss / float64(t.Configuration.MiniBatchSize*accumulationSteps)
 		_, _ = fmt.Fprintf(t.Log, "%3f ", miniBatchLoss)
 
 		totalLoss += miniBatchLoss
@@ -136,11 +184,28 @@ func (t *Trainer) Train(nw network.Network) error {
 			_, _ = fmt.Fprintf(t.Log, " | %5f %5f %5f - %v\n", averageLoss, minMiniBatchLoss, maxMiniBatchLoss, ti)
 		}
 
-		nw.AdjustWeights(t.Configuration.LearningRate)
+		g := nw.Gradients()
+		err := g.CheckFinite()
+		if err != nil {
+			return fmt.Errorf("training diverged during backward pass of iteration %v: %w", ti, err)
+		}
+		g.ClipValue(t.Configuration.ClipValue)
+		g.ClipNorm(t.Configuration.ClipNorm)
 
-		select {
-		case err := <-exit:
+		err = nw.ApplyGradients(g, t.Configuration.LearningRate)
+		if err != nil {
 			return err
+		}
+
+		if t.Configuration.ValidationInterval > 0 && len(t.Metrics) > 0 && (ti+1)%t.Configuration.ValidationInterval == 0 {
+			if err := t.validate(nw, ti); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-timeout:
+			return ErrTimedOut
 		default:
 		}
 
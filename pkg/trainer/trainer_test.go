@@ -0,0 +1,60 @@
+package trainer
+
+import (
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+)
+
+func Test_Train_AbortsOnDivergence(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{1, 2, 1},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.SetConnectionWeightsTo(1)
+	nw[1][1].MustSetConnectionWeights([]float64{math.MaxFloat64})
+
+	td := Data{{Data: []float64{10}, Truth: []float64{0}}}
+	tr := New(Configuration{LearningRate: 0.1, MiniBatchSize: 1, MaxIterations: 10}, td, io.Discard)
+
+	err := tr.Train(nw)
+
+	var nfe *network.NonFiniteError
+	if !errors.As(err, &nfe) {
+		t.Fatalf("expected training to abort with a non-finite error, got %v", err)
+	}
+	if nfe.Layer != 1 || nfe.Neuron != 1 {
+		t.Fatalf("expected blowup in layer 1 neuron 1, got %v", nfe)
+	}
+}
+
+func Test_Train_ClipsGradients(t *testing.T) {
+	nw := network.MustFrom(network.Spec{
+		NeuronMap:              []int{1, 1},
+		OutputLabels:           []string{"y"},
+		ActivationFunctionName: activationfunction.NameLinear,
+	})
+	nw.SetNeuronBiasesTo(0)
+	nw.SetConnectionWeightsTo(0)
+
+	// Without clipping the first step alone would move the weight by 0.1 * 2 *
+	// 100 * 100 = 2000. Clipped to a global norm of 1, each of the two steps
+	// taken can move the weight and bias by at most 0.1 combined.
+	td := Data{{Data: []float64{100}, Truth: []float64{100}}}
+	tr := New(Configuration{LearningRate: 0.1, MiniBatchSize: 1, MaxIterations: 0, ClipNorm: 1}, td, io.Discard)
+
+	if err := tr.Train(nw); err != nil {
+		t.Fatal(err)
+	}
+
+	_, v := nw.MustPredict([]float64{1})
+	if v <= 0 || v > 0.3 {
+		t.Fatalf("expected clipped training to move the output by at most 0.3, got %v", v)
+	}
+}
@@ -0,0 +1,162 @@
+package tuning
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Space declares the hyperparameters searched over. Every dimension lists the
+// values it may take, and a dimension without any values is fixed to the value
+// of Base or Configuration instead.
+type Space struct {
+	// Base is the Spec every trial's network is created from. Its InputLabels
+	// and OutputLabels determine the sizes of the input and output layers, so
+	// its NeuronMap is ignored.
+	Base network.Spec
+	// Configuration is the training configuration of every trial. Its
+	// MaxIterations is the most iterations any trial is trained for.
+	Configuration trainer.Configuration
+
+	// HiddenLayers lists the sizes of the hidden layers of each candidate
+	// architecture, such as {{16}, {32}, {32, 16}}. An empty candidate has no
+	// hidden layers.
+	HiddenLayers [][]int
+	// ActivationFunctionNames lists the activation functions to try.
+	ActivationFunctionNames []activationfunction.Name
+	// LearningRates lists the learning rates to try.
+	LearningRates []float64
+	// LearningRateMin and LearningRateMax, when LearningRates is empty and
+	// both are positive, bound learning rates sampled log-uniformly by random
+	// searches. Grid searches, which can't sample, ignore them.
+	LearningRateMin, LearningRateMax float64
+	// MiniBatchSizes lists the mini batch sizes to try.
+	MiniBatchSizes []int
+}
+
+// candidate is a single point of a Space.
+type candidate struct {
+	spec network.Spec
+	c    trainer.Configuration
+}
+
+func (s Space) validate() error {
+	if len(s.Base.InputLabels) == 0 || len(s.Base.OutputLabels) == 0 {
+		return errors.New("base spec must have input and output labels")
+	}
+	if s.Configuration.MaxIterations < 1 {
+		return fmt.Errorf("max iterations (%v) must be at least 1", s.Configuration.MaxIterations)
+	}
+	for i, hl := range s.HiddenLayers {
+		for _, size := range hl {
+			if size < 1 {
+				return fmt.Errorf("hidden layers candidate %v has a layer of size %v", i, size)
+			}
+		}
+	}
+	for _, name := range s.ActivationFunctionNames {
+		if _, err := activationfunction.GetFunction(name); err != nil {
+			return err
+		}
+	}
+	if s.LearningRateMin > s.LearningRateMax {
+		return fmt.Errorf("learning rate min (%v) must not exceed max (%v)", s.LearningRateMin, s.LearningRateMax)
+	}
+	return nil
+}
+
+// sizes returns the number of values of every dimension of s, in the order
+// candidateAt indexes them.
+func (s Space) sizes() []int {
+	return []int{
+		max1(len(s.HiddenLayers)),
+		max1(len(s.ActivationFunctionNames)),
+		max1(len(s.LearningRates)),
+		max1(len(s.MiniBatchSizes)),
+	}
+}
+
+// Size returns the number of candidates of a grid search over s.
+func (s Space) Size() int {
+	size := 1
+	for _, n := range s.sizes() {
+		size *= n
+	}
+	return size
+}
+
+// candidateAt returns the candidate at the given index of every dimension.
+func (s Space) candidateAt(is []int) candidate {
+	var hidden []int
+	if len(s.HiddenLayers) > 0 {
+		hidden = s.HiddenLayers[is[0]]
+	}
+
+	spec := s.Base
+	spec.NeuronMap = append([]int{len(s.Base.InputLabels)}, hidden...)
+	spec.NeuronMap = append(spec.NeuronMap, len(s.Base.OutputLabels))
+	if len(s.ActivationFunctionNames) > 0 {
+		spec.ActivationFunctionName = s.ActivationFunctionNames[is[1]]
+	}
+
+	c := s.Configuration
+	if len(s.LearningRates) > 0 {
+		c.LearningRate = s.LearningRates[is[2]]
+	}
+	if len(s.MiniBatchSizes) > 0 {
+		c.MiniBatchSize = s.MiniBatchSizes[is[3]]
+	}
+
+	return candidate{spec: spec, c: c}
+}
+
+// grid returns every candidate of s.
+func (s Space) grid() []candidate {
+	sizes := s.sizes()
+	cs := make([]candidate, 0, s.Size())
+	is := make([]int, len(sizes))
+	for {
+		cs = append(cs, s.candidateAt(is))
+
+		// Increment is like an odometer, with the last dimension changing
+		// fastest.
+		d := len(is) - 1
+		for ; d >= 0; d-- {
+			is[d]++
+			if is[d] < sizes[d] {
+				break
+			}
+			is[d] = 0
+		}
+		if d < 0 {
+			return cs
+		}
+	}
+}
+
+// sample returns a random candidate of s.
+func (s Space) sample() candidate {
+	sizes := s.sizes()
+	is := make([]int, len(sizes))
+	for d, size := range sizes {
+		is[d] = rand.Intn(size)
+	}
+	cand := s.candidateAt(is)
+	if len(s.LearningRates) == 0 && s.LearningRateMin > 0 && s.LearningRateMax > 0 {
+		lo, hi := math.Log(s.LearningRateMin), math.Log(s.LearningRateMax)
+		cand.c.LearningRate = math.Exp(lo + rand.Float64()*(hi-lo))
+	}
+	return cand
+}
+
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,446 @@
+// Package tuning searches for the network architecture and training
+// configuration which perform best on validation data, via grid search, random
+// search, successive halving and Hyperband.
+package tuning
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// Options configures how the trials of a search are evaluated.
+type Options struct {
+	// Train is the data every trial's network is trained on.
+	Train trainer.Data
+	// Validation is the data Objective is evaluated on once a trial's network
+	// is trained.
+	Validation trainer.Data
+	// Objective scores every trial; see the metrics package.
+	Objective trainer.Metric
+	// Maximize is true if higher values of Objective are better, such as for
+	// accuracy, and false if lower values are, such as for log-loss.
+	Maximize bool
+	// Workers is the maximum number of trials trained at once. Values less
+	// than 1 are treated as 1.
+	Workers int
+	// KeepNetwork retains the network trained by the best trial in the Result.
+	KeepNetwork bool
+	// Log receives a line describing every trial once it is evaluated.
+	// Providing nil discards them. The training logs of trials are always
+	// discarded.
+	Log io.Writer
+}
+
+// Trial is the outcome of training and evaluating a network with a single
+// candidate of a Space.
+type Trial struct {
+	// Candidate identifies the hyperparameters of the trial. Successive
+	// halving and Hyperband train the same candidate over several trials,
+	// each for more iterations than the last.
+	Candidate int
+	Spec      network.Spec
+	// Configuration is the training configuration of the trial. Its
+	// MaxIterations is the total number of iterations trained by the end of
+	// the trial.
+	Configuration trainer.Configuration
+	// Objective is the value of Options.Objective for the trial.
+	Objective float64
+	// Err is the error encountered if the trial failed, such as training
+	// diverging, in which case Objective is meaningless.
+	Err      error
+	Duration time.Duration
+}
+
+func (t Trial) String() string {
+	s := fmt.Sprintf("candidate %v: neuronMap=%v activation=%v learningRate=%v miniBatchSize=%v iterations=%v",
+		t.Candidate, t.Spec.NeuronMap, t.Spec.ActivationFunctionName, t.Configuration.LearningRate, t.Configuration.MiniBatchSize, t.Configuration.MaxIterations)
+	if t.Err != nil {
+		return s + " error=" + t.Err.Error()
+	}
+	return fmt.Sprintf("%v objective=%.6f", s, t.Objective)
+}
+
+// Result is the outcome of a search.
+type Result struct {
+	// Trials holds every trial of the search, in the order they were started.
+	Trials []Trial
+	// Best is the trial with the best Objective among those trained for the
+	// most iterations.
+	Best Trial
+	// Network is the network trained by Best, if Options.KeepNetwork is set.
+	Network network.Network
+}
+
+// Grid evaluates every candidate of s.
+func Grid(s Space, o Options) (Result, error) {
+	if err := o.check(s); err != nil {
+		return Result{}, err
+	}
+
+	var sr search
+	sr.run(o, jobsFor(s.grid(), 0, s.Configuration.MaxIterations))
+	return sr.result(o)
+}
+
+// MustGrid calls Grid but panics if an error is encountered.
+func MustGrid(s Space, o Options) Result {
+	r, err := Grid(s, o)
+	if err != nil {
+		panic(errors.Wrap(err, "must grid"))
+	}
+	return r
+}
+
+// Random evaluates n candidates sampled at random from s. No candidate is
+// sampled twice unless s samples learning rates from a range, and fewer than
+// n are evaluated if s has fewer than n candidates.
+func Random(s Space, n int, o Options) (Result, error) {
+	if err := o.check(s); err != nil {
+		return Result{}, err
+	}
+	if n < 1 {
+		return Result{}, fmt.Errorf("number of trials (%v) must be at least 1", n)
+	}
+
+	var sr search
+	sr.run(o, jobsFor(s.sampleN(n), 0, s.Configuration.MaxIterations))
+	return sr.result(o)
+}
+
+// MustRandom calls Random but panics if an error is encountered.
+func MustRandom(s Space, n int, o Options) Result {
+	r, err := Random(s, n, o)
+	if err != nil {
+		panic(errors.Wrap(err, "must random"))
+	}
+	return r
+}
+
+// SuccessiveHalving samples n candidates from s as Random does, and trains
+// each for minIterations. Only the best 1/eta of them are then trained
+// further, for eta times as many iterations in total, and so on until the
+// survivors have been trained for s.Configuration.MaxIterations. Networks
+// continue training from where they left off rather than starting over.
+func SuccessiveHalving(s Space, n, minIterations, eta int, o Options) (Result, error) {
+	if err := o.check(s); err != nil {
+		return Result{}, err
+	}
+	if n < 1 {
+		return Result{}, fmt.Errorf("number of candidates (%v) must be at least 1", n)
+	}
+	if err := checkHalving(s, minIterations, eta); err != nil {
+		return Result{}, err
+	}
+
+	var sr search
+	sr.halve(o, s.sampleN(n), minIterations, s.Configuration.MaxIterations, eta)
+	return sr.result(o)
+}
+
+// MustSuccessiveHalving calls SuccessiveHalving but panics if an error is
+// encountered.
+func MustSuccessiveHalving(s Space, n, minIterations, eta int, o Options) Result {
+	r, err := SuccessiveHalving(s, n, minIterations, eta, o)
+	if err != nil {
+		panic(errors.Wrap(err, "must successive halving"))
+	}
+	return r
+}
+
+// Hyperband runs several brackets of SuccessiveHalving, from many candidates
+// starting at minIterations to a few candidates trained for
+// s.Configuration.MaxIterations from the start, hedging against candidates
+// which only perform well after long training being eliminated early.
+func Hyperband(s Space, minIterations, eta int, o Options) (Result, error) {
+	if err := o.check(s); err != nil {
+		return Result{}, err
+	}
+	if err := checkHalving(s, minIterations, eta); err != nil {
+		return Result{}, err
+	}
+
+	maxIterations := s.Configuration.MaxIterations
+	// NOTE: The epsilon guards against log ratios which are whole numbers
+	// being computed as slightly less than them.
+	brackets := int(math.Log(float64(maxIterations)/float64(minIterations))/math.Log(float64(eta))+1e-9) + 1
+
+	var sr search
+	for b := brackets - 1; b >= 0; b-- {
+		scale := math.Pow(float64(eta), float64(b))
+		n := int(math.Ceil(float64(brackets) / float64(b+1) * scale))
+		r := int(float64(maxIterations) / scale)
+		if r < 1 {
+			r = 1
+		}
+		sr.halve(o, s.sampleN(n), r, maxIterations, eta)
+	}
+	return sr.result(o)
+}
+
+// MustHyperband calls Hyperband but panics if an error is encountered.
+func MustHyperband(s Space, minIterations, eta int, o Options) Result {
+	r, err := Hyperband(s, minIterations, eta, o)
+	if err != nil {
+		panic(errors.Wrap(err, "must hyperband"))
+	}
+	return r
+}
+
+func (o Options) check(s Space) error {
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("invalid space: %w", err)
+	}
+	if o.Objective == nil {
+		return errors.New("must provide an objective")
+	}
+	if len(o.Train) == 0 || len(o.Validation) == 0 {
+		return errors.New("must provide training and validation data")
+	}
+	return nil
+}
+
+func checkHalving(s Space, minIterations, eta int) error {
+	if eta < 2 {
+		return fmt.Errorf("eta (%v) must be at least 2", eta)
+	}
+	if minIterations < 1 || minIterations > s.Configuration.MaxIterations {
+		return fmt.Errorf("min iterations (%v) must be in [1, %v]", minIterations, s.Configuration.MaxIterations)
+	}
+	return nil
+}
+
+// sampleN returns n random candidates of s, without repeats unless s samples
+// learning rates from a range.
+func (s Space) sampleN(n int) []candidate {
+	if len(s.LearningRates) == 0 && s.LearningRateMin > 0 && s.LearningRateMax > 0 {
+		cs := make([]candidate, n)
+		for i := range cs {
+			cs[i] = s.sample()
+		}
+		return cs
+	}
+
+	grid := s.grid()
+	rand.Shuffle(len(grid), func(i, j int) {
+		grid[i], grid[j] = grid[j], grid[i]
+	})
+	if n < len(grid) {
+		grid = grid[:n]
+	}
+	return grid
+}
+
+// job trains a network of a candidate for some iterations, then evaluates it.
+type job struct {
+	id   int
+	cand candidate
+	// nw is the network to continue training, or nil to create a new one.
+	nw         network.Network
+	iterations int
+	total      int
+}
+
+type outcome struct {
+	trial Trial
+	nw    network.Network
+}
+
+func jobsFor(cs []candidate, firstID, iterations int) []job {
+	jobs := make([]job, len(cs))
+	for i, cand := range cs {
+		jobs[i] = job{id: firstID + i, cand: cand, iterations: iterations, total: iterations}
+	}
+	return jobs
+}
+
+// search accumulates the outcomes of the jobs of a search.
+type search struct {
+	outcomes   []outcome
+	candidates int
+}
+
+// run evaluates jobs, up to o.Workers at once, returning their outcomes in the
+// order of jobs.
+func (sr *search) run(o Options, jobs []job) []outcome {
+	workers := o.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	log := o.Log
+	if log == nil {
+		log = io.Discard
+	}
+
+	outcomes := make([]outcome, len(jobs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := range jobs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			outcomes[i] = jobs[i].run(o)
+
+			mu.Lock()
+			_, _ = fmt.Fprintln(log, outcomes[i].trial)
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, j := range jobs {
+		if j.id >= sr.candidates {
+			sr.candidates = j.id + 1
+		}
+	}
+	sr.outcomes = append(sr.outcomes, outcomes...)
+	return outcomes
+}
+
+func (j job) run(o Options) outcome {
+	start := time.Now()
+	c := j.cand.c
+	c.MaxIterations = j.total
+	oc := outcome{trial: Trial{Candidate: j.id, Spec: j.cand.spec, Configuration: c}, nw: j.nw}
+	defer func() {
+		oc.trial.Duration = time.Since(start)
+	}()
+
+	if oc.nw == nil {
+		nw, err := network.From(j.cand.spec)
+		if err != nil {
+			oc.trial.Err = fmt.Errorf("creating network: %w", err)
+			return oc
+		}
+		oc.nw = nw
+	}
+
+	// NOTE: Training shuffles its data in place, so every job trains on its
+	// own copy.
+	tc := j.cand.c
+	tc.MaxIterations = j.iterations
+	// NOTE: Reaching the timeout of the candidate's configuration ends its
+	// training like any other cutoff, so the trial is still evaluated.
+	t := trainer.New(tc, append(trainer.Data(nil), o.Train...), io.Discard)
+	if err := t.Train(oc.nw); err != nil && !errors.Is(err, trainer.ErrTimedOut) {
+		oc.trial.Err = fmt.Errorf("training: %w", err)
+		return oc
+	}
+
+	v, err := o.Objective.Evaluate(oc.nw, o.Validation)
+	if err != nil {
+		oc.trial.Err = fmt.Errorf("evaluating objective: %w", err)
+		return oc
+	}
+	oc.trial.Objective = v
+	return oc
+}
+
+// halve runs a single bracket of successive halving over cs.
+func (sr *search) halve(o Options, cs []candidate, minIterations, maxIterations, eta int) {
+	jobs := jobsFor(cs, sr.candidates, minIterations)
+	for {
+		outcomes := sr.run(o, jobs)
+		total := jobs[0].total
+		if total >= maxIterations {
+			return
+		}
+
+		var survivors []outcome
+		for _, oc := range outcomes {
+			if oc.trial.Err == nil {
+				survivors = append(survivors, oc)
+			}
+		}
+		if len(survivors) == 0 {
+			return
+		}
+		sort.SliceStable(survivors, func(a, b int) bool {
+			return better(survivors[a].trial.Objective, survivors[b].trial.Objective, o.Maximize)
+		})
+		keep := len(survivors) / eta
+		if keep < 1 {
+			keep = 1
+		}
+		survivors = survivors[:keep]
+
+		next := total * eta
+		if next > maxIterations {
+			next = maxIterations
+		}
+		jobs = make([]job, len(survivors))
+		for i, oc := range survivors {
+			jobs[i] = job{
+				id:         oc.trial.Candidate,
+				cand:       candidate{spec: oc.trial.Spec, c: oc.trial.Configuration},
+				nw:         oc.nw,
+				iterations: next - total,
+				total:      next,
+			}
+		}
+	}
+}
+
+// result picks the best trial of sr. Only trials trained for the most
+// iterations are considered, since successive halving evaluates candidates
+// after fewer iterations too.
+func (sr *search) result(o Options) (Result, error) {
+	r := Result{Trials: make([]Trial, len(sr.outcomes))}
+	best := -1
+	for i, oc := range sr.outcomes {
+		r.Trials[i] = oc.trial
+		if oc.trial.Err != nil {
+			continue
+		}
+		if best == -1 {
+			best = i
+			continue
+		}
+		bt := sr.outcomes[best].trial
+		switch {
+		case oc.trial.Configuration.MaxIterations > bt.Configuration.MaxIterations:
+			best = i
+		case oc.trial.Configuration.MaxIterations == bt.Configuration.MaxIterations && better(oc.trial.Objective, bt.Objective, o.Maximize):
+			best = i
+		}
+	}
+	if best == -1 {
+		return r, fmt.Errorf("all %v trials failed, the first with: %w", len(r.Trials), r.Trials[0].Err)
+	}
+
+	r.Best = r.Trials[best]
+	if o.KeepNetwork {
+		r.Network = sr.outcomes[best].nw
+	}
+	return r, nil
+}
+
+// better reports whether objective a is better than b. NaN is worse than
+// everything.
+func better(a, b float64, maximize bool) bool {
+	if math.IsNaN(b) {
+		return !math.IsNaN(a)
+	}
+	if math.IsNaN(a) {
+		return false
+	}
+	if maximize {
+		return a > b
+	}
+	return a < b
+}
@@ -0,0 +1,264 @@
+package tuning
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	activationfunction "github.com/Insulince/jnet/pkg/activation-function"
+	"github.com/Insulince/jnet/pkg/network"
+	"github.com/Insulince/jnet/pkg/trainer"
+)
+
+// width is an objective which scores a network by the size of its first hidden
+// layer, so that the best trial is known in advance.
+type width struct{}
+
+func (width) Name() string {
+	return "width"
+}
+
+func (width) Evaluate(nw network.Network, _ trainer.Data) (float64, error) {
+	return float64(len(nw[1])), nil
+}
+
+// halves is the data trained and validated on, whose truth is half its input.
+var halves = trainer.Data{
+	{Data: []float64{0}, Truth: []float64{0}},
+	{Data: []float64{0.5}, Truth: []float64{0.25}},
+	{Data: []float64{1}, Truth: []float64{0.5}},
+}
+
+func Test_Space_grid(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:  trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:   [][]int{{2}, {4, 3}, {8}},
+		LearningRates:  []float64{0.01, 0.001},
+		MiniBatchSizes: []int{1, 2},
+	}
+	cs := s.grid()
+	if len(cs) != 12 || s.Size() != 12 {
+		t.Fatalf("expected 12 candidates, got %v (size %v)", len(cs), s.Size())
+	}
+	if !reflect.DeepEqual(cs[0].spec.NeuronMap, []int{1, 2, 1}) || cs[0].c.LearningRate != 0.01 || cs[0].c.MiniBatchSize != 1 {
+		t.Fatalf("unexpected first candidate %+v", cs[0])
+	}
+	if !reflect.DeepEqual(cs[11].spec.NeuronMap, []int{1, 8, 1}) || cs[11].c.LearningRate != 0.001 || cs[11].c.MiniBatchSize != 2 {
+		t.Fatalf("unexpected last candidate %+v", cs[11])
+	}
+	if cs[11].spec.ActivationFunctionName != activationfunction.NameLinear {
+		t.Fatalf("expected the activation function of the base spec, got %v", cs[11].spec.ActivationFunctionName)
+	}
+}
+
+func Test_Space_sample(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:   trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:    [][]int{{2}},
+		LearningRateMin: 0.001,
+		LearningRateMax: 0.1,
+	}
+	for i := 0; i < 100; i++ {
+		lr := s.sample().c.LearningRate
+		if lr < 0.001 || lr > 0.1 {
+			t.Fatalf("sampled learning rate %v out of range", lr)
+		}
+	}
+
+	s.ActivationFunctionNames = []activationfunction.Name{"nope"}
+	if err := s.validate(); err == nil {
+		t.Fatal("expected an error for an unknown activation function")
+	}
+}
+
+func Test_Grid(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:  trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:   [][]int{{2}, {4, 3}, {8}},
+		LearningRates:  []float64{0.01, 0.001},
+		MiniBatchSizes: []int{1, 2},
+	}
+	r, err := Grid(s, Options{Train: halves, Validation: halves, Objective: width{}, Maximize: true, Workers: 4, KeepNetwork: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Trials) != 12 {
+		t.Fatalf("expected 12 trials, got %v", len(r.Trials))
+	}
+	if r.Best.Objective != 8 || r.Best.Spec.NeuronMap[1] != 8 {
+		t.Fatalf("expected the widest network to be best, got %v", r.Best)
+	}
+	if r.Network == nil || len(r.Network[1]) != 8 {
+		t.Fatal("expected the network of the best trial")
+	}
+}
+
+func Test_Grid_TimedOut(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration: trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: math.MaxInt32, Timeout: 10 * time.Millisecond},
+		HiddenLayers:  [][]int{{2}},
+	}
+	r, err := Grid(s, Options{Train: halves, Validation: halves, Objective: width{}, Maximize: true, Workers: 4, KeepNetwork: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Trials) != 1 || r.Trials[0].Err != nil {
+		t.Fatalf("expected a trial evaluated after timing out, got %v", r.Trials)
+	}
+}
+
+func Test_Random(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:  trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:   [][]int{{2}, {4, 3}, {8}},
+		LearningRates:  []float64{0.01, 0.001},
+		MiniBatchSizes: []int{1, 2},
+	}
+	r, err := Random(s, 20, Options{Train: halves, Validation: halves, Objective: width{}, Maximize: true, Workers: 4, KeepNetwork: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Trials) != 12 {
+		t.Fatalf("expected every one of 12 candidates once, got %v trials", len(r.Trials))
+	}
+	seen := make(map[string]bool)
+	for _, tr := range r.Trials {
+		key := strings.SplitN(tr.String(), " ", 3)[2]
+		if seen[key] {
+			t.Fatalf("candidate sampled twice: %v", tr)
+		}
+		seen[key] = true
+	}
+}
+
+func Test_SuccessiveHalving(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:  trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:   [][]int{{2}, {4, 3}, {8}},
+		LearningRates:  []float64{0.01, 0.001},
+		MiniBatchSizes: []int{1, 2},
+	}
+	r, err := SuccessiveHalving(s, 9, 1, 3, Options{Train: halves, Validation: halves, Objective: width{}, Maximize: true, Workers: 4, KeepNetwork: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 9 candidates are trained for 1 iteration, the best 3 for 3, then the
+	// best for 9.
+	counts := make(map[int]int)
+	for _, tr := range r.Trials {
+		counts[tr.Configuration.MaxIterations]++
+	}
+	if !reflect.DeepEqual(counts, map[int]int{1: 9, 3: 3, 9: 1}) {
+		t.Fatalf("expected 9, 3 and 1 trials per rung, got %v", counts)
+	}
+	if r.Best.Configuration.MaxIterations != 9 || r.Best.Objective != 8 {
+		t.Fatalf("expected the widest network to survive every rung, got %v", r.Best)
+	}
+}
+
+func Test_Hyperband(t *testing.T) {
+	var mu sync.Mutex
+	var sb strings.Builder
+	o := Options{Train: halves, Validation: halves, Objective: width{}, Maximize: true, Workers: 4, KeepNetwork: true}
+	o.Log = writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return sb.Write(p)
+	})
+
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:  trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:   [][]int{{2}, {4, 3}, {8}},
+		LearningRates:  []float64{0.01, 0.001},
+		MiniBatchSizes: []int{1, 2},
+	}
+	r, err := Hyperband(s, 1, 3, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The brackets evaluate 9+3+1, 5+1 and 3 trials.
+	if len(r.Trials) != 22 {
+		t.Fatalf("expected 22 trials, got %v", len(r.Trials))
+	}
+	if r.Best.Configuration.MaxIterations != 9 {
+		t.Fatalf("expected the best trial to be fully trained, got %v", r.Best)
+	}
+	if lines := strings.Count(sb.String(), "\n"); lines != 22 {
+		t.Fatalf("expected a log line per trial, got %v", lines)
+	}
+}
+
+func Test_Errors(t *testing.T) {
+	s := Space{
+		Base: network.Spec{
+			InputLabels:            []string{"x"},
+			OutputLabels:           []string{"y"},
+			ActivationFunctionName: activationfunction.NameLinear,
+		},
+		Configuration:  trainer.Configuration{LearningRate: 0.01, MiniBatchSize: 2, MaxIterations: 9},
+		HiddenLayers:   [][]int{{2}, {4, 3}, {8}},
+		LearningRates:  []float64{0.01, 0.001},
+		MiniBatchSizes: []int{10},
+	}
+	o := Options{Train: halves, Validation: halves, Objective: width{}, Maximize: true, Workers: 4, KeepNetwork: true}
+	r, err := Grid(s, o)
+	if err == nil {
+		t.Fatal("expected an error when every trial fails")
+	}
+	if len(r.Trials) != 6 || r.Trials[0].Err == nil {
+		t.Fatalf("expected every failed trial to be recorded, got %v", r.Trials)
+	}
+
+	if _, err := SuccessiveHalving(s, 9, 10, 3, o); err == nil {
+		t.Fatal("expected an error for min iterations exceeding max iterations")
+	}
+	if _, err := Hyperband(s, 1, 1, o); err == nil {
+		t.Fatal("expected an error for an eta of 1")
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}